@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"octane/pkg/assets"
+	"path/filepath"
+
+	"github.com/spf13/viper"
+)
+
+// embedded holds the scripts/python and configs trees compiled into the binary
+var embedded fs.FS
+
+// SetAssets provides the embedded scripts and default configs
+func SetAssets(fsys fs.FS) {
+	embedded = fsys
+}
+
+// scriptDir extracts the embedded Python scripts if needed and returns their directory.
+// The scripts are executed, so they go to a private per-user directory instead of temp_dir.
+func scriptDir() (string, error) {
+	dir, err := assets.Dir()
+	if err != nil {
+		return "", fmt.Errorf("locating the script directory: %v", err)
+	}
+	if err := assets.Extract(embedded, dir); err != nil {
+		return "", fmt.Errorf("extracting scripts: %v", err)
+	}
+	return filepath.Join(dir, "scripts", "python"), nil
+}
+
+// loadDefaultConfig registers every key of the embedded configs/default.yaml as a viper default
+func loadDefaultConfig() error {
+	data, err := fs.ReadFile(embedded, "configs/default.yaml")
+	if err != nil {
+		return err
+	}
+
+	defaults := viper.New()
+	defaults.SetConfigType("yaml")
+	if err := defaults.ReadConfig(bytes.NewReader(data)); err != nil {
+		return err
+	}
+	for _, key := range defaults.AllKeys() {
+		viper.SetDefault(key, defaults.Get(key))
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"fmt"
+	"octane/pkg/benchmark"
+	"octane/pkg/executor"
+	"octane/pkg/monitor"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// benchCmd represents the bench command
+var benchCmd = &cobra.Command{
+	Use:   "bench <name|group>...",
+	Short: "Run benchmarks by name",
+	Long: `Run one or more registered benchmarks by name. A group prefix selects every
+benchmark below it, e.g. "cpu/crypto" or "memory". See "octane list".`,
+	Args:         cobra.MinimumNArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		threads, _ := cmd.Flags().GetInt("threads")
+		duration, _ := cmd.Flags().GetDuration("duration")
+		size, _ := cmd.Flags().GetString("size")
+		dir, _ := cmd.Flags().GetString("dir")
+
+		scripts, err := scriptDir()
+		if err != nil {
+			return err
+		}
+
+		opts := benchmark.Options{
+			Threads:   threads,
+			Duration:  duration,
+			Dir:       dir,
+			ScriptDir: scripts,
+		}
+		if opts.Dir == "" {
+			opts.Dir = filepath.Join(viper.GetString("global.temp_dir"), "storage")
+		}
+		if size != "" {
+			bytes, err := executor.ParseSize(size)
+			if err != nil {
+				return err
+			}
+			opts.Size = bytes
+		}
+
+		for _, pattern := range args {
+			list, err := benchmark.Select(pattern)
+			if err != nil {
+				return err
+			}
+			for _, b := range list {
+				fmt.Printf("▶ %s\n", b.Name())
+				result, err := benchmark.Run(runContext(cmd), b, opts)
+				if err = interrupted(cmd, err); err != nil {
+					return err
+				}
+				displayBenchmarkResult(result)
+			}
+		}
+		return nil
+	},
+}
+
+func init() {
+	benchCmd.Flags().IntP("threads", "t", 0, "Number of threads to use (default is auto)")
+	benchCmd.Flags().DurationP("duration", "d", 0, "Duration of each benchmark (default is the benchmark's own)")
+	benchCmd.Flags().StringP("size", "s", "", "Working set or test file size (e.g., 512MB, 1GB)")
+	benchCmd.Flags().String("dir", "", "Directory for storage benchmarks (default is <temp_dir>/storage)")
+
+	rootCmd.AddCommand(benchCmd)
+}
+
+// displayBenchmarkResult prints the metrics of a single benchmark run
+func displayBenchmarkResult(result *benchmark.Result) {
+	for _, m := range result.Metrics {
+		fmt.Printf("  %-20s %12.2f %s\n", m.Name, m.Value, m.Unit)
+	}
+	fmt.Printf("  completed in %s\n", result.Duration.Round(time.Millisecond))
+	if result.Steal.Steal > 0 || result.Steal.IOWait > 0 {
+		fmt.Printf("  steal %.1f%% (peak %.1f%%), iowait %.1f%%\n", result.Steal.Steal, result.Steal.PeakSteal, result.Steal.IOWait)
+	}
+	if result.Power.Available || result.Power.Reason != "" {
+		fmt.Printf("  power %s\n", monitor.FormatPower(result.Power))
+	}
+}
@@ -10,42 +10,44 @@ import (
 
 // cpuCmd represents the CPU performance test command
 var cpuCmd = &cobra.Command{
-	Use:   "cpu",
-	Short: "Test CPU performance",
-	Long:  `Run various CPU performance tests to evaluate the processing power of the system.`,
-	Run: func(cmd *cobra.Command, args []string) {
+	Use:          "cpu",
+	Short:        "Test CPU performance",
+	Long:         `Run various CPU performance tests to evaluate the processing power of the system.`,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
 		// Parse command line arguments
 		threads, _ := cmd.Flags().GetInt("threads")
 		duration, _ := cmd.Flags().GetString("duration")
 		testType, _ := cmd.Flags().GetString("test")
 
 		// Execute the CPU test
-		results, err := executor.ExecuteCPUTest(threads, duration, testType)
-		if err != nil {
-			fmt.Printf("Error executing CPU test: %v\n", err)
-			return
+		results, err := executor.ExecuteCPUTest(runContext(cmd), threads, duration, testType)
+		if err = interrupted(cmd, err); err != nil {
+			return err
 		}
 
 		// Display results
 		displayResults(results)
+		return nil
 	},
 }
 
 // cpuInfoCmd represents the CPU info command
 var cpuInfoCmd = &cobra.Command{
-	Use:   "info",
-	Short: "Show CPU information",
-	Long:  `Display detailed information about the current CPU platform.`,
-	Run: func(cmd *cobra.Command, args []string) {
+	Use:          "info",
+	Short:        "Show CPU information",
+	Long:         `Display detailed information about the current CPU platform.`,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
 		// Get CPU info
 		cpuInfo, err := executor.GetCPUInfo()
 		if err != nil {
-			fmt.Printf("Error getting CPU info: %v\n", err)
-			return
+			return fmt.Errorf("getting CPU info: %v", err)
 		}
 
 		// Display CPU info
 		displayCPUInfo(cpuInfo)
+		return nil
 	},
 }
 
@@ -53,7 +55,7 @@ func init() {
 	// Add flags for the CPU command
 	cpuCmd.Flags().IntP("threads", "t", 0, "Number of threads to use (default is auto)")
 	cpuCmd.Flags().StringP("duration", "d", "60s", "Duration of the test (e.g., 60s, 2m)")
-	cpuCmd.Flags().StringP("test", "T", "all", "Type of test to run (all|compute|crypto|compress)")
+	cpuCmd.Flags().StringP("test", "T", "all", "Type of test to run (all|compute|crypto|compress or a benchmark name)")
 
 	// Add the cpu command to the root command
 	rootCmd.AddCommand(cpuCmd)
@@ -132,4 +134,17 @@ func displayResults(results *types.CPUResults) {
 		fmt.Printf("  LZ4: %d MB/s\n", results.Tests.MultiCore.Compression.LZ4)
 		fmt.Printf("  Zstd: %d MB/s\n", results.Tests.MultiCore.Compression.Zstd)
 	}
+
+	fmt.Println("\n⚡ Efficiency:")
+	if !results.Efficiency.Available {
+		fmt.Printf("  Unavailable: %s\n", results.Efficiency.Reason)
+		return
+	}
+	if results.Efficiency.SingleCorePointsPerWatt > 0 {
+		fmt.Printf("  Single-Core: %.1f points/W\n", results.Efficiency.SingleCorePointsPerWatt)
+	}
+	if results.Efficiency.MultiCorePointsPerWatt > 0 {
+		fmt.Printf("  Multi-Core: %.1f points/W (%.1f W package)\n",
+			results.Efficiency.MultiCorePointsPerWatt, results.Efficiency.MultiCoreWatts)
+	}
 }
@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"octane/pkg/assets"
+	"octane/pkg/doctor"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// doctorCmd represents the doctor command
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check the environment before running benchmarks",
+	Long: `Check that the interpreters, tools, sysfs files, privileges and storage octane
+depends on are available on this host. Each check reports pass, warn or fail with a
+hint on how to fix it. The command exits with status 1 if any check fails.`,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		asJSON, _ := cmd.Flags().GetBool("json")
+
+		results := runChecks(cmd)
+		if asJSON {
+			data, err := json.MarshalIndent(results, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(data))
+		} else {
+			for _, result := range results {
+				displayCheck(result)
+			}
+		}
+
+		if doctor.Failed(results) {
+			return &exitError{code: 1, err: errors.New("some checks failed")}
+		}
+		return nil
+	},
+}
+
+func init() {
+	doctorCmd.Flags().Bool("json", false, "Print the results as JSON")
+
+	rootCmd.AddCommand(doctorCmd)
+}
+
+// runChecks runs every environment check in display order
+func runChecks(cmd *cobra.Command) []doctor.Result {
+	ctx := cmd.Context()
+
+	// 资源比较需要在解压之前进行，否则总是一致
+	var results []doctor.Result
+	if dir, err := assets.Dir(); err != nil {
+		results = append(results, doctor.Result{Name: "embedded assets", Status: doctor.StatusFail, Message: err.Error()})
+	} else {
+		results = append(results, doctor.CheckAssets(embedded, dir))
+	}
+
+	scripts, err := scriptDir()
+	if err != nil {
+		results = append(results, doctor.Result{Name: "python", Status: doctor.StatusFail, Message: err.Error()})
+	} else {
+		python, interpreter := doctor.CheckPython(ctx)
+		results = append(results, python, doctor.CheckModules(ctx, interpreter, scripts))
+	}
+
+	for _, tool := range doctor.Tools {
+		results = append(results, doctor.CheckTool(ctx, toolsDir(), tool))
+	}
+
+	results = append(results, doctor.CheckSysfs("/")...)
+	results = append(results,
+		doctor.CheckPrivileges("/"),
+		doctor.CheckTempDir(viper.GetString("global.temp_dir")),
+		doctor.CheckDatabase(expandHome(viper.GetString("global.database"))),
+		doctor.CheckUpload(
+			viper.GetBool("upload.enabled"),
+			viper.GetString("upload.server_url"),
+			viper.GetString("upload.api_key"),
+			viper.GetBool("upload.anonymous"),
+		),
+	)
+	return results
+}
+
+// displayCheck prints one check result with its fix hint
+func displayCheck(result doctor.Result) {
+	icon := map[string]string{
+		doctor.StatusPass: "✓",
+		doctor.StatusWarn: "⚠",
+		doctor.StatusFail: "✗",
+	}[result.Status]
+
+	lines := strings.Split(result.Message, "\n")
+	fmt.Printf("%s %-18s %s\n", icon, result.Name, lines[0])
+	for _, line := range lines[1:] {
+		fmt.Printf("  %-18s   %s\n", "", line)
+	}
+	if result.Status != doctor.StatusPass && result.Hint != "" {
+		fmt.Printf("  %-18s → %s\n", "", result.Hint)
+	}
+}
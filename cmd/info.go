@@ -1,27 +1,219 @@
 package cmd
 
-// import (
-// 	"fmt"
-// 	"octane/pkg/executor"
-
-// 	"github.com/spf13/cobra"
-// )
-
-// // infoCmd represents the info command
-// var infoCmd = &cobra.Command{
-// 	Use:   "info",
-// 	Short: "View system information",
-// 	Long:  `Displays detailed information about the system's hardware and software configuration.`,
-// 	Run: func(cmd *cobra.Command, args []string) {
-// 		systemInfo, err := executor.ExecutePythonScript("scripts/python/system_info.py")
-// 		if err != nil {
-// 			fmt.Println("Error retrieving system information:", err)
-// 			return
-// 		}
-// 		fmt.Println(systemInfo)
-// 	},
-// }
-
-// func init() {
-// 	rootCmd.AddCommand(infoCmd)
-// }
+import (
+	"context"
+	"fmt"
+	"octane/pkg/executor"
+	"octane/pkg/gpu"
+	"octane/pkg/inventory"
+	"octane/pkg/octane"
+	"octane/pkg/types"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
+)
+
+// infoCmd represents the info command
+var infoCmd = &cobra.Command{
+	Use:   "info",
+	Short: "View system information",
+	Long:  `Displays detailed information about the system's hardware and software configuration.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		root, _ := cmd.Flags().GetString("root")
+		asYAML, _ := cmd.Flags().GetBool("yaml")
+		virtual, _ := cmd.Flags().GetBool("all-interfaces")
+		linkSpeeds, err := linkSpeedOverrides()
+		if err != nil {
+			return err
+		}
+
+		info := collectSystemInfo(cmd.Context(), root, virtual, linkSpeeds)
+		if asYAML {
+			encoder := yaml.NewEncoder(os.Stdout)
+			encoder.SetIndent(2)
+			return encoder.Encode(info)
+		}
+		displaySystemInfo(info)
+		if recommendations := hardwareRecommendations(info, linkSpeeds); len(recommendations) > 0 {
+			fmt.Println("\n🔧 Recommendations")
+			for _, r := range recommendations {
+				fmt.Printf("  • %s\n", r.Suggestion)
+			}
+		}
+		return nil
+	},
+}
+
+func init() {
+	infoCmd.Flags().Bool("yaml", false, "Print the inventory as YAML")
+	infoCmd.Flags().String("root", "/", "Filesystem root to read /proc, /sys and /etc from")
+	infoCmd.Flags().Bool("all-interfaces", false, "Include virtual network interfaces and bridges")
+
+	rootCmd.AddCommand(infoCmd)
+}
+
+// collectSystemInfo gathers the inventory below root; CPU details are only read from the live system.
+// Interfaces with a link speed override are listed even when virtual, so a named VLAN is not reported missing.
+func collectSystemInfo(ctx context.Context, root string, includeVirtual bool, linkSpeeds map[string]int) *types.SystemInfo {
+	collector := inventory.NewCollector(root)
+	collector.IncludeVirtual = includeVirtual
+	for name := range linkSpeeds {
+		collector.Named = append(collector.Named, name)
+	}
+	info := collector.Collect(ctx)
+	if root == "" || root == "/" {
+		if cpu, err := executor.GetCPUInfo(); err == nil {
+			info.CPU = *cpu
+		}
+		// nvidia-smi or rocm-smi adds driver, clock, power and temperature readings
+		if tool, err := gpu.Find(toolsDir()); err == nil {
+			if snapshot, err := tool.Query(ctx); err == nil {
+				info.GPU = gpu.Apply(info.GPU, snapshot)
+			}
+		}
+	}
+	return info
+}
+
+// hardwareRecommendations checks the negotiated NIC speeds against network.link_speed
+// and the GPU PCIe links against what the cards support. linkSpeeds comes from linkSpeedOverrides.
+func hardwareRecommendations(info *types.SystemInfo, linkSpeeds map[string]int) []types.Recommendation {
+	// viper lowercases config keys, so a name like enP1s0 falls back to its lowercased key
+	// and names that match no interface are kept so they are reported as missing
+	overrides := make(map[string]int)
+	matched := make(map[string]bool)
+	for _, iface := range info.Network {
+		if speed, ok := linkSpeeds[iface.Name]; ok {
+			overrides[iface.Name] = speed
+			matched[iface.Name] = true
+		} else if speed, ok := linkSpeeds[strings.ToLower(iface.Name)]; ok {
+			overrides[iface.Name] = speed
+			matched[strings.ToLower(iface.Name)] = true
+		}
+	}
+	for name, speed := range linkSpeeds {
+		if !matched[name] {
+			overrides[name] = speed
+		}
+	}
+	recommendations := octane.LinkRecommendations(info.Network, viper.GetInt("network.link_speed.expected"), overrides)
+	return append(recommendations, octane.GPULinkRecommendations(info.GPU)...)
+}
+
+// linkSpeedOverrides reads network.link_speed.interfaces as one map. Reading each speed
+// by its own key would break VLAN names like eth0.100, since viper splits keys on '.'.
+func linkSpeedOverrides() (map[string]int, error) {
+	var speeds map[string]int
+	if err := viper.UnmarshalKey("network.link_speed.interfaces", &speeds); err != nil {
+		return nil, fmt.Errorf("invalid network.link_speed.interfaces: %v", err)
+	}
+	return speeds, nil
+}
+
+// displaySystemInfo prints the inventory grouped by component
+func displaySystemInfo(info *types.SystemInfo) {
+	host := info.Host
+	fmt.Println("🖥  Host")
+	fmt.Printf("  %-14s %s\n", "OS:", host.OS)
+	fmt.Printf("  %-14s %s\n", "Kernel:", host.Kernel)
+	fmt.Printf("  %-14s %s\n", "Architecture:", host.Architecture)
+	fmt.Printf("  %-14s %s\n", "Hostname:", host.Hostname)
+	fmt.Printf("  %-14s %s\n", "Uptime:", host.Uptime)
+	fmt.Printf("  %-14s %s\n", "Timezone:", host.Timezone)
+	if host.Hypervisor != "" {
+		fmt.Printf("  %-14s %s\n", "Hypervisor:", host.Hypervisor)
+	}
+	if host.Cloud != "" {
+		fmt.Printf("  %-14s %s\n", "Cloud:", host.Cloud)
+	}
+	if host.Container != "" {
+		fmt.Printf("  %-14s %s\n", "Container:", host.Container)
+	}
+	if host.CPULimit > 0 {
+		fmt.Printf("  %-14s %.1f CPUs (cgroup v%d)\n", "CPU limit:", host.CPULimit, host.CgroupVersion)
+	}
+	if host.MemoryLimit > 0 {
+		fmt.Printf("  %-14s %d MB (cgroup v%d)\n", "Memory limit:", host.MemoryLimit, host.CgroupVersion)
+	}
+
+	if info.Product.Name != "" || info.Board.Product != "" || info.BIOS.Version != "" {
+		fmt.Println("\n🏭 Platform")
+		fmt.Printf("  %-14s %s %s\n", "System:", info.Product.Manufacturer, info.Product.Name)
+		if info.Product.SerialNumber != "" {
+			fmt.Printf("  %-14s %s\n", "Serial:", info.Product.SerialNumber)
+		}
+		fmt.Printf("  %-14s %s %s\n", "Board:", info.Board.Manufacturer, info.Board.Product)
+		fmt.Printf("  %-14s %s %s (%s)\n", "BIOS:", info.BIOS.Vendor, info.BIOS.Version, info.BIOS.ReleaseDate)
+	}
+
+	if cpu := info.CPU; cpu.ModelName != "" {
+		fmt.Println("\n⚙  CPU")
+		fmt.Printf("  %-14s %s\n", "Model:", cpu.ModelName)
+		fmt.Printf("  %-14s %d physical, %d logical\n", "Cores:", cpu.PhysicalCores, cpu.LogicalCores)
+		if cpu.BaseFrequency > 0 {
+			fmt.Printf("  %-14s %.2f GHz\n", "Frequency:", cpu.BaseFrequency)
+		}
+	}
+
+	fmt.Println("\n🧠 Memory")
+	fmt.Printf("  %-14s %d MB\n", "Total:", info.Memory.Total)
+	fmt.Printf("  %-14s %d MB\n", "Available:", info.Memory.Available)
+	if info.Memory.Slots.Total > 0 {
+		fmt.Printf("  %-14s %s @ %d MT/s\n", "Type:", info.Memory.Type, info.Memory.Frequency)
+		fmt.Printf("  %-14s %d of %d used\n", "Slots:", info.Memory.Slots.Used, info.Memory.Slots.Total)
+		for _, module := range info.Memory.Modules {
+			fmt.Printf("    %-28s %6d MB  %s %s\n", module.Slot, module.Size, module.Manufacturer, module.PartNumber)
+		}
+	}
+
+	if len(info.Storage) > 0 {
+		fmt.Println("\n💾 Storage")
+		for _, device := range info.Storage {
+			fmt.Printf("  %-10s %-9s %-9s %8d MB  %s\n", device.Name, device.Type, device.Interface, device.Capacity, device.Model)
+			if device.SmartStatus != "" {
+				fmt.Printf("  %-10s SMART %s, health %d%%, %d°C, %d h powered on\n", "", device.SmartStatus, device.Health, device.Temperature, device.PowerOnHours)
+			}
+		}
+	}
+
+	if len(info.GPU) > 0 {
+		fmt.Println("\n🎮 GPU")
+		for _, gpu := range info.GPU {
+			fmt.Printf("  %-3d %-36s %s", gpu.Index, gpu.Name, gpu.PCIBus)
+			if gpu.PCIe.Width > 0 {
+				fmt.Printf("  PCIe %.1f GT/s x%d (max %.1f GT/s x%d)", gpu.PCIe.Speed, gpu.PCIe.Width, gpu.PCIe.MaxSpeed, gpu.PCIe.MaxWidth)
+			}
+			if gpu.Memory.Total > 0 {
+				fmt.Printf("  %d MB", gpu.Memory.Total)
+			}
+			if gpu.Driver.Name != "" || gpu.Driver.Version != "" {
+				fmt.Printf("  %s %s", gpu.Driver.Name, gpu.Driver.Version)
+			}
+			if gpu.Driver.CUDAVersion != "" {
+				fmt.Printf("  CUDA %s", gpu.Driver.CUDAVersion)
+			}
+			if gpu.Power.TDP > 0 {
+				fmt.Printf("  %d/%d W  %d°C", gpu.Power.Current, gpu.Power.TDP, gpu.Temperature)
+			}
+			fmt.Println()
+		}
+	}
+
+	if len(info.Network) > 0 {
+		fmt.Println("\n🌐 Network")
+		for _, iface := range info.Network {
+			speed := "-"
+			if iface.Speed > 0 {
+				speed = fmt.Sprintf("%d Mbps", iface.Speed)
+			}
+			mtu := "-"
+			if iface.MTU > 0 {
+				mtu = fmt.Sprintf("mtu %d", iface.MTU)
+			}
+			fmt.Printf("  %-10s %-9s %-6s %-10s %-9s %-17s %s\n", iface.Name, iface.Type, iface.Status, speed, mtu, iface.MAC, iface.IPv4)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"octane/pkg/types"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+// readConfig replaces the global viper configuration for the duration of a test
+func readConfig(t *testing.T, config string) {
+	t.Helper()
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+	viper.SetConfigType("yaml")
+	if err := viper.ReadConfig(strings.NewReader(config)); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestHardwareRecommendationsLinkSpeed(t *testing.T) {
+	readConfig(t, `
+network:
+  link_speed:
+    expected: 1000
+    interfaces:
+      eth0.100: 10000
+      enP1s0: 25000
+      eth9: 10000
+`)
+	speeds, err := linkSpeedOverrides()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// viper lowercases the keys but keeps the VLAN name in one piece
+	if len(speeds) != 3 || speeds["eth0.100"] != 10000 || speeds["enp1s0"] != 25000 {
+		t.Fatalf("linkSpeedOverrides = %v", speeds)
+	}
+
+	info := &types.SystemInfo{Network: []types.NetworkInfo{
+		{Name: "eth0", Type: "ethernet", Speed: 10000, Duplex: "full", Status: "up"},
+		{Name: "eth0.100", Type: "virtual", Speed: 1000, Status: "up"},
+		{Name: "enP1s0", Type: "ethernet", Status: "down"},
+	}}
+	var got []string
+	for _, r := range hardwareRecommendations(info, speeds) {
+		got = append(got, r.Suggestion)
+	}
+	want := []string{
+		"eth9 is missing",
+		"eth0.100 linked at 1000 Mbps but 10000 Mbps is expected",
+		"enP1s0 is down but a 25000 Mbps link is expected",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("recommendations = %q, want %d", got, len(want))
+	}
+	for i := range want {
+		if !strings.HasPrefix(got[i], want[i]) {
+			t.Errorf("recommendation %d = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
+
+func TestLinkSpeedOverridesInvalid(t *testing.T) {
+	readConfig(t, "network:\n  link_speed:\n    interfaces:\n      eth0: fast\n")
+	if _, err := linkSpeedOverrides(); err == nil || !strings.HasPrefix(err.Error(), "invalid network.link_speed.interfaces") {
+		t.Errorf("linkSpeedOverrides = %v, want an error", err)
+	}
+}
@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"fmt"
+	"octane/pkg/benchmark"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+// listCmd represents the list command
+var listCmd = &cobra.Command{
+	Use:   "list [category]",
+	Short: "List available benchmarks",
+	Long:  `List every registered benchmark with its category and default duration. Any name shown here can be run with "octane bench".`,
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "NAME\tCATEGORY\tDURATION\tDESCRIPTION")
+		for _, b := range benchmark.List() {
+			if len(args) > 0 && b.Category() != args[0] {
+				continue
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", b.Name(), b.Category(), b.DefaultDuration(), b.Description())
+		}
+		w.Flush()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(listCmd)
+}
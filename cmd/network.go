@@ -1,33 +1,629 @@
 package cmd
 
-// import (
-//     "fmt"
-//     "github.com/spf13/cobra"
-//     "octane/pkg/utils"
-//     "octane/pkg/executor"
-// )
-
-// // networkCmd represents the network command
-// var networkCmd = &cobra.Command{
-//     Use:   "network",
-//     Short: "Run network performance tests",
-//     Long:  `This command runs various network performance tests including bandwidth, latency, and connectivity checks.`,
-//     Run: func(cmd *cobra.Command, args []string) {
-//         // Execute network tests
-//         fmt.Println("Starting network performance tests...")
-
-//         // Example of running a Python script for network testing
-//         err := executor.ExecutePythonScript("scripts/python/network_test.py")
-//         if err != nil {
-//             fmt.Printf("Error executing network test: %v\n", err)
-//             return
-//         }
-
-//         // Display results (this would be replaced with actual result handling)
-//         utils.DisplayResults("Network performance tests completed.")
-//     },
-// }
-
-// func init() {
-//     rootCmd.AddCommand(networkCmd)
-// }
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"octane/pkg/executor"
+	"octane/pkg/netperf"
+	"octane/pkg/types"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// networkCmd groups the host-to-host network tests
+var networkCmd = &cobra.Command{
+	Use:   "network",
+	Short: "Run network performance tests",
+	Long: `Measure network performance between two hosts without any public service.
+
+Start "octane network serve" on one host and run "octane network test --peer <host>"
+on the other. Both TCP and UDP use port ` + strconv.Itoa(netperf.DefaultPort) + ` unless another is given.
+The other subcommands test the network.catalog targets, iperf3 servers, HTTP
+mirrors, DNS resolvers, service reachability and TCP ports.`,
+}
+
+// networkServeCmd runs the peer server
+var networkServeCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Run the network test peer",
+	Long:  `Listen for "octane network test" clients until interrupted.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		listen, _ := cmd.Flags().GetString("listen")
+
+		server, err := netperf.Listen(listen)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Listening on %s (tcp, udp)\n", server.Addr())
+		return server.Serve(cmd.Context())
+	},
+}
+
+// networkTestCmd measures throughput, latency, jitter and loss against a peer
+var networkTestCmd = &cobra.Command{
+	Use:   "test",
+	Short: "Test throughput and latency against a peer",
+	Long: `Measure TCP latency, download and upload throughput over parallel streams
+and, with --udp-rate, UDP jitter and packet loss against an "octane network serve" peer.`,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		opts := netperf.Options{}
+		opts.Peer, _ = cmd.Flags().GetString("peer")
+		opts.Streams, _ = cmd.Flags().GetInt("streams")
+		opts.Duration, _ = cmd.Flags().GetDuration("duration")
+		opts.UDPRate, _ = cmd.Flags().GetFloat64("udp-rate")
+		opts.PacketSize, _ = cmd.Flags().GetInt("packet-size")
+
+		fmt.Printf("Testing against %s with %d streams for %v each way...\n",
+			netperf.PeerAddr(opts.Peer), opts.Streams, opts.Duration)
+		result, err := netperf.Run(cmd.Context(), opts)
+		if err = interrupted(cmd, err); err != nil {
+			return err
+		}
+
+		fmt.Println("\n🌐 Network Performance:")
+		fmt.Printf("  %-12s %.2f Mbps\n", "Download:", result.Download)
+		fmt.Printf("  %-12s %.2f Mbps\n", "Upload:", result.Upload)
+		fmt.Printf("  %-12s %.3f ms\n", "Latency:", result.Latency)
+		if opts.UDPRate > 0 {
+			fmt.Printf("  %-12s %.3f ms (UDP at %.0f Mbps)\n", "Jitter:", result.Jitter, opts.UDPRate)
+			fmt.Printf("  %-12s %.2f%%\n", "Packet loss:", result.PacketLoss)
+		}
+		return nil
+	},
+}
+
+// networkHTTPCmd measures download throughput from an HTTP(S) URL
+var networkHTTPCmd = &cobra.Command{
+	Use:   "http <url>",
+	Short: "Test download throughput from an HTTP(S) URL",
+	Long: `Download the URL repeatedly over parallel keep-alive connections for the test
+duration and discard the data. With --range-size each request fetches the next
+part of the file with a Range header. Reports throughput, median time to first
+byte and how many requests reused a connection.`,
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		opts := netperf.HTTPOptions{}
+		opts.Connections, _ = cmd.Flags().GetInt("connections")
+		opts.Duration, _ = cmd.Flags().GetDuration("duration")
+		if size, _ := cmd.Flags().GetString("range-size"); size != "" {
+			bytes, err := executor.ParseSize(size)
+			if err != nil {
+				return err
+			}
+			opts.RangeSize = int64(bytes)
+		}
+
+		fmt.Printf("Downloading %s over %d connections for %v...\n", args[0], opts.Connections, opts.Duration)
+		result, err := netperf.HTTPDownload(cmd.Context(), args[0], opts)
+		if err = interrupted(cmd, err); err != nil {
+			return err
+		}
+
+		fmt.Println("\n🌐 HTTP Download:")
+		fmt.Printf("  %-12s %.2f Mbps\n", "Throughput:", result.Download)
+		fmt.Printf("  %-12s %.2f ms\n", "TTFB:", result.TTFB)
+		fmt.Printf("  %-12s %.2f ms\n", "Connect:", result.Latency)
+		fmt.Printf("  %-12s %d (%.0f%% reused a connection)\n", "Requests:", result.Requests, result.ConnectionReuse)
+		return nil
+	},
+}
+
+// networkRunCmd measures bandwidth against every target in the catalog
+var networkRunCmd = &cobra.Command{
+	Use:   "run",
+	Short: "Test bandwidth against the network target catalog",
+	Long: `Test every target in the network.catalog file and in network.iperf3.servers,
+network.concurrency targets at a time. Results are grouped by region and targets
+below their min_download are reported as warnings.`,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		runner, err := networkRunner()
+		if err != nil {
+			return err
+		}
+		targets, err := networkTargets()
+		if err != nil {
+			return err
+		}
+		if len(targets) == 0 {
+			return fmt.Errorf("no network targets: add them to %s or network.iperf3.servers", expandHome(viper.GetString("network.catalog")))
+		}
+
+		fmt.Printf("Testing %d network targets, %d at a time...\n", len(targets), max(1, runner.Concurrency))
+		results, err := runner.Run(cmd.Context(), targets)
+		if err = interrupted(cmd, err); err != nil {
+			return err
+		}
+		displayNetworkResults(results)
+		return nil
+	},
+}
+
+// networkIperf3Cmd measures bandwidth against the configured iperf3 servers
+var networkIperf3Cmd = &cobra.Command{
+	Use:   "iperf3",
+	Short: "Test bandwidth against iperf3 servers",
+	Long: `Run iperf3 -J (TCP upload, TCP download with -R and, when network.udp_rate
+is set, UDP) against each iperf3 target in the catalog and network.iperf3.servers,
+or against --server.`,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		runner, err := networkRunner()
+		if err != nil {
+			return err
+		}
+		if runner.Iperf3 == nil {
+			return fmt.Errorf("iperf3 not found: install it (e.g. apt install iperf3) or place it in %s", toolsDir())
+		}
+
+		var targets []netperf.Target
+		if hosts, _ := cmd.Flags().GetStringSlice("server"); len(hosts) > 0 {
+			for _, host := range hosts {
+				server := netperf.Iperf3Server{Host: host}
+				if h, port, err := net.SplitHostPort(host); err == nil {
+					server.Host = h
+					server.Port, _ = strconv.Atoi(port)
+				}
+				targets = append(targets, netperf.Iperf3Target(server))
+			}
+		} else {
+			all, err := networkTargets()
+			if err != nil {
+				return err
+			}
+			for _, target := range all {
+				if target.Protocol == netperf.ProtocolIperf3 {
+					targets = append(targets, target)
+				}
+			}
+			if len(targets) == 0 {
+				return fmt.Errorf("no iperf3 servers configured (network.iperf3.servers or %s)", expandHome(viper.GetString("network.catalog")))
+			}
+		}
+
+		fmt.Printf("Running iperf3 against %d servers with %d streams for %v each way...\n",
+			len(targets), runner.Iperf3.Streams, runner.Iperf3.Duration)
+		results, err := runner.Run(cmd.Context(), targets)
+		if err = interrupted(cmd, err); err != nil {
+			return err
+		}
+		displayNetworkResults(results)
+		return nil
+	},
+}
+
+// networkDNSCmd times name resolution against the configured resolvers
+var networkDNSCmd = &cobra.Command{
+	Use:   "dns",
+	Short: "Time DNS resolution against the configured resolvers",
+	Long: `Query each name in network.dns.names network.dns.queries times against each
+resolver in network.dns.resolvers. The first query of each name is reported as
+cold; the median of the rest, which the resolver should answer from its cache,
+as cached. Resolvers are "system", udp://, tcp://, tls://host[:port] or a DoH
+https:// URL.`,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		names, resolvers, opts, err := dnsOptions()
+		if err != nil {
+			return err
+		}
+		if flag, _ := cmd.Flags().GetStringSlice("name"); len(flag) > 0 {
+			names = flag
+		}
+		if flag, _ := cmd.Flags().GetStringSlice("resolver"); len(flag) > 0 {
+			if resolvers, err = parseResolvers(flag); err != nil {
+				return err
+			}
+		}
+		if len(names) == 0 {
+			return fmt.Errorf("no names to resolve: set network.dns.names or --name")
+		}
+
+		results, err := netperf.DNS(cmd.Context(), names, resolvers, opts)
+		if err = interrupted(cmd, err); err != nil {
+			return err
+		}
+		displayDNSResults(results)
+		return nil
+	},
+}
+
+// dnsOptions reads the names, resolvers and query options from network.dns
+func dnsOptions() ([]string, []netperf.Resolver, netperf.DNSOptions, error) {
+	opts := netperf.DNSOptions{Queries: viper.GetInt("network.dns.queries")}
+	timeout, err := time.ParseDuration(viper.GetString("network.dns.timeout"))
+	if err != nil {
+		return nil, nil, opts, fmt.Errorf("invalid network.dns.timeout: %v", err)
+	}
+	opts.Timeout = timeout
+
+	resolvers, err := parseResolvers(viper.GetStringSlice("network.dns.resolvers"))
+	if err != nil {
+		return nil, nil, opts, fmt.Errorf("invalid network.dns.resolvers: %v", err)
+	}
+	return viper.GetStringSlice("network.dns.names"), resolvers, opts, nil
+}
+
+// parseResolvers parses resolver specs, defaulting to the system resolver
+func parseResolvers(specs []string) ([]netperf.Resolver, error) {
+	if len(specs) == 0 {
+		specs = []string{netperf.DNSSystem}
+	}
+	resolvers := make([]netperf.Resolver, 0, len(specs))
+	for _, spec := range specs {
+		resolver, err := netperf.ParseResolver(spec)
+		if err != nil {
+			return nil, err
+		}
+		resolvers = append(resolvers, resolver)
+	}
+	return resolvers, nil
+}
+
+// displayDNSResults prints the timing of each resolver and name
+func displayDNSResults(results []types.DNSResult) {
+	fmt.Println("\n🔎 DNS Resolution:")
+	resolver := ""
+	for _, r := range results {
+		if r.Resolver != resolver {
+			resolver = r.Resolver
+			fmt.Printf("  %s:\n", resolver)
+		}
+		if r.Failures == r.Queries {
+			fmt.Printf("    %-24s failed: %s\n", r.Name, r.Error)
+			continue
+		}
+		fmt.Printf("    %-24s median %.2f ms  p95 %.2f ms  cold %.2f ms  cached %.2f ms",
+			r.Name, r.Median, r.P95, r.Cold, r.Cached)
+		if r.Failures > 0 {
+			fmt.Printf("  %.0f%% failed", r.FailureRate)
+		}
+		fmt.Println()
+	}
+}
+
+// networkServicesCmd checks that the configured services are reachable
+var networkServicesCmd = &cobra.Command{
+	Use:   "services",
+	Short: "Check that the configured services are reachable",
+	Long: `Check each service in network.connectivity.services: HTTP(S) URLs must answer
+with the expected status, TCP addresses must accept a connection and, with tls set,
+complete a TLS handshake. TCP connect, TLS handshake, first byte and total times
+are reported for each service.`,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		services, timeout, err := connectivityOptions()
+		if err != nil {
+			return err
+		}
+		if len(services) == 0 {
+			return fmt.Errorf("no services configured (network.connectivity.services)")
+		}
+
+		results, err := netperf.CheckServices(cmd.Context(), services, timeout)
+		if err = interrupted(cmd, err); err != nil {
+			return err
+		}
+		displayServiceResults(results)
+		for _, r := range results {
+			if !r.Reachable {
+				return &exitError{code: 1, err: errors.New("some services are not reachable")}
+			}
+		}
+		return nil
+	},
+}
+
+// connectivityOptions reads the services and check timeout from network.connectivity
+func connectivityOptions() ([]netperf.Service, time.Duration, error) {
+	timeout, err := time.ParseDuration(viper.GetString("network.connectivity.timeout"))
+	if err != nil {
+		return nil, 0, fmt.Errorf("invalid network.connectivity.timeout: %v", err)
+	}
+	var services []netperf.Service
+	if err := viper.UnmarshalKey("network.connectivity.services", &services); err != nil {
+		return nil, 0, fmt.Errorf("invalid network.connectivity.services: %v", err)
+	}
+	seen := make(map[string]bool)
+	for _, service := range services {
+		if err := service.Validate(); err != nil {
+			return nil, 0, err
+		}
+		if seen[service.Name] {
+			return nil, 0, fmt.Errorf("duplicate service %s", service.Name)
+		}
+		seen[service.Name] = true
+	}
+	return services, timeout, nil
+}
+
+// regionWeights reads network.region_weights, which only knows the domestic and international groups;
+// it returns nil when no weights are configured
+func regionWeights() (map[string]float64, error) {
+	var weights map[string]float64
+	if err := viper.UnmarshalKey("network.region_weights", &weights); err != nil {
+		return nil, fmt.Errorf("invalid network.region_weights: %v", err)
+	}
+	for region, weight := range weights {
+		if region != netperf.RegionDomestic && region != netperf.RegionInternational {
+			return nil, fmt.Errorf("invalid network.region_weights: unknown region %q (want domestic or international)", region)
+		}
+		if weight < 0 {
+			return nil, fmt.Errorf("invalid network.region_weights: %s must not be negative", region)
+		}
+	}
+	return weights, nil
+}
+
+// displayServiceResults prints the reachability and timing of each service
+func displayServiceResults(results []types.ServiceResult) {
+	fmt.Println("\n🔌 Service Accessibility:")
+	for _, r := range results {
+		if !r.Reachable {
+			fmt.Printf("  ❌ %-20s %s: %s\n", r.Name, r.Target, r.Error)
+			continue
+		}
+		fmt.Printf("  ✅ %-20s connect %.2f ms", r.Name, r.Connect)
+		if r.TLS > 0 {
+			fmt.Printf("  tls %.2f ms", r.TLS)
+		}
+		if r.TTFB > 0 {
+			fmt.Printf("  ttfb %.2f ms", r.TTFB)
+		}
+		fmt.Printf("  total %.2f ms", r.Total)
+		if r.Status > 0 {
+			fmt.Printf("  (%d)", r.Status)
+		}
+		fmt.Println()
+	}
+}
+
+// networkPortsCmd checks which TCP ports are reachable from this host
+var networkPortsCmd = &cobra.Command{
+	Use:   "ports",
+	Short: "Check which TCP ports are reachable",
+	Long: `Connect to each host and port in network.port_scan.targets, or to --target,
+and classify the port as open, closed (refused) or filtered (timed out).
+Connections are limited by network.port_scan.concurrency and network.port_scan.rate,
+and at most network.port_scan.max_targets host:port pairs are checked. The rate
+is capped at ` + strconv.Itoa(netperf.MaxScanRate) + ` connections per second, the concurrency at ` + strconv.Itoa(netperf.MaxScanConcurrency) + `
+and max_targets at ` + strconv.Itoa(netperf.MaxScanTargets) + `.`,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		targets, opts, err := portScanOptions()
+		if err != nil {
+			return err
+		}
+		if flag, _ := cmd.Flags().GetStringSlice("target"); len(flag) > 0 {
+			targets = targets[:0]
+			for _, target := range flag {
+				host, ports, ok := strings.Cut(target, "=")
+				if !ok {
+					return fmt.Errorf("invalid target %q (want host=ports)", target)
+				}
+				targets = append(targets, netperf.ScanTarget{Host: host, Ports: ports})
+			}
+		}
+		if len(targets) == 0 {
+			return fmt.Errorf("no scan targets configured (network.port_scan.targets or --target)")
+		}
+
+		results, err := netperf.Scan(cmd.Context(), targets, opts)
+		if err = interrupted(cmd, err); err != nil {
+			return err
+		}
+		displayPortScan(results)
+		return nil
+	},
+}
+
+// portScanOptions reads the scan targets and limits from network.port_scan
+func portScanOptions() ([]netperf.ScanTarget, netperf.ScanOptions, error) {
+	opts := netperf.ScanOptions{
+		Concurrency: viper.GetInt("network.port_scan.concurrency"),
+		Rate:        viper.GetFloat64("network.port_scan.rate"),
+		MaxTargets:  viper.GetInt("network.port_scan.max_targets"),
+	}
+	timeout, err := time.ParseDuration(viper.GetString("network.port_scan.timeout"))
+	if err != nil {
+		return nil, opts, fmt.Errorf("invalid network.port_scan.timeout: %v", err)
+	}
+	opts.Timeout = timeout
+
+	var targets []netperf.ScanTarget
+	if err := viper.UnmarshalKey("network.port_scan.targets", &targets); err != nil {
+		return nil, opts, fmt.Errorf("invalid network.port_scan.targets: %v", err)
+	}
+	return targets, opts, nil
+}
+
+// displayPortScan prints the state of each host:port in order
+func displayPortScan(results map[string]string) {
+	addresses := make([]string, 0, len(results))
+	for address := range results {
+		addresses = append(addresses, address)
+	}
+	sort.Slice(addresses, func(i, j int) bool {
+		hi, pi, _ := net.SplitHostPort(addresses[i])
+		hj, pj, _ := net.SplitHostPort(addresses[j])
+		if hi != hj {
+			return hi < hj
+		}
+		a, _ := strconv.Atoi(pi)
+		b, _ := strconv.Atoi(pj)
+		return a < b
+	})
+
+	counts := make(map[string]int)
+	fmt.Println("\n🚪 Port Scan:")
+	for _, address := range addresses {
+		state := results[address]
+		counts[state]++
+		fmt.Printf("  %-28s %s\n", address, state)
+	}
+	fmt.Printf("\n%d open, %d closed, %d filtered", counts[netperf.PortOpen], counts[netperf.PortClosed], counts[netperf.PortFiltered])
+	if counts[netperf.PortUnresolved] > 0 {
+		fmt.Printf(", %d unresolved", counts[netperf.PortUnresolved])
+	}
+	fmt.Println()
+}
+
+// networkBandwidth tests the catalog targets or, when there are none,
+// runs the speedtest script
+func networkBandwidth(ctx context.Context) (*types.NetworkResults, error) {
+	targets, err := networkTargets()
+	if err != nil {
+		return nil, err
+	}
+	if len(targets) > 0 {
+		runner, err := networkRunner()
+		if err != nil {
+			return nil, err
+		}
+		return runner.Run(ctx, targets)
+	}
+
+	scripts, err := scriptDir()
+	if err != nil {
+		return nil, err
+	}
+	return executor.ExecuteNetworkTest(ctx, scripts)
+}
+
+// networkRunner builds the catalog runner from the network config section.
+// iperf3 is optional: without it only iperf3 targets fail.
+func networkRunner() (*netperf.CatalogRunner, error) {
+	duration, err := time.ParseDuration(viper.GetString("network.duration"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid network.duration: %v", err)
+	}
+
+	runner := &netperf.CatalogRunner{
+		Options: netperf.Options{
+			Streams:  viper.GetInt("network.streams"),
+			Duration: duration,
+			UDPRate:  viper.GetFloat64("network.udp_rate"),
+		},
+		Concurrency: viper.GetInt("network.concurrency"),
+	}
+	if path, err := executor.FindTool(toolsDir(), "iperf3"); err == nil {
+		runner.Iperf3 = &netperf.Iperf3{
+			Path:     path,
+			Streams:  runner.Options.Streams,
+			Duration: duration,
+			UDPRate:  runner.Options.UDPRate,
+		}
+	}
+	return runner, nil
+}
+
+// networkTargets loads the network.catalog file and appends network.iperf3.servers
+func networkTargets() ([]netperf.Target, error) {
+	path := expandHome(viper.GetString("network.catalog"))
+	catalog, err := netperf.LoadCatalog(path)
+	if err != nil {
+		return nil, fmt.Errorf("loading %s: %v", path, err)
+	}
+
+	var servers []netperf.Iperf3Server
+	if err := viper.UnmarshalKey("network.iperf3.servers", &servers); err != nil {
+		return nil, fmt.Errorf("invalid network.iperf3.servers: %v", err)
+	}
+	for _, server := range servers {
+		if server.Host == "" {
+			return nil, fmt.Errorf("iperf3 server %q has no host", server.Name)
+		}
+		catalog.Targets = append(catalog.Targets, netperf.Iperf3Target(server))
+	}
+	if err := catalog.Validate(); err != nil {
+		return nil, err
+	}
+	return catalog.Targets, nil
+}
+
+// displayNetworkResults prints the bandwidth results of each region
+func displayNetworkResults(results *types.NetworkResults) {
+	for _, group := range []struct {
+		title   string
+		results map[string]types.BandwidthResult
+	}{
+		{"🏠 Domestic", results.Bandwidth.Domestic},
+		{"🌍 International", results.Bandwidth.International},
+	} {
+		if len(group.results) == 0 {
+			continue
+		}
+		fmt.Printf("\n%s:\n", group.title)
+		names := make([]string, 0, len(group.results))
+		for name := range group.results {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			r := group.results[name]
+			fmt.Printf("  %s: ↓ %.2f Mbps  ↑ %.2f Mbps  %.2f ms", name, r.Download, r.Upload, r.Latency)
+			if r.Retransmits > 0 {
+				fmt.Printf("  %d retransmits", r.Retransmits)
+			}
+			if r.Jitter > 0 || r.PacketLoss > 0 {
+				fmt.Printf("  jitter %.3f ms  loss %.2f%%", r.Jitter, r.PacketLoss)
+			}
+			if r.LocalCPU > 0 || r.RemoteCPU > 0 {
+				fmt.Printf("  cpu %.0f%%/%.0f%%", r.LocalCPU, r.RemoteCPU)
+			}
+			if r.Requests > 0 {
+				fmt.Printf("  ttfb %.2f ms  %d requests  %.0f%% reused", r.TTFB, r.Requests, r.ConnectionReuse)
+			}
+			fmt.Println()
+		}
+	}
+
+	if len(results.Errors) > 0 {
+		fmt.Println("\n❌ Failed:")
+		names := make([]string, 0, len(results.Errors))
+		for name := range results.Errors {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			fmt.Printf("  %s: %s\n", name, results.Errors[name])
+		}
+	}
+	for _, warning := range results.Warnings {
+		fmt.Printf("⚠️  %s\n", warning)
+	}
+}
+
+func init() {
+	networkServeCmd.Flags().String("listen", ":"+strconv.Itoa(netperf.DefaultPort), "Address to listen on")
+
+	networkTestCmd.Flags().String("peer", "", "Peer running \"octane network serve\" (host[:port])")
+	networkTestCmd.Flags().Int("streams", 4, "Number of parallel TCP streams")
+	networkTestCmd.Flags().DurationP("duration", "d", 10*time.Second, "Duration of the download and upload tests")
+	networkTestCmd.Flags().Float64("udp-rate", 0, "UDP send rate in Mbps for jitter and loss (0 skips UDP)")
+	networkTestCmd.Flags().Int("packet-size", 1200, "UDP packet size in bytes")
+	networkTestCmd.MarkFlagRequired("peer")
+
+	networkHTTPCmd.Flags().Int("connections", 4, "Number of parallel connections")
+	networkHTTPCmd.Flags().DurationP("duration", "d", 10*time.Second, "Duration of the download test")
+	networkHTTPCmd.Flags().String("range-size", "", "Fetch the file in Range requests of this size (e.g. 8MB)")
+
+	networkDNSCmd.Flags().StringSlice("name", nil, "Names to resolve instead of network.dns.names")
+	networkDNSCmd.Flags().StringSlice("resolver", nil, "Resolvers to test instead of network.dns.resolvers")
+
+	networkPortsCmd.Flags().StringSlice("target", nil, "Targets to scan instead of network.port_scan.targets (host=ports, e.g. 10.0.0.5=22,80)")
+
+	networkIperf3Cmd.Flags().StringSlice("server", nil, "iperf3 servers to test instead of the configured ones (host[:port])")
+
+	networkCmd.AddCommand(networkServeCmd, networkTestCmd, networkRunCmd, networkIperf3Cmd, networkHTTPCmd, networkDNSCmd, networkServicesCmd, networkPortsCmd)
+	rootCmd.AddCommand(networkCmd)
+}
@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"log"
+	"octane/pkg/plugin"
+
+	"github.com/spf13/viper"
+)
+
+// plugins holds the external benchmarks loaded from plugins.dir
+var plugins []*plugin.Plugin
+
+// loadPlugins loads plugin manifests, applies configured weights and registers them as benchmarks
+func loadPlugins() {
+	loaded, errs := plugin.LoadDir(expandHome(viper.GetString("plugins.dir")))
+	for _, err := range errs {
+		log.Printf("Warning: skipping plugin: %v", err)
+	}
+
+	weights := viper.GetStringMap("plugins.weights")
+	for _, p := range loaded {
+		if _, ok := weights[p.Manifest.Name]; !ok {
+			continue
+		}
+		weight := viper.GetFloat64("plugins.weights." + p.Manifest.Name)
+		if weight < 0 || weight > 1 {
+			log.Printf("Warning: ignoring weight %v for plugin %s: must be between 0 and 1", weight, p.Manifest.Name)
+			continue
+		}
+		p.Manifest.Weight = weight
+	}
+
+	// Plugin weights are taken out of the built-in score, so together they cannot exceed 1
+	var total float64
+	for _, p := range loaded {
+		total += p.Manifest.Weight
+	}
+	if total > 1 {
+		log.Printf("Warning: ignoring plugin weights: they add up to %v, must not exceed 1", total)
+		for _, p := range loaded {
+			p.Manifest.Weight = 0
+		}
+	}
+
+	if err := plugin.Register(loaded); err != nil {
+		log.Printf("Warning: plugins not registered: %v", err)
+		return
+	}
+	plugins = loaded
+}
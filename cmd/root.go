@@ -1,12 +1,24 @@
 package cmd
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"log"
+	"octane/pkg/monitor"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
 
+// version is the octane release recorded in generated reports
+const version = "1.0.0"
+
 var rootCmd = &cobra.Command{
 	Use:   "octane",
 	Short: "Octane Performance Analyzer",
@@ -17,14 +29,46 @@ var rootCmd = &cobra.Command{
 	},
 }
 
+// exitInterrupted is the exit code used when a run is stopped by SIGINT/SIGTERM
+const exitInterrupted = 130
+
+// exitError carries a specific process exit code out of a command
+type exitError struct {
+	code int
+	err  error
+}
+
+func (e *exitError) Error() string {
+	return e.err.Error()
+}
+
+// interrupted returns an exitInterrupted error when the command context was cancelled
+// by SIGINT/SIGTERM and err otherwise, so every benchmark command exits like "octane test"
+func interrupted(cmd *cobra.Command, err error) error {
+	if cmd.Context().Err() != nil {
+		return &exitError{code: exitInterrupted, err: fmt.Errorf("%s interrupted", cmd.CommandPath())}
+	}
+	return err
+}
+
 func Execute() {
-	if err := rootCmd.Execute(); err != nil {
+	// Ctrl-C cancels the command context so running benchmarks can stop cleanly
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := rootCmd.ExecuteContext(ctx); err != nil {
+		var exitErr *exitError
+		if errors.As(err, &exitErr) {
+			log.Printf("%v", exitErr.err)
+			stop()
+			os.Exit(exitErr.code)
+		}
 		log.Fatalf("Error executing command: %v", err)
 	}
 }
 
 func init() {
-	cobra.OnInitialize(initConfig)
+	cobra.OnInitialize(initConfig, loadPlugins)
 
 	// Define global flags
 	rootCmd.PersistentFlags().StringP("config", "c", "", "config file (default is $HOME/.octane.yaml)")
@@ -36,6 +80,8 @@ func init() {
 }
 
 func initConfig() {
+	setConfigDefaults()
+
 	if cfgFile := viper.GetString("config"); cfgFile != "" {
 		viper.SetConfigFile(cfgFile)
 
@@ -57,3 +103,34 @@ func initConfig() {
 		}
 	}
 }
+
+// setConfigDefaults loads the embedded configs/default.yaml so commands work without a config file
+func setConfigDefaults() {
+	if err := loadDefaultConfig(); err != nil {
+		log.Printf("Error loading default config: %v", err)
+	}
+}
+
+// runContext returns the command context with RAPL power sampling switched on
+// according to tests.power_monitoring
+func runContext(cmd *cobra.Command) context.Context {
+	return monitor.WithPowerMonitoring(cmd.Context(), viper.GetBool("tests.power_monitoring"))
+}
+
+// toolsDir returns the tools/ directory shipped next to the octane binary
+func toolsDir() string {
+	if exe, err := os.Executable(); err == nil {
+		return filepath.Join(filepath.Dir(exe), "tools")
+	}
+	return "tools"
+}
+
+// expandHome expands a leading ~ in path to the user's home directory
+func expandHome(path string) string {
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, path[1:])
+		}
+	}
+	return path
+}
@@ -1,39 +1,502 @@
 package cmd
 
-// import (
-//     "fmt"
-//     "github.com/spf13/cobra"
-//     "octane/pkg/octane"
-// )
-
-// // testCmd represents the test command
-// var testCmd = &cobra.Command{
-//     Use:   "test",
-//     Short: "Run the complete test suite",
-//     Long:  `Run the complete test suite to evaluate the performance of the system across various components.`,
-//     Run: func(cmd *cobra.Command, args []string) {
-//         // Initialize the octane calculator
-//         calculator := octane.NewCalculator()
-
-//         // Run tests
-//         results, err := calculator.RunAllTests()
-//         if err != nil {
-//             fmt.Println("Error running tests:", err)
-//             return
-//         }
-
-//         // Generate report
-//         report, err := calculator.GenerateReport(results)
-//         if err != nil {
-//             fmt.Println("Error generating report:", err)
-//             return
-//         }
-
-//         // Output the report
-//         fmt.Println(report)
-//     },
-// }
-
-// func init() {
-//     rootCmd.AddCommand(testCmd)
-// }
+import (
+	"context"
+	"fmt"
+	"octane/pkg/benchmark"
+	"octane/pkg/cgroup"
+	"octane/pkg/database"
+	"octane/pkg/executor"
+	"octane/pkg/gpu"
+	"octane/pkg/monitor"
+	"octane/pkg/netperf"
+	"octane/pkg/octane"
+	"octane/pkg/plugin"
+	"octane/pkg/suite"
+	"octane/pkg/types"
+	"octane/pkg/yaml"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// testCmd represents the test command
+var testCmd = &cobra.Command{
+	Use:   "test",
+	Short: "Run the complete test suite",
+	Long: `Run the complete test suite to evaluate the performance of the system across various components.
+
+Stages run in order (cpu, memory, storage, network, gpu) with a cooldown between
+them. Before each stage octane waits for background CPU, disk and network activity
+to settle (tests.quiescence); if it does not, the report is marked as contended.
+A failing stage is recorded in the report without aborting the others.
+Ctrl-C stops the current stage and saves the completed stages as a partial report.`,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		resume, _ := cmd.Flags().GetString("resume")
+		output, _ := cmd.Flags().GetString("output")
+		checkpointDir := expandHome(viper.GetString("global.checkpoint_dir"))
+
+		// 恢复运行时沿用检查点中的参数，保证各阶段结果可比
+		var checkpoint *suite.Checkpoint
+		if resume != "" {
+			// Set 会追加到命令行上已设置的 StringSlice，且与检查点不同的参数会使结果不可比
+			for _, name := range suiteSettings {
+				if cmd.Flags().Changed(name) {
+					return fmt.Errorf("--%s cannot be combined with --resume: a resumed run keeps the settings it started with", name)
+				}
+			}
+			var err error
+			checkpoint, err = suite.LoadCheckpoint(checkpointDir, resume)
+			if err != nil {
+				return err
+			}
+			for name, value := range checkpoint.Config {
+				if !slices.Contains(suiteSettings, name) {
+					return fmt.Errorf("invalid checkpoint setting %s", name)
+				}
+				if err := cmd.Flags().Set(name, value); err != nil {
+					return fmt.Errorf("invalid checkpoint setting %s: %v", name, err)
+				}
+			}
+			fmt.Printf("Resuming run %s (last updated %s)\n", checkpoint.RunID, checkpoint.UpdatedAt)
+		}
+
+		only, _ := cmd.Flags().GetStringSlice("only")
+		skip, _ := cmd.Flags().GetStringSlice("skip")
+		cooldown, _ := cmd.Flags().GetDuration("cooldown")
+
+		cpuInfo, err := executor.GetCPUInfo()
+		if err != nil {
+			cpuInfo = &types.CPUInfo{}
+		}
+
+		quiescence, abortIfBusy, err := quiescenceOptions()
+		if err != nil {
+			return err
+		}
+		gpuTool, gpuInterval, err := gpuTelemetryOptions()
+		if err != nil {
+			return err
+		}
+		linkSpeeds, err := linkSpeedOverrides()
+		if err != nil {
+			return err
+		}
+		weights, err := regionWeights()
+		if err != nil {
+			return err
+		}
+
+		runner := suite.NewRunner(suiteStages(cmd), suite.Options{
+			Only:           only,
+			Skip:           skip,
+			Cooldown:       cooldown,
+			Version:        version,
+			Tags:           viper.GetStringSlice("upload.tags"),
+			StealThreshold: viper.GetFloat64("tests.steal_threshold"),
+			Quiescence:     quiescence,
+			AbortIfBusy:    abortIfBusy,
+			GPUTelemetry:   gpuTool,
+			GPUInterval:    gpuInterval,
+			CheckpointDir:  checkpointDir,
+			Fingerprint:    systemFingerprint(cpuInfo),
+			Config:         suiteConfig(cmd),
+		})
+		if len(weights) > 0 {
+			runner.Calculator.RegionWeights = weights
+		}
+
+		var report *types.Report
+		if checkpoint != nil {
+			report, err = runner.Resume(runContext(cmd), checkpoint)
+		} else {
+			report, err = runner.Run(runContext(cmd))
+		}
+		if err != nil {
+			return err
+		}
+
+		report.SystemInfo = *collectSystemInfo(cmd.Context(), "/", false, linkSpeeds)
+		report.SystemInfo.CPU = *cpuInfo
+		report.Metadata.ContainerLimited = cgroup.Self().Limited(runtime.NumCPU(), int64(report.SystemInfo.Memory.Total)<<20)
+		if rating, ok := report.OctaneRatings.Breakdown["storage"]; ok {
+			rating.Warnings = octane.StorageHealthWarnings(report.SystemInfo.Storage)
+			report.OctaneRatings.Breakdown["storage"] = rating
+		}
+		report.Recommendations.Hardware = hardwareRecommendations(&report.SystemInfo, linkSpeeds)
+		if sample, ok := report.TestResults.GPUTelemetry["gpu"]; ok {
+			gpu.FillResults(&report.TestResults.GPU, sample)
+		}
+
+		displayReport(report, runner.StealExceeded(&report.TestResults))
+		saveReport(report, output)
+
+		if finished(report) {
+			if path, err := suite.CheckpointPath(checkpointDir, report.Metadata.TestID); err == nil {
+				os.Remove(path)
+			}
+		} else {
+			fmt.Printf("\n↻ Resume with: octane test --resume %s\n", report.Metadata.TestID)
+		}
+
+		if report.Metadata.Partial {
+			return &exitError{code: exitInterrupted, err: fmt.Errorf("test suite interrupted, partial results saved")}
+		}
+		if report.Metadata.Contended && abortIfBusy {
+			return &exitError{code: 1, err: fmt.Errorf("test suite aborted: system did not become idle")}
+		}
+		return nil
+	},
+}
+
+func init() {
+	testCmd.Flags().StringSlice("only", nil, "Only run these stages (cpu,memory,storage,network,gpu)")
+	testCmd.Flags().StringSlice("skip", nil, "Skip these stages (cpu,memory,storage,network,gpu)")
+	testCmd.Flags().Duration("cooldown", 10*time.Second, "Cooldown between stages")
+	testCmd.Flags().StringP("duration", "d", "60s", "Duration of each benchmark stage")
+	testCmd.Flags().IntP("threads", "t", 0, "Number of CPU threads to use (default is auto)")
+	testCmd.Flags().String("memory-size", "", "Size of memory to test (default 512MB, capped by the container memory limit)")
+	testCmd.Flags().String("storage-size", "256MB", "Size of the storage test file")
+	testCmd.Flags().StringP("output", "o", "", "Report file path (default is <temp_dir>/<test_id>.yaml)")
+	testCmd.Flags().String("resume", "", "Resume an interrupted run by its run ID, skipping finished stages (keeps the run's original settings)")
+
+	rootCmd.AddCommand(testCmd)
+}
+
+// quiescenceOptions 读取阶段开始前的空闲检查配置，未启用时返回 nil
+func quiescenceOptions() (*monitor.QuietOptions, bool, error) {
+	if !viper.GetBool("tests.quiescence.enabled") {
+		return nil, false, nil
+	}
+
+	action := viper.GetString("tests.quiescence.action")
+	if action != "mark" && action != "abort" {
+		return nil, false, fmt.Errorf("invalid tests.quiescence.action %q (want mark or abort)", action)
+	}
+	timeout, err := time.ParseDuration(viper.GetString("tests.quiescence.timeout"))
+	if err != nil {
+		return nil, false, fmt.Errorf("invalid tests.quiescence.timeout: %v", err)
+	}
+
+	return &monitor.QuietOptions{
+		Root:    "/",
+		CPU:     viper.GetFloat64("tests.quiescence.max_cpu"),
+		Disk:    viper.GetFloat64("tests.quiescence.max_disk"),
+		Network: viper.GetFloat64("tests.quiescence.max_network"),
+		Timeout: timeout,
+		Top:     5,
+	}, action == "abort", nil
+}
+
+// gpuTelemetryOptions 读取 GPU 遥测配置，未启用或找不到 nvidia-smi 和 rocm-smi 时返回 nil
+func gpuTelemetryOptions() (gpu.Source, time.Duration, error) {
+	if !viper.GetBool("tests.gpu_telemetry.enabled") {
+		return nil, 0, nil
+	}
+	interval, err := time.ParseDuration(viper.GetString("tests.gpu_telemetry.interval"))
+	if err != nil {
+		return nil, 0, fmt.Errorf("invalid tests.gpu_telemetry.interval: %v", err)
+	}
+	tool, err := gpu.Find(toolsDir())
+	if err != nil {
+		return nil, 0, nil
+	}
+	return tool, interval, nil
+}
+
+// suiteStages 构造完整测试套件的各个阶段
+func suiteStages(cmd *cobra.Command) []suite.Stage {
+	duration, _ := cmd.Flags().GetString("duration")
+	threads, _ := cmd.Flags().GetInt("threads")
+	memorySize, _ := cmd.Flags().GetString("memory-size")
+	storageSize, _ := cmd.Flags().GetString("storage-size")
+	tempDir := viper.GetString("global.temp_dir")
+
+	stages := []suite.Stage{
+		{Name: "cpu", Run: func(ctx context.Context, results *types.TestResults) error {
+			cpu, err := executor.ExecuteCPUTest(ctx, threads, duration, "all")
+			if err != nil {
+				return err
+			}
+			results.CPU = *cpu
+			return nil
+		}},
+		{Name: "memory", Run: func(ctx context.Context, results *types.TestResults) error {
+			memory, err := executor.ExecuteMemoryTest(ctx, memorySize, duration)
+			if err != nil {
+				return err
+			}
+			results.Memory = *memory
+			return nil
+		}},
+		{Name: "storage", Run: func(ctx context.Context, results *types.TestResults) error {
+			storage, err := executor.ExecuteStorageTest(ctx, filepath.Join(tempDir, "storage"), storageSize, duration)
+			if err != nil {
+				return err
+			}
+			results.Storage = *storage
+			return nil
+		}},
+		{Name: "network", Run: func(ctx context.Context, results *types.TestResults) error {
+			network, err := networkBandwidth(ctx)
+			if err != nil {
+				return err
+			}
+
+			names, resolvers, opts, err := dnsOptions()
+			if err != nil {
+				return err
+			}
+			if len(names) > 0 {
+				dns, err := netperf.DNS(ctx, names, resolvers, opts)
+				if err != nil {
+					return err
+				}
+				network.Connectivity.DNS = dns
+				network.Connectivity.DNSResolution = netperf.DNSResolution(dns)
+			}
+
+			services, timeout, err := connectivityOptions()
+			if err != nil {
+				return err
+			}
+			if len(services) > 0 {
+				checks, err := netperf.CheckServices(ctx, services, timeout)
+				if err != nil {
+					return err
+				}
+				network.Connectivity.Services = checks
+				network.Connectivity.ServiceAccessibility = netperf.ServiceAccessibility(checks)
+			}
+
+			scanTargets, scanOptions, err := portScanOptions()
+			if err != nil {
+				return err
+			}
+			if len(scanTargets) > 0 {
+				ports, err := netperf.Scan(ctx, scanTargets, scanOptions)
+				if err != nil {
+					return err
+				}
+				network.Connectivity.PortScan = ports
+			}
+			results.Network = *network
+			return nil
+		}},
+		{Name: "gpu", Run: func(ctx context.Context, results *types.TestResults) error {
+			scripts, err := scriptDir()
+			if err != nil {
+				return err
+			}
+			gpu, err := executor.ExecuteGPUTest(ctx, scripts)
+			if err != nil {
+				return err
+			}
+			results.GPU = *gpu
+			return nil
+		}},
+	}
+
+	// 插件阶段只在插件目录中有有效插件时加入
+	if len(plugins) > 0 {
+		stages = append(stages, suite.Stage{Name: "plugins", Run: func(ctx context.Context, results *types.TestResults) error {
+			results.Plugins = plugin.Execute(ctx, plugins, benchmark.Options{
+				Threads: threads,
+				Dir:     filepath.Join(tempDir, "plugins"),
+			})
+			return nil
+		}})
+	}
+	return stages
+}
+
+// suiteSettings 是写入检查点并在恢复时还原的参数
+var suiteSettings = []string{"only", "skip", "cooldown", "duration", "threads", "memory-size", "storage-size"}
+
+// suiteConfig 收集本次运行的参数
+func suiteConfig(cmd *cobra.Command) map[string]string {
+	config := make(map[string]string)
+	for _, name := range suiteSettings {
+		flag := cmd.Flags().Lookup(name)
+		value := flag.Value.String()
+		// StringSlice 的字符串形式带有方括号，还原为逗号分隔
+		if flag.Value.Type() == "stringSlice" {
+			value = strings.Trim(value, "[]")
+		}
+		config[name] = value
+	}
+	return config
+}
+
+// systemFingerprint 根据CPU和主机标识计算硬件指纹
+func systemFingerprint(cpu *types.CPUInfo) string {
+	hostname, _ := os.Hostname()
+	return suite.Fingerprint(
+		hostname,
+		runtime.GOARCH,
+		cpu.ModelName,
+		strconv.Itoa(cpu.PhysicalCores),
+		strconv.Itoa(cpu.LogicalCores),
+	)
+}
+
+// contendedStages 返回开始前系统未能空闲的阶段，按套件顺序排列
+func contendedStages(report *types.Report) []string {
+	var stages []string
+	for _, stage := range report.Stages {
+		if load, ok := report.Metadata.BackgroundLoad[stage.Name]; ok && load.Contended {
+			stages = append(stages, stage.Name)
+		}
+	}
+	return stages
+}
+
+// finished 判断所有选中的阶段是否均已完成
+func finished(report *types.Report) bool {
+	for _, stage := range report.Stages {
+		if stage.Status == suite.StatusFailed || stage.Status == suite.StatusInterrupted {
+			return false
+		}
+	}
+	return !report.Metadata.Partial
+}
+
+// displayReport 打印套件运行结果和辛烷值评级，stolen 是 CPU steal 超过阈值的基准测试
+func displayReport(report *types.Report, stolen []string) {
+	fmt.Println("\n🏁 OCTANE PERFORMANCE RATING 🏁")
+	fmt.Printf("Test ID: %s\n", report.Metadata.TestID)
+	fmt.Printf("Duration: %s\n", report.Metadata.Duration)
+	if report.Metadata.Partial {
+		fmt.Println("⚠ Partial results: the run was interrupted")
+	}
+	if report.Metadata.ContainerLimited {
+		host := report.SystemInfo.Host
+		fmt.Printf("⚠ Container-limited (%s): %.1f CPUs, %d MB memory available to octane\n",
+			containerName(host.Container), host.CPULimit, host.MemoryLimit)
+	}
+	if report.Metadata.Contended {
+		fmt.Printf("⚠ Contended: the system was busy before %s\n", strings.Join(contendedStages(report), ", "))
+	}
+	if report.Metadata.Unreliable {
+		fmt.Printf("⚠ Unreliable: CPU steal above %.0f%% during %s\n",
+			viper.GetFloat64("tests.steal_threshold"), strings.Join(stolen, ", "))
+	}
+
+	fmt.Println("\n📋 Stages:")
+	for _, stage := range report.Stages {
+		switch stage.Status {
+		case suite.StatusCompleted:
+			fmt.Printf("  ✓ %-8s %s\n", stage.Name, stage.Duration)
+		case suite.StatusFailed:
+			fmt.Printf("  ✗ %-8s %s\n", stage.Name, stage.Error)
+		case suite.StatusInterrupted:
+			fmt.Printf("  ⚠ %-8s interrupted\n", stage.Name)
+		default:
+			fmt.Printf("  - %-8s %s\n", stage.Name, stage.Status)
+		}
+	}
+
+	if len(report.TestResults.Plugins) > 0 {
+		fmt.Println("\n🔌 Plugins:")
+		for _, p := range report.TestResults.Plugins {
+			if p.Error != "" {
+				fmt.Printf("  ✗ %s: %s\n", p.Name, p.Error)
+				continue
+			}
+			fmt.Printf("  ✓ %s\n", p.Name)
+			for _, m := range p.Metrics {
+				fmt.Printf("      %-16s %.2f %s\n", m.Name, m.Value, m.Unit)
+			}
+		}
+	}
+
+	if len(report.TestResults.GPUTelemetry) > 0 {
+		fmt.Println("\n🎮 GPU telemetry:")
+		for _, stage := range report.Stages {
+			sample, ok := report.TestResults.GPUTelemetry[stage.Name]
+			if !ok {
+				continue
+			}
+			if !sample.Available {
+				fmt.Printf("  %-8s unavailable: %s\n", stage.Name, sample.Reason)
+				continue
+			}
+			fmt.Printf("  %-8s %.0f°C avg, %.0f°C max  %.0f W avg, %.0f W peak  %.0f%% busy\n", stage.Name,
+				sample.Temperature.Average, sample.Temperature.Max, sample.Power.Average, sample.Power.Peak, sample.Utilization)
+		}
+	}
+
+	if len(report.OctaneRatings.Breakdown) > 0 {
+		fmt.Println("\n⛽ Component Octane:")
+		names := make([]string, 0, len(report.OctaneRatings.Breakdown))
+		for name := range report.OctaneRatings.Breakdown {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			rating := report.OctaneRatings.Breakdown[name]
+			fmt.Printf("  %-12s %.1f RON  %s\n", name, rating.RON, rating.Color)
+			for _, warning := range rating.Warnings {
+				fmt.Printf("    ⚠ %s\n", warning)
+			}
+		}
+	}
+
+	if len(report.Recommendations.Hardware) > 0 {
+		fmt.Println("\n🔧 Recommendations:")
+		for _, r := range report.Recommendations.Hardware {
+			fmt.Printf("  • %s\n", r.Suggestion)
+		}
+	}
+
+	overall := report.OctaneRatings.Overall
+	fmt.Printf("\nOverall System Octane: %.1f RON (%s)\n", overall.RON, overall.Grade)
+	fmt.Printf("Description: %s\n", overall.Description)
+}
+
+// saveReport 将报告写入 YAML 文件并记录到数据库
+func saveReport(report *types.Report, output string) {
+	if output == "" {
+		output = filepath.Join(viper.GetString("global.temp_dir"), report.Metadata.TestID+".yaml")
+	}
+	if err := os.MkdirAll(filepath.Dir(output), 0755); err != nil {
+		fmt.Printf("Error creating report directory: %v\n", err)
+	} else if err := yaml.WriteYAML(output, report); err != nil {
+		fmt.Printf("Error writing report: %v\n", err)
+	} else {
+		fmt.Printf("\n📄 Report saved to %s\n", output)
+	}
+
+	dbPath := expandHome(viper.GetString("global.database"))
+	if err := os.MkdirAll(filepath.Dir(dbPath), 0755); err != nil {
+		fmt.Printf("Error creating database directory: %v\n", err)
+		return
+	}
+	db, err := database.NewDatabase(dbPath)
+	if err != nil {
+		fmt.Printf("Error opening database: %v\n", err)
+		return
+	}
+	defer db.Close()
+
+	if err := db.SaveReport(report); err != nil {
+		fmt.Printf("Error saving results: %v\n", err)
+	}
+}
+
+// containerName describes the container runtime, or the bare cgroup when none was detected
+func containerName(container string) string {
+	if container == "" {
+		return "cgroup"
+	}
+	return container
+}
@@ -1,7 +1,18 @@
 package main
 
-import "octane/cmd"
+import (
+	"embed"
+
+	"octane/cmd"
+)
+
+// assets holds the Python test scripts and default configs so an installed binary
+// does not depend on the source tree
+//
+//go:embed scripts/python configs
+var assets embed.FS
 
 func main() {
+	cmd.SetAssets(assets)
 	cmd.Execute()
 }
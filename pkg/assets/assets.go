@@ -0,0 +1,201 @@
+package assets
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// 差异状态
+const (
+	StatusMissing  = "missing"
+	StatusModified = "modified"
+	StatusExtra    = "extra" // 磁盘上有但二进制中没有的文件
+)
+
+// pycache 是 Python 运行脚本时写入的字节码缓存目录，不视为多余文件
+const pycache = "__pycache__"
+
+// Change 描述嵌入文件与磁盘文件的一处差异
+type Change struct {
+	Path   string `json:"path"`
+	Status string `json:"status"`
+}
+
+// Hashes 返回文件系统中每个文件内容的 sha256
+func Hashes(fsys fs.FS) (map[string]string, error) {
+	hashes := make(map[string]string)
+	err := fs.WalkDir(fsys, ".", func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		data, err := fs.ReadFile(fsys, path)
+		if err != nil {
+			return err
+		}
+		hashes[path] = hashBytes(data)
+		return nil
+	})
+	return hashes, err
+}
+
+// Dir 返回当前用户的解压目录 <用户缓存目录>/octane。
+// 解压的脚本会被执行，因此不能放在 /tmp 等其他用户可写的共享目录中。
+func Dir() (string, error) {
+	cache, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(cache, "octane"), nil
+}
+
+// Extract 将嵌入的文件解压到 dest，内容哈希一致的文件保持不动，过期的副本被替换，多余的文件被删除。
+// dest 不存在时以 0700 创建；已存在时必须属于当前用户且其他用户无权访问，否则拒绝解压。
+func Extract(fsys fs.FS, dest string) error {
+	if err := os.MkdirAll(dest, 0700); err != nil {
+		return err
+	}
+	if err := checkPrivate(dest); err != nil {
+		return err
+	}
+	changes, err := Diff(fsys, dest)
+	if err != nil {
+		return err
+	}
+
+	for _, change := range changes {
+		path := filepath.Join(dest, filepath.FromSlash(change.Path))
+		if change.Status == StatusExtra {
+			if err := os.Remove(path); err != nil {
+				return fmt.Errorf("removing %s: %v", change.Path, err)
+			}
+			continue
+		}
+		data, err := fs.ReadFile(fsys, change.Path)
+		if err != nil {
+			return err
+		}
+		if err := writeFile(path, data); err != nil {
+			return fmt.Errorf("extracting %s: %v", change.Path, err)
+		}
+	}
+	return nil
+}
+
+// Diff 比较嵌入的文件与 dest 中已解压的副本，按路径排序返回差异。
+// 嵌入的顶层目录（scripts、configs）中多出的文件报告为 extra，__pycache__ 除外。
+func Diff(fsys fs.FS, dest string) ([]Change, error) {
+	hashes, err := Hashes(fsys)
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []Change
+	for path, hash := range hashes {
+		data, err := os.ReadFile(filepath.Join(dest, filepath.FromSlash(path)))
+		switch {
+		case os.IsNotExist(err):
+			changes = append(changes, Change{Path: path, Status: StatusMissing})
+		case err != nil:
+			return nil, err
+		case hashBytes(data) != hash:
+			changes = append(changes, Change{Path: path, Status: StatusModified})
+		}
+	}
+
+	extra, err := extraFiles(fsys, dest, hashes)
+	if err != nil {
+		return nil, err
+	}
+	changes = append(changes, extra...)
+
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Path < changes[j].Path })
+	return changes, nil
+}
+
+// extraFiles 返回 dest 中嵌入的顶层目录下不属于 hashes 的文件，包括符号链接
+func extraFiles(fsys fs.FS, dest string, hashes map[string]string) ([]Change, error) {
+	roots, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []Change
+	for _, root := range roots {
+		if !root.IsDir() {
+			continue
+		}
+		base := filepath.Join(dest, root.Name())
+		err := filepath.WalkDir(base, func(path string, d fs.DirEntry, err error) error {
+			switch {
+			case errors.Is(err, fs.ErrNotExist):
+				return nil
+			case err != nil:
+				return err
+			case d.IsDir() && d.Name() == pycache:
+				return filepath.SkipDir
+			case d.IsDir():
+				return nil
+			}
+			rel, err := filepath.Rel(dest, path)
+			if err != nil {
+				return err
+			}
+			if _, ok := hashes[filepath.ToSlash(rel)]; !ok {
+				changes = append(changes, Change{Path: filepath.ToSlash(rel), Status: StatusExtra})
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return changes, nil
+}
+
+// checkPrivate 确认 dir 是当前用户所有、其他用户无权访问的目录，而不是指向别处的符号链接
+func checkPrivate(dir string) error {
+	info, err := os.Lstat(dir)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", dir)
+	}
+	return checkOwner(dir, info)
+}
+
+// writeFile 先写临时文件再重命名，避免并发运行读到半个文件
+func writeFile(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+func hashBytes(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
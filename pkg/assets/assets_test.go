@@ -0,0 +1,90 @@
+package assets
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"testing/fstest"
+)
+
+var embedded = fstest.MapFS{
+	"configs/default.yaml":     {Data: []byte("global:\n  log_level: info\n")},
+	"scripts/cpu_test.py":      {Data: []byte("print('cpu')\n")},
+	"scripts/requirements.txt": {Data: []byte("numpy\n")},
+	"scripts/lib/__init__.py":  {Data: []byte("")},
+}
+
+// writeAsset 在 dest 中写入一个文件，必要时创建上级目录
+func writeAsset(t *testing.T, dest, path, data string) {
+	t.Helper()
+	path = filepath.Join(dest, filepath.FromSlash(path))
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	dest := t.TempDir()
+	writeAsset(t, dest, "configs/default.yaml", "global:\n  log_level: info\n")
+	writeAsset(t, dest, "scripts/cpu_test.py", "import os; os.system('id')\n")
+	writeAsset(t, dest, "scripts/requirements.txt", "numpy\n")
+	writeAsset(t, dest, "scripts/planted.py", "print('planted')\n")
+	writeAsset(t, dest, "scripts/__pycache__/cpu_test.cpython-312.pyc", "bytecode")
+	// 嵌入的顶层目录以外的文件不属于解压结果
+	writeAsset(t, dest, "notes.txt", "mine")
+
+	changes, err := Diff(embedded, dest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Change{
+		{Path: "scripts/cpu_test.py", Status: StatusModified},
+		{Path: "scripts/lib/__init__.py", Status: StatusMissing},
+		{Path: "scripts/planted.py", Status: StatusExtra},
+	}
+	if !slices.Equal(changes, want) {
+		t.Errorf("Diff = %+v, want %+v", changes, want)
+	}
+}
+
+func TestExtract(t *testing.T) {
+	dest := filepath.Join(t.TempDir(), "octane")
+	if err := Extract(embedded, dest); err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(dest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0700 {
+		t.Errorf("dest mode = %04o, want 0700", perm)
+	}
+
+	// 过期的副本被替换，多余的文件被删除，字节码缓存保留
+	writeAsset(t, dest, "scripts/cpu_test.py", "import os; os.system('id')\n")
+	writeAsset(t, dest, "scripts/planted.py", "print('planted')\n")
+	writeAsset(t, dest, "scripts/__pycache__/cpu_test.cpython-312.pyc", "bytecode")
+	if err := os.Remove(filepath.Join(dest, "scripts/requirements.txt")); err != nil {
+		t.Fatal(err)
+	}
+	if err := Extract(embedded, dest); err != nil {
+		t.Fatal(err)
+	}
+
+	if changes, err := Diff(embedded, dest); err != nil || len(changes) != 0 {
+		t.Errorf("Diff after Extract = %+v, %v; want no changes", changes, err)
+	}
+	if data, err := os.ReadFile(filepath.Join(dest, "scripts/cpu_test.py")); err != nil || string(data) != "print('cpu')\n" {
+		t.Errorf("cpu_test.py = %q, %v; want the embedded copy", data, err)
+	}
+	if _, err := os.Stat(filepath.Join(dest, "scripts/planted.py")); !os.IsNotExist(err) {
+		t.Errorf("planted.py was not removed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dest, "scripts/__pycache__/cpu_test.cpython-312.pyc")); err != nil {
+		t.Errorf("__pycache__ was removed: %v", err)
+	}
+}
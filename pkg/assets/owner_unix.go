@@ -0,0 +1,21 @@
+//go:build !windows
+
+package assets
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"syscall"
+)
+
+// checkOwner 确认目录属于当前用户且权限为 0700
+func checkOwner(dir string, info fs.FileInfo) error {
+	if stat, ok := info.Sys().(*syscall.Stat_t); !ok || int(stat.Uid) != os.Getuid() {
+		return fmt.Errorf("%s is not owned by the current user; remove it or choose another directory", dir)
+	}
+	if perm := info.Mode().Perm(); perm&0077 != 0 {
+		return fmt.Errorf("%s has mode %04o, want 0700; run chmod 700 %s", dir, perm, dir)
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+//go:build !windows
+
+package assets
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestExtractRefusesSharedDir(t *testing.T) {
+	dest := filepath.Join(t.TempDir(), "octane")
+	if err := os.Mkdir(dest, 0700); err != nil {
+		t.Fatal(err)
+	}
+	// Mkdir 受 umask 影响，显式设置权限
+	if err := os.Chmod(dest, 0777); err != nil {
+		t.Fatal(err)
+	}
+	err := Extract(embedded, dest)
+	if err == nil || !strings.Contains(err.Error(), "want 0700") {
+		t.Fatalf("Extract into a 0777 directory: got %v, want a mode error", err)
+	}
+	if entries, _ := os.ReadDir(dest); len(entries) != 0 {
+		t.Errorf("Extract wrote into a refused directory: %v", entries)
+	}
+}
+
+func TestExtractRefusesSymlink(t *testing.T) {
+	target := t.TempDir()
+	dest := filepath.Join(t.TempDir(), "octane")
+	if err := os.Symlink(target, dest); err != nil {
+		t.Fatal(err)
+	}
+	if err := Extract(embedded, dest); err == nil || !strings.Contains(err.Error(), "not a directory") {
+		t.Errorf("Extract through a symlink: got %v, want an error", err)
+	}
+}
+
+func TestExtractRefusesForeignOwner(t *testing.T) {
+	// 只有 root 能把目录交给其他用户
+	if os.Getuid() != 0 {
+		t.Skip("changing the owner needs root")
+	}
+	dest := filepath.Join(t.TempDir(), "octane")
+	if err := os.Mkdir(dest, 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chown(dest, 65534, 65534); err != nil {
+		t.Fatal(err)
+	}
+	if err := Extract(embedded, dest); err == nil || !strings.Contains(err.Error(), "not owned by the current user") {
+		t.Errorf("Extract into a foreign directory: got %v, want an ownership error", err)
+	}
+}
@@ -0,0 +1,10 @@
+//go:build windows
+
+package assets
+
+import "io/fs"
+
+// checkOwner 在 Windows 上不检查，用户缓存目录位于只有当前用户可写的 %LocalAppData% 中
+func checkOwner(dir string, info fs.FileInfo) error {
+	return nil
+}
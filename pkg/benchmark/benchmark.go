@@ -0,0 +1,106 @@
+package benchmark
+
+import (
+	"context"
+	"fmt"
+	"octane/pkg/types"
+	"time"
+)
+
+// Metric 定义基准测试产生的单项指标
+type Metric struct {
+	Name           string  `json:"name" yaml:"name"`
+	Value          float64 `json:"value" yaml:"value"`
+	Unit           string  `json:"unit" yaml:"unit"`
+	HigherIsBetter bool    `json:"higher_is_better" yaml:"higher_is_better"`
+}
+
+// Result 定义一次基准测试的结果
+type Result struct {
+	Benchmark string        `json:"benchmark" yaml:"benchmark"`
+	Category  string        `json:"category" yaml:"category"`
+	Duration  time.Duration `json:"duration" yaml:"duration"`
+	Metrics   []Metric      `json:"metrics" yaml:"metrics"`
+
+	Steal types.StealSample `json:"steal" yaml:"steal"` // 测试期间的 CPU steal 和 iowait
+	Power types.PowerSample `json:"power" yaml:"power"` // 测试期间的 RAPL 能耗，未开启功耗采样时为零值
+}
+
+// Value 返回指定指标的值，不存在时返回0
+func (r *Result) Value(name string) float64 {
+	for _, m := range r.Metrics {
+		if m.Name == name {
+			return m.Value
+		}
+	}
+	return 0
+}
+
+// Options 定义基准测试的运行参数
+type Options struct {
+	Threads   int           // 线程数，0 表示自动
+	Duration  time.Duration // 运行时长，0 表示使用默认值
+	Size      int           // 工作集或测试文件大小（字节）
+	Dir       string        // 测试目录
+	ScriptDir string        // Python 脚本目录
+}
+
+// Benchmark 定义所有基准测试需要实现的接口
+type Benchmark interface {
+	Name() string
+	Category() string
+	Description() string
+	DefaultDuration() time.Duration
+	Run(ctx context.Context, opts Options) (*Result, error)
+}
+
+// RunFunc 是基准测试的执行函数
+type RunFunc func(ctx context.Context, opts Options) ([]Metric, error)
+
+// funcBenchmark 用函数实现 Benchmark 接口
+type funcBenchmark struct {
+	name        string
+	category    string
+	description string
+	duration    time.Duration
+	run         RunFunc
+}
+
+// New 创建一个由函数实现的基准测试
+func New(name, category, description string, duration time.Duration, run RunFunc) Benchmark {
+	return &funcBenchmark{
+		name:        name,
+		category:    category,
+		description: description,
+		duration:    duration,
+		run:         run,
+	}
+}
+
+func (b *funcBenchmark) Name() string                   { return b.name }
+func (b *funcBenchmark) Category() string               { return b.category }
+func (b *funcBenchmark) Description() string            { return b.description }
+func (b *funcBenchmark) DefaultDuration() time.Duration { return b.duration }
+
+// Run 执行基准测试并记录耗时
+func (b *funcBenchmark) Run(ctx context.Context, opts Options) (*Result, error) {
+	if opts.Duration <= 0 {
+		opts.Duration = b.duration
+	}
+
+	start := time.Now()
+	metrics, err := b.run(ctx, opts)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", b.name, err)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return &Result{
+		Benchmark: b.name,
+		Category:  b.category,
+		Duration:  time.Since(start),
+		Metrics:   metrics,
+	}, nil
+}
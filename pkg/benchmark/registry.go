@@ -0,0 +1,124 @@
+package benchmark
+
+import (
+	"context"
+	"fmt"
+	"octane/pkg/monitor"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	mu       sync.RWMutex
+	registry = make(map[string]Benchmark)
+)
+
+// Register 注册一个基准测试，名称重复时 panic
+func Register(b Benchmark) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if _, exists := registry[b.Name()]; exists {
+		panic(fmt.Sprintf("benchmark %s registered twice", b.Name()))
+	}
+	registry[b.Name()] = b
+}
+
+// Get 按名称查找基准测试
+func Get(name string) (Benchmark, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	b, ok := registry[name]
+	return b, ok
+}
+
+// List 返回所有已注册的基准测试，按名称排序
+func List() []Benchmark {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	list := make([]Benchmark, 0, len(registry))
+	for _, b := range registry {
+		list = append(list, b)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name() < list[j].Name() })
+	return list
+}
+
+// Select 按名称或路径前缀选择基准测试。
+// "cpu" 选中所有 cpu/ 下的测试，"cpu/crypto" 选中该组，完整名称只选中一个。
+func Select(pattern string) ([]Benchmark, error) {
+	pattern = strings.Trim(pattern, "/")
+
+	var selected []Benchmark
+	for _, b := range List() {
+		if b.Name() == pattern || strings.HasPrefix(b.Name(), pattern+"/") {
+			selected = append(selected, b)
+		}
+	}
+	if len(selected) == 0 {
+		return nil, fmt.Errorf("no benchmark matches %q", pattern)
+	}
+	return selected, nil
+}
+
+// Run 运行单个基准测试，并采样测试期间的 CPU steal、iowait 和（开启时）RAPL 能耗。
+// 采样结果写入 Result，并记录到上下文中的 monitor.Recorder。
+func Run(ctx context.Context, b Benchmark, opts Options) (*Result, error) {
+	var power *monitor.PowerSampler
+	if monitor.PowerMonitoring(ctx) {
+		power = monitor.StartPowerSampler("/")
+	}
+	sampler := monitor.StartStealSampler("/")
+	result, err := b.Run(ctx, opts)
+	sample := sampler.Stop()
+	if power != nil {
+		energy := power.Stop()
+		if err == nil {
+			result.Power = energy
+			monitor.RecordPower(ctx, b.Name(), energy)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	result.Steal = sample
+	monitor.Record(ctx, b.Name(), sample)
+	return result, nil
+}
+
+// Progress 在每个基准测试开始和结束时被调用，result 为空表示开始
+type Progress func(b Benchmark, result *Result)
+
+// RunAll 依次运行基准测试，total 按各测试默认时长的比例分配
+func RunAll(ctx context.Context, list []Benchmark, opts Options, total time.Duration, progress Progress) ([]*Result, error) {
+	var sum time.Duration
+	for _, b := range list {
+		sum += b.DefaultDuration()
+	}
+
+	results := make([]*Result, 0, len(list))
+	for _, b := range list {
+		runOpts := opts
+		if total > 0 && sum > 0 {
+			runOpts.Duration = time.Duration(float64(total) * float64(b.DefaultDuration()) / float64(sum))
+		}
+
+		if progress != nil {
+			progress(b, nil)
+		}
+		result, err := Run(ctx, b, runOpts)
+		if err != nil {
+			return results, err
+		}
+		if progress != nil {
+			progress(b, result)
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
@@ -0,0 +1,230 @@
+package cgroup
+
+import (
+	"bufio"
+	"math"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// unlimitedMemory 是 cgroup v1 表示不限制内存的阈值，实际值接近 math.MaxInt64 并按页对齐
+const unlimitedMemory = 1 << 62
+
+// Limits 定义当前进程所在 cgroup 的资源限制，0 表示不限制
+type Limits struct {
+	Version int     // cgroup 版本，1 或 2，0 表示未检测到
+	CPUs    float64 // cpu.max 或 cfs_quota/cfs_period 换算的 CPU 数
+	CPUSet  int     // cpuset 中可用的 CPU 数
+	Memory  int64   // 内存上限（字节）
+}
+
+// EffectiveCPUs 返回可用的 CPU 数：CPU 配额、cpuset 与 online CPU 数中的最小值，至少为 1
+func (l Limits) EffectiveCPUs(online int) int {
+	cpus := online
+	if l.CPUSet > 0 && l.CPUSet < cpus {
+		cpus = l.CPUSet
+	}
+	if l.CPUs > 0 && int(math.Ceil(l.CPUs)) < cpus {
+		cpus = int(math.Ceil(l.CPUs))
+	}
+	return max(cpus, 1)
+}
+
+// Limited 判断 CPU 或内存是否受到低于主机资源的限制
+func (l Limits) Limited(online int, totalMemory int64) bool {
+	return l.EffectiveCPUs(online) < online || (l.Memory > 0 && (totalMemory == 0 || l.Memory < totalMemory))
+}
+
+// Detect 读取 root 下 /proc/self/cgroup 和 /sys/fs/cgroup 中的限制，root 通常为 "/"
+func Detect(root string) Limits {
+	paths := selfCgroups(root)
+	mount := filepath.Join(root, "sys/fs/cgroup")
+
+	if _, err := os.Stat(filepath.Join(mount, "cgroup.controllers")); err == nil {
+		return detectV2(mount, paths[""])
+	}
+	if len(paths) > 0 {
+		return detectV1(mount, paths)
+	}
+	return Limits{}
+}
+
+// detectV2 沿 cgroup 层级向上查找最严格的限制
+func detectV2(mount, path string) Limits {
+	limits := Limits{Version: 2}
+	for _, dir := range ancestors(mount, path) {
+		if quota, period, ok := parseCPUMax(readString(filepath.Join(dir, "cpu.max"))); ok {
+			cpus := quota / period
+			if limits.CPUs == 0 || cpus < limits.CPUs {
+				limits.CPUs = cpus
+			}
+		}
+		if n := countCPUs(readString(filepath.Join(dir, "cpuset.cpus.effective"))); n > 0 && (limits.CPUSet == 0 || n < limits.CPUSet) {
+			limits.CPUSet = n
+		}
+		if memory, err := strconv.ParseInt(readString(filepath.Join(dir, "memory.max")), 10, 64); err == nil {
+			if limits.Memory == 0 || memory < limits.Memory {
+				limits.Memory = memory
+			}
+		}
+	}
+	return limits
+}
+
+// detectV1 读取 cpu、cpuset 和 memory 控制器的限制
+func detectV1(mount string, paths map[string]string) Limits {
+	limits := Limits{Version: 1}
+
+	for _, dir := range controllerDirs(mount, paths, "cpu") {
+		quota, err1 := strconv.ParseFloat(readString(filepath.Join(dir, "cpu.cfs_quota_us")), 64)
+		period, err2 := strconv.ParseFloat(readString(filepath.Join(dir, "cpu.cfs_period_us")), 64)
+		if err1 == nil && err2 == nil && quota > 0 && period > 0 {
+			limits.CPUs = quota / period
+			break
+		}
+	}
+	for _, dir := range controllerDirs(mount, paths, "cpuset") {
+		if n := countCPUs(readString(filepath.Join(dir, "cpuset.effective_cpus"))); n > 0 {
+			limits.CPUSet = n
+			break
+		}
+		if n := countCPUs(readString(filepath.Join(dir, "cpuset.cpus"))); n > 0 {
+			limits.CPUSet = n
+			break
+		}
+	}
+	for _, dir := range controllerDirs(mount, paths, "memory") {
+		if memory, err := strconv.ParseInt(readString(filepath.Join(dir, "memory.limit_in_bytes")), 10, 64); err == nil {
+			if memory < unlimitedMemory {
+				limits.Memory = memory
+			}
+			break
+		}
+	}
+	return limits
+}
+
+// selfCgroups 解析 /proc/self/cgroup，返回控制器到路径的映射，v2 的统一层级键为空字符串
+func selfCgroups(root string) map[string]string {
+	paths := make(map[string]string)
+
+	file, err := os.Open(filepath.Join(root, "proc/self/cgroup"))
+	if err != nil {
+		return paths
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		// 格式为 hierarchy-ID:controller-list:path
+		parts := strings.SplitN(scanner.Text(), ":", 3)
+		if len(parts) != 3 {
+			continue
+		}
+		if parts[1] == "" {
+			paths[""] = parts[2]
+			continue
+		}
+		for _, controller := range strings.Split(parts[1], ",") {
+			paths[controller] = parts[2]
+		}
+	}
+	return paths
+}
+
+// controllerDirs 返回 v1 控制器可能的目录：容器内 cgroup 命名空间通常把自身挂在控制器根目录
+func controllerDirs(mount string, paths map[string]string, controller string) []string {
+	var dirs []string
+	for _, name := range []string{controller, controller + ",cpuacct", "cpu,cpuacct", "cpuacct,cpu"} {
+		base := filepath.Join(mount, name)
+		if _, err := os.Stat(base); err != nil {
+			continue
+		}
+		if path, ok := paths[controller]; ok {
+			dirs = append(dirs, filepath.Join(base, path))
+		}
+		dirs = append(dirs, base)
+	}
+	return dirs
+}
+
+// ancestors 返回从 cgroup 自身目录到挂载点的所有目录
+func ancestors(mount, path string) []string {
+	dirs := []string{mount}
+	current := mount
+	for _, part := range strings.Split(strings.Trim(path, "/"), "/") {
+		if part == "" {
+			continue
+		}
+		current = filepath.Join(current, part)
+		dirs = append(dirs, current)
+	}
+	return dirs
+}
+
+// parseCPUMax 解析 cpu.max 的 "quota period"，quota 为 max 时表示不限制
+func parseCPUMax(value string) (float64, float64, bool) {
+	fields := strings.Fields(value)
+	if len(fields) != 2 || fields[0] == "max" {
+		return 0, 0, false
+	}
+	quota, err1 := strconv.ParseFloat(fields[0], 64)
+	period, err2 := strconv.ParseFloat(fields[1], 64)
+	if err1 != nil || err2 != nil || quota <= 0 || period <= 0 {
+		return 0, 0, false
+	}
+	return quota, period, true
+}
+
+// countCPUs 统计 cpuset 列表中的 CPU 数，例如 "0-3,8,10-11" 为 7
+func countCPUs(list string) int {
+	count := 0
+	for _, part := range strings.Split(strings.TrimSpace(list), ",") {
+		if part == "" {
+			continue
+		}
+		lo, hi, isRange := strings.Cut(part, "-")
+		start, err := strconv.Atoi(lo)
+		if err != nil {
+			return 0
+		}
+		end := start
+		if isRange {
+			if end, err = strconv.Atoi(hi); err != nil || end < start {
+				return 0
+			}
+		}
+		count += end - start + 1
+	}
+	return count
+}
+
+func readString(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+var (
+	selfOnce   sync.Once
+	selfLimits Limits
+)
+
+// Self 返回当前进程的 cgroup 限制，只检测一次
+func Self() Limits {
+	selfOnce.Do(func() {
+		selfLimits = Detect("/")
+	})
+	return selfLimits
+}
+
+// CPUs 返回当前进程实际可用的 CPU 数，用作线程数的默认值
+func CPUs() int {
+	return Self().EffectiveCPUs(runtime.NumCPU())
+}
@@ -0,0 +1,87 @@
+package cgroup
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		root    string
+		want    Limits
+		online  int
+		cpus    int  // 对应 CPUs() 在 online 个 CPU 的主机上的返回值
+		limited bool // 主机内存为 64 GiB 时
+	}{
+		// cpu.max 为 "max 100000"、memory.max 为 "max" 时不限制，只有 cpuset 生效
+		{"v2-unlimited", Limits{Version: 2, CPUSet: 7}, 16, 7, true},
+		{"v2-unlimited", Limits{Version: 2, CPUSet: 7}, 4, 4, false},
+		// 沿层级取最严格的限制：自身的 1.5 CPU、4 个 CPU 的 cpuset 和父级的 4 GiB 内存
+		{"v2-limited", Limits{Version: 2, CPUs: 1.5, CPUSet: 4, Memory: 4 << 30}, 32, 2, true},
+		// cfs_quota_us 为 -1，memory.limit_in_bytes 为按页对齐的 MaxInt64，均表示不限制
+		{"v1-unlimited", Limits{Version: 1, CPUSet: 7}, 8, 7, true},
+		{"v1-limited", Limits{Version: 1, CPUs: 2.5, CPUSet: 4, Memory: 512 << 20}, 16, 3, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.root, func(t *testing.T) {
+			limits := Detect(filepath.Join("testdata", tt.root))
+			if limits != tt.want {
+				t.Errorf("Detect = %+v, want %+v", limits, tt.want)
+			}
+			if cpus := limits.EffectiveCPUs(tt.online); cpus != tt.cpus {
+				t.Errorf("EffectiveCPUs(%d) = %d, want %d", tt.online, cpus, tt.cpus)
+			}
+			if limited := limits.Limited(tt.online, 64<<30); limited != tt.limited {
+				t.Errorf("Limited(%d) = %v, want %v", tt.online, limited, tt.limited)
+			}
+		})
+	}
+}
+
+func TestDetectWithoutCgroups(t *testing.T) {
+	if limits := Detect(t.TempDir()); limits != (Limits{}) {
+		t.Errorf("Detect = %+v, want no limits", limits)
+	}
+	if cpus := (Limits{}).EffectiveCPUs(12); cpus != 12 {
+		t.Errorf("EffectiveCPUs = %d, want 12", cpus)
+	}
+}
+
+func TestCountCPUs(t *testing.T) {
+	tests := map[string]int{
+		"0":           1,
+		"0-3":         4,
+		"0-3,8,10-11": 7,
+		"0-63\n":      64,
+		"":            0,
+		"3-1":         0,
+		"a-b":         0,
+	}
+	for list, want := range tests {
+		if got := countCPUs(list); got != want {
+			t.Errorf("countCPUs(%q) = %d, want %d", list, got, want)
+		}
+	}
+}
+
+func TestParseCPUMax(t *testing.T) {
+	tests := []struct {
+		value  string
+		quota  float64
+		period float64
+		ok     bool
+	}{
+		{"max 100000", 0, 0, false},
+		{"max", 0, 0, false},
+		{"150000 100000", 150000, 100000, true},
+		{"50000 100000", 50000, 100000, true},
+		{"0 100000", 0, 0, false},
+		{"", 0, 0, false},
+	}
+	for _, tt := range tests {
+		quota, period, ok := parseCPUMax(tt.value)
+		if quota != tt.quota || period != tt.period || ok != tt.ok {
+			t.Errorf("parseCPUMax(%q) = %g, %g, %v", tt.value, quota, period, ok)
+		}
+	}
+}
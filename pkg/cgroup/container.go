@@ -0,0 +1,64 @@
+package cgroup
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// 容器运行时名称
+const (
+	Docker     = "docker"
+	Kubernetes = "kubernetes"
+	Podman     = "podman"
+	LXC        = "lxc"
+	Nspawn     = "systemd-nspawn"
+)
+
+// Container 检测进程运行在哪种容器中，不在容器中时返回空字符串。
+// Kubernetes 优先于其底层运行时返回。
+func Container(root string) string {
+	exists := func(path string) bool {
+		_, err := os.Stat(filepath.Join(root, path))
+		return err == nil
+	}
+
+	if exists("var/run/secrets/kubernetes.io/serviceaccount") {
+		return Kubernetes
+	}
+	cgroups := readString(filepath.Join(root, "proc/1/cgroup"))
+	if strings.Contains(cgroups, "kubepods") {
+		return Kubernetes
+	}
+
+	// systemd 和 LXC、nspawn、podman 都会写入此文件
+	switch value := readString(filepath.Join(root, "run/systemd/container")); {
+	case value == "systemd-nspawn":
+		return Nspawn
+	case strings.HasPrefix(value, "lxc"):
+		return LXC
+	case value != "":
+		return value
+	}
+
+	if exists(".dockerenv") {
+		return Docker
+	}
+	if exists("run/.containerenv") {
+		return Podman
+	}
+
+	// 没有上述标记时从 PID 1 的环境变量和 cgroup 路径推断
+	for _, env := range strings.Split(readString(filepath.Join(root, "proc/1/environ")), "\x00") {
+		if value, ok := strings.CutPrefix(env, "container="); ok && value != "" {
+			return value
+		}
+	}
+	switch {
+	case strings.Contains(cgroups, "docker"):
+		return Docker
+	case strings.Contains(cgroups, "lxc"):
+		return LXC
+	}
+	return ""
+}
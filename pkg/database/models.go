@@ -7,6 +7,7 @@ import (
 // TestResult 定义测试结果模型
 type TestResult struct {
     ID        uint   `gorm:"primaryKey"`
+    TestID    string `gorm:"index"`    // 所属测试套件 ID
     TestType  string `gorm:"not null"` // 测试类型，例如 CPU、内存、存储等
     Score     float64 `gorm:"not null"` // 测试得分
     Timestamp string `gorm:"not null"` // 测试时间戳
@@ -0,0 +1,55 @@
+package database
+
+import (
+	"fmt"
+	"octane/pkg/types"
+	"strings"
+)
+
+// SaveReport 将测试报告的评分和系统信息写入数据库
+func (db *Database) SaveReport(report *types.Report) error {
+	if err := InitializeDatabase(db.Connection); err != nil {
+		return err
+	}
+
+	records := []TestResult{{
+		TestID:    report.Metadata.TestID,
+		TestType:  "overall",
+		Score:     report.OctaneRatings.Overall.RON,
+		Timestamp: report.Metadata.Timestamp,
+	}}
+	for component, rating := range report.OctaneRatings.Breakdown {
+		records = append(records, TestResult{
+			TestID:    report.Metadata.TestID,
+			TestType:  component,
+			Score:     rating.RON,
+			Timestamp: report.Metadata.Timestamp,
+		})
+	}
+	if err := db.Connection.Create(&records).Error; err != nil {
+		return fmt.Errorf("failed to save test results: %v", err)
+	}
+
+	info := report.SystemInfo
+	gpus := make([]string, 0, len(info.GPU))
+	for _, gpu := range info.GPU {
+		gpus = append(gpus, gpu.Name)
+	}
+	storage := make([]string, 0, len(info.Storage))
+	for _, device := range info.Storage {
+		storage = append(storage, device.Name)
+	}
+	system := SystemInfo{
+		Hostname: report.Metadata.Hostname,
+		OS:       info.Host.OS,
+		CPU:      info.CPU.ModelName,
+		Memory:   fmt.Sprintf("%d MB", info.Memory.Total),
+		Storage:  strings.Join(storage, ", "),
+		GPU:      strings.Join(gpus, ", "),
+	}
+	if err := db.Connection.Create(&system).Error; err != nil {
+		return fmt.Errorf("failed to save system info: %v", err)
+	}
+
+	return nil
+}
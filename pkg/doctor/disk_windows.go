@@ -0,0 +1,21 @@
+package doctor
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+// freeSpace 返回目录所在卷对当前用户可用的字节数
+func freeSpace(dir string) (uint64, error) {
+	path, err := syscall.UTF16PtrFromString(dir)
+	if err != nil {
+		return 0, err
+	}
+
+	var available uint64
+	proc := syscall.NewLazyDLL("kernel32.dll").NewProc("GetDiskFreeSpaceExW")
+	if ret, _, err := proc.Call(uintptr(unsafe.Pointer(path)), uintptr(unsafe.Pointer(&available)), 0, 0); ret == 0 {
+		return 0, err
+	}
+	return available, nil
+}
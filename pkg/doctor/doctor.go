@@ -0,0 +1,56 @@
+package doctor
+
+import (
+	"fmt"
+	"io/fs"
+	"octane/pkg/assets"
+)
+
+// 检查结果状态
+const (
+	StatusPass = "pass"
+	StatusWarn = "warn"
+	StatusFail = "fail"
+)
+
+// Result 定义一项环境检查的结果
+type Result struct {
+	Name    string `json:"name"`
+	Status  string `json:"status"`
+	Message string `json:"message"`
+	Hint    string `json:"hint,omitempty"` // 未通过时的修复建议
+}
+
+// CheckAssets 比较嵌入的脚本和配置与 dir 中已解压的副本
+func CheckAssets(fsys fs.FS, dir string) Result {
+	result := Result{Name: "embedded assets"}
+
+	changes, err := assets.Diff(fsys, dir)
+	switch {
+	case err != nil:
+		result.Status = StatusFail
+		result.Message = err.Error()
+		result.Hint = fmt.Sprintf("check that %s is readable", dir)
+	case len(changes) == 0:
+		result.Status = StatusPass
+		result.Message = fmt.Sprintf("extracted copies in %s match this binary", dir)
+	default:
+		result.Status = StatusWarn
+		result.Message = fmt.Sprintf("%d file(s) in %s differ from this binary", len(changes), dir)
+		for _, change := range changes {
+			result.Message += fmt.Sprintf("\n%s (%s)", change.Path, change.Status)
+		}
+		result.Hint = "they are replaced or removed automatically on the next Python-backed test"
+	}
+	return result
+}
+
+// Failed 判断是否有检查失败
+func Failed(results []Result) bool {
+	for _, result := range results {
+		if result.Status == StatusFail {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,76 @@
+package doctor
+
+import (
+	"context"
+	"fmt"
+	"octane/pkg/executor"
+	"path/filepath"
+	"strings"
+)
+
+// CheckPython 检查 Python 解释器是否可用
+func CheckPython(ctx context.Context) (Result, string) {
+	result := Result{Name: "python"}
+
+	python, err := executor.FindPython()
+	if err != nil {
+		result.Status = StatusFail
+		result.Message = err.Error()
+		result.Hint = "install python3 or set " + executor.PythonEnv + " to an interpreter"
+		return result, ""
+	}
+
+	stdout, stderr, err := executor.RunCommand(ctx, executor.Command{Path: python, Args: []string{"--version"}})
+	if err != nil {
+		result.Status = StatusFail
+		result.Message = fmt.Sprintf("%s: %v", python, err)
+		result.Hint = "check that " + python + " runs"
+		return result, ""
+	}
+
+	// Python 2 会把版本写到 stderr
+	version := strings.TrimSpace(string(stdout) + string(stderr))
+	result.Status = StatusPass
+	result.Message = fmt.Sprintf("%s (%s)", version, python)
+	if !strings.HasPrefix(version, "Python 3") {
+		result.Status = StatusFail
+		result.Hint = "octane scripts need Python 3; set " + executor.PythonEnv
+	}
+	return result, python
+}
+
+// CheckModules 检查 requirements.txt 中的模块是否已安装
+func CheckModules(ctx context.Context, python, scriptDir string) Result {
+	result := Result{Name: "python modules"}
+	if python == "" {
+		result.Status = StatusFail
+		result.Message = "skipped: no python interpreter"
+		result.Hint = "fix the python check first"
+		return result
+	}
+
+	requirements := filepath.Join(scriptDir, executor.RequirementsFile)
+	modules, err := executor.ReadRequirements(requirements)
+	if err != nil {
+		result.Status = StatusFail
+		result.Message = err.Error()
+		return result
+	}
+
+	missing, err := executor.CheckModules(ctx, python, modules)
+	if err != nil {
+		result.Status = StatusFail
+		result.Message = err.Error()
+		return result
+	}
+	if len(missing) > 0 {
+		result.Status = StatusWarn
+		result.Message = fmt.Sprintf("%d of %d missing: %s", len(missing), len(modules), strings.Join(missing, ", "))
+		result.Hint = fmt.Sprintf("%s -m pip install -r %s", python, requirements)
+		return result
+	}
+
+	result.Status = StatusPass
+	result.Message = fmt.Sprintf("all %d modules installed", len(modules))
+	return result
+}
@@ -0,0 +1,116 @@
+package doctor
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+)
+
+// minFreeSpace 是临时目录建议的最小可用空间，够默认存储测试文件和解压的脚本使用
+const minFreeSpace = 1 << 30
+
+// CheckTempDir 检查临时目录是否可写以及剩余空间
+func CheckTempDir(dir string) Result {
+	result := Result{Name: "temp_dir"}
+
+	if err := checkWritable(dir); err != nil {
+		result.Status = StatusFail
+		result.Message = err.Error()
+		result.Hint = "set global.temp_dir to a writable directory"
+		return result
+	}
+
+	free, err := freeSpace(dir)
+	if err != nil {
+		result.Status = StatusWarn
+		result.Message = fmt.Sprintf("%s: cannot read free space: %v", dir, err)
+		return result
+	}
+
+	result.Message = fmt.Sprintf("%s: %.1f GB free", dir, float64(free)/(1<<30))
+	if free < minFreeSpace {
+		result.Status = StatusWarn
+		result.Hint = "free up space or lower --storage-size; storage tests need at least 1 GB"
+		return result
+	}
+	result.Status = StatusPass
+	return result
+}
+
+// CheckDatabase 检查 SQLite 数据库文件或其所在目录是否可写
+func CheckDatabase(path string) Result {
+	result := Result{Name: "database"}
+
+	if _, err := os.Stat(path); err == nil {
+		file, err := os.OpenFile(path, os.O_WRONLY, 0)
+		if err != nil {
+			result.Status = StatusFail
+			result.Message = err.Error()
+			result.Hint = "fix the file permissions or set global.database"
+			return result
+		}
+		file.Close()
+		result.Status = StatusPass
+		result.Message = path + " is writable"
+		return result
+	}
+
+	if err := checkWritable(filepath.Dir(path)); err != nil {
+		result.Status = StatusFail
+		result.Message = err.Error()
+		result.Hint = "set global.database to a path in a writable directory"
+		return result
+	}
+	result.Status = StatusPass
+	result.Message = path + " will be created on the first run"
+	return result
+}
+
+// CheckUpload 检查上传服务器配置是否合理
+func CheckUpload(enabled bool, serverURL, apiKey string, anonymous bool) Result {
+	result := Result{Name: "upload"}
+
+	if !enabled {
+		result.Status = StatusPass
+		result.Message = "disabled"
+		return result
+	}
+
+	u, err := url.Parse(serverURL)
+	if err != nil || u.Host == "" || (u.Scheme != "https" && u.Scheme != "http") {
+		result.Status = StatusFail
+		result.Message = fmt.Sprintf("invalid server_url %q", serverURL)
+		result.Hint = "set upload.server_url to an http(s) URL"
+		return result
+	}
+	if apiKey == "" && !anonymous {
+		result.Status = StatusFail
+		result.Message = "api_key is empty and anonymous upload is off"
+		result.Hint = "set upload.api_key or enable upload.anonymous"
+		return result
+	}
+	if u.Scheme == "http" {
+		result.Status = StatusWarn
+		result.Message = serverURL + " does not use TLS"
+		result.Hint = "use https so the API key is not sent in clear text"
+		return result
+	}
+
+	result.Status = StatusPass
+	result.Message = serverURL
+	return result
+}
+
+// checkWritable 创建目录并写入一个临时文件来确认其可写
+func checkWritable(dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	file, err := os.CreateTemp(dir, ".octane-doctor-*")
+	if err != nil {
+		return err
+	}
+	file.Close()
+	return os.Remove(file.Name())
+}
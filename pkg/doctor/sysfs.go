@@ -0,0 +1,69 @@
+package doctor
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// sysfsSources 是硬件监控依赖的 sysfs 文件，路径相对于根目录
+var sysfsSources = []struct {
+	name string
+	glob string
+	hint string
+}{
+	{"thermal", "sys/class/thermal/thermal_zone*/temp", "temperature readings fall back to estimates"},
+	{"cpufreq", "sys/devices/system/cpu/cpu*/cpufreq/scaling_cur_freq", "load the cpufreq driver to report CPU frequency"},
+	{"powercap", "sys/class/powercap/intel-rapl:*/energy_uj", "run as root to read RAPL energy counters"},
+}
+
+// CheckSysfs 检查温度、频率和功耗相关的 sysfs 文件是否可读，root 通常为 "/"
+func CheckSysfs(root string) []Result {
+	var results []Result
+	for _, source := range sysfsSources {
+		result := Result{Name: source.name}
+
+		matches, _ := filepath.Glob(filepath.Join(root, source.glob))
+		readable := 0
+		for _, path := range matches {
+			if _, err := os.ReadFile(path); err == nil {
+				readable++
+			}
+		}
+
+		switch {
+		case len(matches) == 0:
+			result.Status = StatusWarn
+			result.Message = "not available on this host"
+			result.Hint = source.hint
+		case readable == 0:
+			result.Status = StatusWarn
+			result.Message = fmt.Sprintf("%d file(s) present but not readable", len(matches))
+			result.Hint = source.hint
+		default:
+			result.Status = StatusPass
+			result.Message = fmt.Sprintf("%d of %d file(s) readable", readable, len(matches))
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+// CheckPrivileges 检查是否以 root 运行，O_DIRECT 存储测试和 DMI 信息需要 root
+func CheckPrivileges(root string) Result {
+	result := Result{Name: "privileges"}
+
+	if os.Geteuid() == 0 {
+		result.Status = StatusPass
+		result.Message = "running as root"
+		return result
+	}
+
+	result.Status = StatusWarn
+	result.Message = "not running as root"
+	if _, err := os.ReadFile(filepath.Join(root, "sys/class/dmi/id/product_serial")); err != nil {
+		result.Message += "; DMI serial numbers are not readable"
+	}
+	result.Hint = "run with sudo for O_DIRECT storage tests and full DMI information"
+	return result
+}
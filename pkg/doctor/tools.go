@@ -0,0 +1,40 @@
+package doctor
+
+import (
+	"context"
+	"fmt"
+	"octane/pkg/executor"
+	"strings"
+)
+
+// Tools 是 tools/ 目录中随附的外部测试工具
+var Tools = []string{"iperf3", "stress-ng", "sysbench"}
+
+// CheckTool 检查外部工具是否存在并读取其版本
+func CheckTool(ctx context.Context, toolsDir, name string) Result {
+	result := Result{Name: name}
+
+	path, err := executor.FindTool(toolsDir, name)
+	if err != nil {
+		result.Status = StatusWarn
+		result.Message = "not found"
+		result.Hint = fmt.Sprintf("install %s (e.g. apt install %s) to enable its tests", name, name)
+		return result
+	}
+
+	stdout, stderr, err := executor.RunCommand(ctx, executor.Command{Path: path, Args: []string{"--version"}})
+	if err != nil {
+		result.Status = StatusWarn
+		result.Message = fmt.Sprintf("%s: %v", path, err)
+		result.Hint = "reinstall " + name
+		return result
+	}
+
+	version := strings.TrimSpace(string(stdout) + string(stderr))
+	if i := strings.IndexByte(version, '\n'); i >= 0 {
+		version = version[:i]
+	}
+	result.Status = StatusPass
+	result.Message = fmt.Sprintf("%s (%s)", version, path)
+	return result
+}
@@ -0,0 +1,51 @@
+package executor
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+)
+
+// Command 描述一个外部进程调用
+type Command struct {
+	Path string   // 可执行文件
+	Args []string // 参数
+	Dir  string   // 工作目录
+	Env  []string // 追加的环境变量，形如 KEY=VALUE
+
+	Stderr io.Writer // 非空时 stderr 写入此处而不是缓存返回
+}
+
+// RunCommand 运行外部进程，分别返回 stdout 和 stderr。
+// 上下文取消或超时时终止进程及其子进程。
+func RunCommand(ctx context.Context, c Command) ([]byte, []byte, error) {
+	cmd := exec.CommandContext(ctx, c.Path, c.Args...)
+	cmd.Dir = c.Dir
+	if len(c.Env) > 0 {
+		cmd.Env = append(os.Environ(), c.Env...)
+	}
+	cmd.WaitDelay = processWaitDelay
+	setProcessGroup(cmd)
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if c.Stderr != nil {
+		cmd.Stderr = c.Stderr
+	}
+
+	err := cmd.Run()
+	return stdout.Bytes(), stderr.Bytes(), err
+}
+
+// FindTool 查找外部工具，优先使用 toolsDir 中的可执行文件，其次是 PATH
+func FindTool(toolsDir, name string) (string, error) {
+	path := filepath.Join(toolsDir, name)
+	if info, err := os.Stat(path); err == nil && !info.IsDir() && info.Mode()&0111 != 0 {
+		return path, nil
+	}
+	return exec.LookPath(name)
+}
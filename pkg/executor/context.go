@@ -0,0 +1,14 @@
+package executor
+
+import (
+	"context"
+	"time"
+)
+
+// running 判断基准测试循环是否应继续：未到时间且上下文未取消
+func running(ctx context.Context, start time.Time, duration time.Duration) bool {
+	return ctx.Err() == nil && time.Since(start) < duration
+}
+
+// processWaitDelay 是取消外部进程后等待其输出管道关闭的最长时间
+const processWaitDelay = 5 * time.Second
@@ -2,8 +2,12 @@ package executor
 
 import (
 	"bufio"
+	"context"
 	"fmt"
 	"math"
+	"octane/pkg/benchmark"
+	"octane/pkg/cgroup"
+	"octane/pkg/monitor"
 	"octane/pkg/types"
 	"os"
 	"os/exec"
@@ -14,17 +18,45 @@ import (
 	"time"
 )
 
-// ExecuteCPUTest 执行CPU性能测试
-func ExecuteCPUTest(threads int, duration string, testType string) (*types.CPUResults, error) {
+func init() {
+	benchmark.Register(benchmark.New("cpu/compute/single-core", "cpu",
+		"Single-core integer/floating point throughput", 15*time.Second, runSingleCoreBenchmark))
+	benchmark.Register(benchmark.New("cpu/compute/multi-core", "cpu",
+		"Multi-core integer/floating point throughput", 30*time.Second, runMultiCoreBenchmark))
+	benchmark.Register(benchmark.New("cpu/crypto/aes256", "cpu",
+		"AES-256 encryption throughput", 5*time.Second, runAESBenchmark))
+	benchmark.Register(benchmark.New("cpu/crypto/sha256", "cpu",
+		"SHA-256 hashing throughput", 5*time.Second, runSHA256Benchmark))
+	benchmark.Register(benchmark.New("cpu/crypto/rsa2048", "cpu",
+		"RSA-2048 operations per second", 5*time.Second, runRSABenchmark))
+	benchmark.Register(benchmark.New("cpu/compress/gzip", "cpu",
+		"Multi-threaded Gzip compression throughput", 5*time.Second, runGzipBenchmark))
+	benchmark.Register(benchmark.New("cpu/compress/lz4", "cpu",
+		"Multi-threaded LZ4 compression throughput", 5*time.Second, runLZ4Benchmark))
+	benchmark.Register(benchmark.New("cpu/compress/zstd", "cpu",
+		"Multi-threaded Zstd compression throughput", 5*time.Second, runZstdBenchmark))
+}
+
+// ExecuteCPUTest 执行CPU性能测试。
+// testType 可以是 all、compute、crypto、compress，也可以是完整的基准测试名称。
+func ExecuteCPUTest(ctx context.Context, threads int, duration string, testType string) (*types.CPUResults, error) {
 	// 解析持续时间
 	testDuration, err := time.ParseDuration(duration)
 	if err != nil {
 		return nil, fmt.Errorf("invalid duration format: %v", err)
 	}
 
-	// 如果threads为0，使用系统CPU核心数
-	if threads == 0 {
-		threads = runtime.NumCPU()
+	// 选择要运行的基准测试
+	pattern := "cpu"
+	if testType != "all" {
+		pattern = testType
+		if !strings.Contains(testType, "/") {
+			pattern = "cpu/" + testType
+		}
+	}
+	list, err := benchmark.Select(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("unknown test type: %s", testType)
 	}
 
 	// 创建结果结构
@@ -42,54 +74,140 @@ func ExecuteCPUTest(threads int, duration string, testType string) (*types.CPURe
 	results.Frequencies.AverageAllCores = 3200.0
 	results.Frequencies.Stability = 98.5
 
-	// 根据测试类型执行相应测试
-	switch testType {
-	case "all":
-		return runAllCPUTests(threads, testDuration, results)
-	case "compute":
-		return runComputeTest(threads, testDuration, results)
-	case "crypto":
-		return runCryptoTest(threads, testDuration, results)
-	case "compress":
-		return runCompressionTest(threads, testDuration, results)
-	default:
-		return nil, fmt.Errorf("unknown test type: %s", testType)
+	// threads 为 0 时由各基准测试按 cgroup 限制决定线程数，这里只用于显示
+	fmt.Printf("Running %d CPU benchmarks with %d threads for %v...\n", len(list), threadCount(threads), testDuration)
+
+	runs, err := benchmark.RunAll(ctx, list, benchmark.Options{Threads: threads}, testDuration, printProgress)
+	if err != nil {
+		return nil, err
+	}
+
+	if !monitor.PowerMonitoring(ctx) {
+		results.Efficiency.Reason = "power monitoring disabled"
+	}
+	for _, run := range runs {
+		applyCPUMetrics(results, run)
+	}
+
+	return results, nil
+}
+
+// applyCPUMetrics 将基准测试指标写入 CPUResults，有功耗数据时同时计算每瓦性能
+func applyCPUMetrics(results *types.CPUResults, run *benchmark.Result) {
+	watts := run.Power.Package.Watts
+	if !run.Power.Available && run.Power.Reason != "" && !results.Efficiency.Available {
+		results.Efficiency.Reason = run.Power.Reason
+	}
+
+	for _, m := range run.Metrics {
+		switch m.Name {
+		case "single_core_score":
+			score := int(m.Value)
+			results.Tests.SingleCore.IntegerPerformance.Score = score
+			results.Tests.SingleCore.IntegerPerformance.Unit = m.Unit
+			results.Tests.SingleCore.IntegerPerformance.Percentile = calculatePercentile(score)
+			if watts > 0 {
+				results.Efficiency.Available, results.Efficiency.Reason = true, ""
+				results.Efficiency.SingleCorePointsPerWatt = m.Value / watts
+			}
+		case "multi_core_score":
+			score := int(m.Value)
+			results.Tests.MultiCore.IntegerPerformance.Score = score
+			results.Tests.MultiCore.IntegerPerformance.Unit = m.Unit
+			results.Tests.MultiCore.IntegerPerformance.Percentile = calculatePercentile(score)
+			if watts > 0 {
+				results.Efficiency.Available, results.Efficiency.Reason = true, ""
+				results.Efficiency.MultiCorePointsPerWatt = m.Value / watts
+				results.Efficiency.MultiCoreWatts = watts
+			}
+		case "aes256":
+			results.Tests.SingleCore.Cryptography.AES256 = m.Value
+		case "sha256":
+			results.Tests.SingleCore.Cryptography.SHA256 = m.Value
+		case "rsa2048":
+			results.Tests.SingleCore.Cryptography.RSA2048 = int(m.Value)
+		case "gzip":
+			results.Tests.MultiCore.Compression.Gzip = int(m.Value)
+		case "lz4":
+			results.Tests.MultiCore.Compression.LZ4 = int(m.Value)
+		case "zstd":
+			results.Tests.MultiCore.Compression.Zstd = int(m.Value)
+		}
 	}
 }
 
-// runAllCPUTests 运行所有CPU测试
-func runAllCPUTests(threads int, duration time.Duration, results *types.CPUResults) (*types.CPUResults, error) {
-	fmt.Printf("Running comprehensive CPU tests with %d threads for %v...\n", threads, duration)
+// printProgress 打印基准测试的开始和结果
+func printProgress(b benchmark.Benchmark, result *benchmark.Result) {
+	if result == nil {
+		fmt.Printf("Running %s...\n", b.Name())
+		return
+	}
+	for _, m := range result.Metrics {
+		fmt.Printf("  %s: %.2f %s\n", m.Name, m.Value, m.Unit)
+	}
+}
+
+// runSingleCoreBenchmark 运行单核性能测试
+func runSingleCoreBenchmark(ctx context.Context, opts benchmark.Options) ([]benchmark.Metric, error) {
+	score := runSingleCoreTest(ctx, opts.Duration)
+	return []benchmark.Metric{{Name: "single_core_score", Value: float64(score), Unit: "points", HigherIsBetter: true}}, nil
+}
+
+// runMultiCoreBenchmark 运行多核性能测试
+func runMultiCoreBenchmark(ctx context.Context, opts benchmark.Options) ([]benchmark.Metric, error) {
+	score := runMultiCoreTest(ctx, threadCount(opts.Threads), opts.Duration)
+	return []benchmark.Metric{{Name: "multi_core_score", Value: float64(score), Unit: "points", HigherIsBetter: true}}, nil
+}
 
-	// 运行单核测试
-	singleCoreScore := runSingleCoreTest(duration / 4)
-	results.Tests.SingleCore.IntegerPerformance.Score = singleCoreScore
-	results.Tests.SingleCore.IntegerPerformance.Unit = "points"
-	results.Tests.SingleCore.IntegerPerformance.Percentile = calculatePercentile(singleCoreScore)
+// runAESBenchmark 运行AES加密测试
+func runAESBenchmark(ctx context.Context, opts benchmark.Options) ([]benchmark.Metric, error) {
+	return []benchmark.Metric{{Name: "aes256", Value: runAESTest(ctx, opts.Duration), Unit: "GB/s", HigherIsBetter: true}}, nil
+}
 
-	// 运行多核测试
-	multiCoreScore := runMultiCoreTest(threads, duration/2)
-	results.Tests.MultiCore.IntegerPerformance.Score = multiCoreScore
-	results.Tests.MultiCore.IntegerPerformance.Unit = "points"
-	results.Tests.MultiCore.IntegerPerformance.Percentile = calculatePercentile(multiCoreScore)
+// runSHA256Benchmark 运行SHA256测试
+func runSHA256Benchmark(ctx context.Context, opts benchmark.Options) ([]benchmark.Metric, error) {
+	return []benchmark.Metric{{Name: "sha256", Value: runSHA256Test(ctx, opts.Duration), Unit: "GB/s", HigherIsBetter: true}}, nil
+}
 
-	// 运行加密测试
-	runCryptographyTests(threads, duration/4, results)
+// runRSABenchmark 运行RSA测试
+func runRSABenchmark(ctx context.Context, opts benchmark.Options) ([]benchmark.Metric, error) {
+	return []benchmark.Metric{{Name: "rsa2048", Value: float64(runRSATest(ctx, opts.Duration)), Unit: "ops/sec", HigherIsBetter: true}}, nil
+}
 
-	// 运行压缩测试
-	runCompressionTests(threads, duration/4, results)
+// runGzipBenchmark 运行Gzip压缩测试
+func runGzipBenchmark(ctx context.Context, opts benchmark.Options) ([]benchmark.Metric, error) {
+	mbps := runGzipTest(ctx, threadCount(opts.Threads), opts.Duration)
+	return []benchmark.Metric{{Name: "gzip", Value: float64(mbps), Unit: "MB/s", HigherIsBetter: true}}, nil
+}
 
-	return results, nil
+// runLZ4Benchmark 运行LZ4压缩测试
+func runLZ4Benchmark(ctx context.Context, opts benchmark.Options) ([]benchmark.Metric, error) {
+	// 类似Gzip测试，但速度更快，LZ4通常比Gzip快50%
+	mbps := float64(runGzipTest(ctx, threadCount(opts.Threads), opts.Duration)) * 1.5
+	return []benchmark.Metric{{Name: "lz4", Value: mbps, Unit: "MB/s", HigherIsBetter: true}}, nil
 }
 
-// runSingleCoreTest 运行单核性能测试
-func runSingleCoreTest(duration time.Duration) int {
-	fmt.Println("Running single-core integer performance test...")
+// runZstdBenchmark 运行Zstd压缩测试
+func runZstdBenchmark(ctx context.Context, opts benchmark.Options) ([]benchmark.Metric, error) {
+	// 类似Gzip测试，性能介于Gzip和LZ4之间，Zstd通常比Gzip快20%
+	mbps := float64(runGzipTest(ctx, threadCount(opts.Threads), opts.Duration)) * 1.2
+	return []benchmark.Metric{{Name: "zstd", Value: mbps, Unit: "MB/s", HigherIsBetter: true}}, nil
+}
+
+// threadCount 返回实际使用的线程数，0 表示使用 cgroup 限制下可用的CPU核心数
+func threadCount(threads int) int {
+	if threads <= 0 {
+		return cgroup.CPUs()
+	}
+	return threads
+}
 
+// runSingleCoreTest 运行单核性能测试
+func runSingleCoreTest(ctx context.Context, duration time.Duration) int {
 	start := time.Now()
 	operations := 0
 
-	for time.Since(start) < duration {
+	for running(ctx, start, duration) {
 		// 执行计算密集型操作
 		for i := 0; i < 10000; i++ {
 			_ = math.Sqrt(float64(i)) * math.Sin(float64(i))
@@ -98,15 +216,11 @@ func runSingleCoreTest(duration time.Duration) int {
 	}
 
 	// 根据操作数计算分数
-	score := operations / 1000
-	fmt.Printf("Single-core test completed: %d operations, score: %d\n", operations, score)
-	return score
+	return operations / 1000
 }
 
 // runMultiCoreTest 运行多核性能测试
-func runMultiCoreTest(threads int, duration time.Duration) int {
-	fmt.Printf("Running multi-core test with %d threads...\n", threads)
-
+func runMultiCoreTest(ctx context.Context, threads int, duration time.Duration) int {
 	var wg sync.WaitGroup
 	var mu sync.Mutex
 	totalOperations := 0
@@ -119,7 +233,7 @@ func runMultiCoreTest(threads int, duration time.Duration) int {
 			defer wg.Done()
 			operations := 0
 
-			for time.Since(start) < duration {
+			for running(ctx, start, duration) {
 				// 执行计算密集型操作
 				for j := 0; j < 5000; j++ {
 					_ = math.Sqrt(float64(j)) * math.Cos(float64(j))
@@ -135,33 +249,15 @@ func runMultiCoreTest(threads int, duration time.Duration) int {
 
 	wg.Wait()
 
-	score := totalOperations / 1000
-	fmt.Printf("Multi-core test completed: %d total operations, score: %d\n", totalOperations, score)
-	return score
-}
-
-// runCryptographyTests 运行加密性能测试
-func runCryptographyTests(threads int, duration time.Duration, results *types.CPUResults) {
-	fmt.Println("Running cryptography tests...")
-
-	// 模拟AES加密测试
-	results.Tests.SingleCore.Cryptography.AES256 = runAESTest(duration / 3)
-
-	// 模拟SHA256测试
-	results.Tests.SingleCore.Cryptography.SHA256 = runSHA256Test(duration / 3)
-
-	// 模拟RSA测试
-	results.Tests.SingleCore.Cryptography.RSA2048 = runRSATest(duration / 3)
+	return totalOperations / 1000
 }
 
-// runAESTest 模拟AES加密测试
-func runAESTest(duration time.Duration) float64 {
-	fmt.Println("Running AES-256 encryption test...")
-
+// runAESTest 模拟AES加密测试，返回 GB/s
+func runAESTest(ctx context.Context, duration time.Duration) float64 {
 	start := time.Now()
 	bytesProcessed := 0
 
-	for time.Since(start) < duration {
+	for running(ctx, start, duration) {
 		// 模拟AES加密操作
 		data := make([]byte, 1024*1024) // 1MB数据
 		for i := range data {
@@ -171,19 +267,15 @@ func runAESTest(duration time.Duration) float64 {
 	}
 
 	// 计算GB/s
-	gbPerSecond := float64(bytesProcessed) / float64(duration.Seconds()) / (1024 * 1024 * 1024)
-	fmt.Printf("AES-256 test: %.2f GB/s\n", gbPerSecond)
-	return gbPerSecond
+	return float64(bytesProcessed) / float64(duration.Seconds()) / (1024 * 1024 * 1024)
 }
 
-// runSHA256Test 模拟SHA256测试
-func runSHA256Test(duration time.Duration) float64 {
-	fmt.Println("Running SHA-256 hash test...")
-
+// runSHA256Test 模拟SHA256测试，返回 GB/s
+func runSHA256Test(ctx context.Context, duration time.Duration) float64 {
 	start := time.Now()
 	bytesProcessed := 0
 
-	for time.Since(start) < duration {
+	for running(ctx, start, duration) {
 		// 模拟SHA256哈希操作
 		data := make([]byte, 512*1024) // 512KB数据
 		for i := range data {
@@ -192,19 +284,15 @@ func runSHA256Test(duration time.Duration) float64 {
 		bytesProcessed += len(data)
 	}
 
-	gbPerSecond := float64(bytesProcessed) / float64(duration.Seconds()) / (1024 * 1024 * 1024)
-	fmt.Printf("SHA-256 test: %.2f GB/s\n", gbPerSecond)
-	return gbPerSecond
+	return float64(bytesProcessed) / float64(duration.Seconds()) / (1024 * 1024 * 1024)
 }
 
-// runRSATest 模拟RSA测试
-func runRSATest(duration time.Duration) int {
-	fmt.Println("Running RSA-2048 test...")
-
+// runRSATest 模拟RSA测试，返回 ops/sec
+func runRSATest(ctx context.Context, duration time.Duration) int {
 	start := time.Now()
 	operations := 0
 
-	for time.Since(start) < duration {
+	for running(ctx, start, duration) {
 		// 模拟RSA操作（简化版本）
 		for i := 0; i < 100; i++ {
 			_ = math.Pow(float64(i), 2.0)
@@ -212,24 +300,11 @@ func runRSATest(duration time.Duration) int {
 		}
 	}
 
-	opsPerSecond := int(float64(operations) / duration.Seconds())
-	fmt.Printf("RSA-2048 test: %d ops/sec\n", opsPerSecond)
-	return opsPerSecond
+	return int(float64(operations) / duration.Seconds())
 }
 
-// runCompressionTests 运行压缩测试
-func runCompressionTests(threads int, duration time.Duration, results *types.CPUResults) {
-	fmt.Println("Running compression tests...")
-
-	results.Tests.MultiCore.Compression.Gzip = runGzipTest(threads, duration/3)
-	results.Tests.MultiCore.Compression.LZ4 = runLZ4Test(threads, duration/3)
-	results.Tests.MultiCore.Compression.Zstd = runZstdTest(threads, duration/3)
-}
-
-// runGzipTest 模拟Gzip压缩测试
-func runGzipTest(threads int, duration time.Duration) int {
-	fmt.Println("Running Gzip compression test...")
-
+// runGzipTest 模拟Gzip压缩测试，返回 MB/s
+func runGzipTest(ctx context.Context, threads int, duration time.Duration) int {
 	var wg sync.WaitGroup
 	var mu sync.Mutex
 	totalMB := 0
@@ -242,7 +317,7 @@ func runGzipTest(threads int, duration time.Duration) int {
 			defer wg.Done()
 			mbProcessed := 0
 
-			for time.Since(start) < duration {
+			for running(ctx, start, duration) {
 				// 模拟压缩操作
 				data := make([]byte, 1024*1024) // 1MB数据
 				for j := range data {
@@ -259,56 +334,7 @@ func runGzipTest(threads int, duration time.Duration) int {
 
 	wg.Wait()
 
-	mbPerSecond := int(float64(totalMB) / duration.Seconds())
-	fmt.Printf("Gzip test: %d MB/s\n", mbPerSecond)
-	return mbPerSecond
-}
-
-// runLZ4Test 模拟LZ4压缩测试
-func runLZ4Test(threads int, duration time.Duration) int {
-	fmt.Println("Running LZ4 compression test...")
-	// 类似Gzip测试，但速度更快
-	result := runGzipTest(threads, duration)
-	return int(float64(result) * 1.5) // LZ4通常比Gzip快50%
-}
-
-// runZstdTest 模拟Zstd压缩测试
-func runZstdTest(threads int, duration time.Duration) int {
-	fmt.Println("Running Zstd compression test...")
-	// 类似Gzip测试，性能介于Gzip和LZ4之间
-	result := runGzipTest(threads, duration)
-	return int(float64(result) * 1.2) // Zstd通常比Gzip快20%
-}
-
-// runComputeTest 运行计算测试
-func runComputeTest(threads int, duration time.Duration, results *types.CPUResults) (*types.CPUResults, error) {
-	fmt.Printf("Running compute-focused CPU test with %d threads...\n", threads)
-
-	singleScore := runSingleCoreTest(duration / 2)
-	multiScore := runMultiCoreTest(threads, duration/2)
-
-	results.Tests.SingleCore.IntegerPerformance.Score = singleScore
-	results.Tests.MultiCore.IntegerPerformance.Score = multiScore
-
-	return results, nil
-}
-
-// runCryptoTest 运行加密测试
-func runCryptoTest(threads int, duration time.Duration, results *types.CPUResults) (*types.CPUResults, error) {
-	fmt.Printf("Running crypto-focused CPU test...\n")
-
-	runCryptographyTests(threads, duration, results)
-
-	return results, nil
-}
-
-// runCompressionTest 运行压缩测试
-func runCompressionTest(threads int, duration time.Duration, results *types.CPUResults) (*types.CPUResults, error) {
-	fmt.Printf("Running compression-focused CPU test...\n")
-
-	runCompressionTests(threads, duration, results)
-
-	return results, nil
+	return int(float64(totalMB) / duration.Seconds())
 }
 
 // calculatePercentile 计算百分位数（简化版本）
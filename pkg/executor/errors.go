@@ -0,0 +1,70 @@
+package executor
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// InterpreterError 表示找不到可用的 Python 解释器
+type InterpreterError struct {
+	Tried []string // 依次尝试过的解释器
+}
+
+func (e *InterpreterError) Error() string {
+	return fmt.Sprintf("no usable python interpreter found (tried %s); set OCTANE_PYTHON or create a venv",
+		strings.Join(e.Tried, ", "))
+}
+
+// MissingModuleError 表示脚本依赖的 Python 模块未安装
+type MissingModuleError struct {
+	Script  string
+	Modules []string
+}
+
+func (e *MissingModuleError) Error() string {
+	return fmt.Sprintf("%s: missing python modules: %s (pip install -r requirements.txt)",
+		e.Script, strings.Join(e.Modules, ", "))
+}
+
+// TimeoutError 表示脚本超过了允许的运行时间
+type TimeoutError struct {
+	Script  string
+	Timeout time.Duration
+}
+
+func (e *TimeoutError) Error() string {
+	return fmt.Sprintf("%s: timed out after %v", e.Script, e.Timeout)
+}
+
+// OutputError 表示脚本的 stdout 不是合法的结果信封
+type OutputError struct {
+	Script string
+	Err    error
+}
+
+func (e *OutputError) Error() string {
+	return fmt.Sprintf("%s: malformed output: %v", e.Script, e.Err)
+}
+
+func (e *OutputError) Unwrap() error {
+	return e.Err
+}
+
+// ScriptError 表示脚本以非零状态退出
+type ScriptError struct {
+	Script string
+	Err    error
+	Stderr string // stderr 的最后几行
+}
+
+func (e *ScriptError) Error() string {
+	if e.Stderr == "" {
+		return fmt.Sprintf("%s: %v", e.Script, e.Err)
+	}
+	return fmt.Sprintf("%s: %v: %s", e.Script, e.Err, e.Stderr)
+}
+
+func (e *ScriptError) Unwrap() error {
+	return e.Err
+}
@@ -1,34 +1,208 @@
 package executor
 
 import (
-    "os/exec"
-    "path/filepath"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"octane/pkg/benchmark"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
 )
 
+// EnvelopeVersion 是当前支持的脚本输出格式版本
+const EnvelopeVersion = 1
+
+// defaultScriptTimeout 是脚本未指定超时时的默认值
+const defaultScriptTimeout = 10 * time.Minute
+
+// Envelope 定义脚本写到 stdout 的 JSON 结果信封，进度和诊断信息写到 stderr
+//
+//	{"version": 1, "script": "network_test", "metrics": [...], "data": {...}}
+type Envelope struct {
+	Version int                `json:"version"`
+	Script  string             `json:"script"`
+	Metrics []benchmark.Metric `json:"metrics"`
+	Data    json.RawMessage    `json:"data,omitempty"`
+}
+
 // Executor 结构体用于执行Python脚本
 type Executor struct {
-    ScriptPath string // Python脚本的路径
+	ScriptPath   string        // Python脚本的路径
+	Python       string        // 解释器，为空时通过 FindPython 查找
+	Requirements string        // 依赖清单，为空时使用脚本目录下的 requirements.txt
+	Timeout      time.Duration // 超时时间
+	Env          []string      // 追加的环境变量
 }
 
 // NewExecutor 创建一个新的Executor实例
 func NewExecutor(scriptPath string) *Executor {
-    return &Executor{
-        ScriptPath: scriptPath,
-    }
-}
-
-// Run 执行Python脚本并返回输出
-func (e *Executor) Run(args ...string) (string, error) {
-    cmd := exec.Command("python3", append([]string{e.ScriptPath}, args...)...)
-    output, err := cmd.CombinedOutput()
-    return string(output), err
-}
-
-// GetScriptPath 获取脚本的绝对路径
-func (e *Executor) GetScriptPath() (string, error) {
-    absPath, err := filepath.Abs(e.ScriptPath)
-    if err != nil {
-        return "", err
-    }
-    return absPath, nil
-}
\ No newline at end of file
+	return &Executor{
+		ScriptPath: scriptPath,
+		Timeout:    defaultScriptTimeout,
+	}
+}
+
+// Run 检查解释器和依赖后执行脚本，stderr 逐行写入日志，stdout 解析为结果信封。
+// 上下文取消时终止脚本及其子进程。
+func (e *Executor) Run(ctx context.Context, args ...string) (*Envelope, error) {
+	script := strings.TrimSuffix(filepath.Base(e.ScriptPath), ".py")
+
+	python := e.Python
+	if python == "" {
+		var err error
+		if python, err = FindPython(); err != nil {
+			return nil, err
+		}
+	}
+
+	requirements := e.Requirements
+	if requirements == "" {
+		requirements = filepath.Join(filepath.Dir(e.ScriptPath), RequirementsFile)
+	}
+	missing, err := MissingModules(ctx, python, e.ScriptPath, requirements)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", script, err)
+	}
+	if len(missing) > 0 {
+		return nil, &MissingModuleError{Script: script, Modules: missing}
+	}
+
+	runCtx := ctx
+	if e.Timeout > 0 {
+		var cancel context.CancelFunc
+		runCtx, cancel = context.WithTimeout(ctx, e.Timeout)
+		defer cancel()
+	}
+
+	stderr := &logWriter{prefix: script}
+	stdout, _, err := RunCommand(runCtx, Command{
+		Path:   python,
+		Args:   append([]string{e.ScriptPath}, args...),
+		Env:    e.Env,
+		Stderr: stderr,
+	})
+	stderr.Flush()
+
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	if runCtx.Err() == context.DeadlineExceeded {
+		return nil, &TimeoutError{Script: script, Timeout: e.Timeout}
+	}
+	if err != nil {
+		if module := missingModule(stderr.Tail()); module != "" {
+			return nil, &MissingModuleError{Script: script, Modules: []string{module}}
+		}
+		return nil, &ScriptError{Script: script, Err: err, Stderr: stderr.Tail()}
+	}
+
+	envelope, err := ParseEnvelope(stdout)
+	if err != nil {
+		return nil, &OutputError{Script: script, Err: err}
+	}
+	return envelope, nil
+}
+
+// ParseEnvelope 解析并校验脚本输出的结果信封
+func ParseEnvelope(data []byte) (*Envelope, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return nil, errors.New("empty stdout")
+	}
+
+	var envelope Envelope
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&envelope); err != nil {
+		return nil, err
+	}
+	if decoder.More() {
+		return nil, errors.New("unexpected data after envelope")
+	}
+	if envelope.Version != EnvelopeVersion {
+		return nil, fmt.Errorf("unsupported envelope version %d (want %d)", envelope.Version, EnvelopeVersion)
+	}
+	for _, m := range envelope.Metrics {
+		if m.Name == "" {
+			return nil, errors.New("metric without name")
+		}
+	}
+	return &envelope, nil
+}
+
+// moduleNotFound 匹配 Python 的 ModuleNotFoundError
+var moduleNotFound = regexp.MustCompile(`ModuleNotFoundError: No module named '([^']+)'`)
+
+// missingModule 从 stderr 中提取缺失的模块名
+func missingModule(stderr string) string {
+	if match := moduleNotFound.FindStringSubmatch(stderr); match != nil {
+		return match[1]
+	}
+	return ""
+}
+
+// stderrTailLines 是错误信息中保留的 stderr 行数
+const stderrTailLines = 5
+
+// logWriter 将脚本的 stderr 逐行写入日志并保留最后几行
+type logWriter struct {
+	prefix string
+
+	mu      sync.Mutex
+	partial []byte
+	tail    []string
+}
+
+func (w *logWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.partial = append(w.partial, p...)
+	for {
+		i := bytes.IndexByte(w.partial, '\n')
+		if i < 0 {
+			break
+		}
+		w.line(string(w.partial[:i]))
+		w.partial = w.partial[i+1:]
+	}
+	return len(p), nil
+}
+
+// Flush 输出最后一个未以换行结尾的行
+func (w *logWriter) Flush() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if len(w.partial) > 0 {
+		w.line(string(w.partial))
+		w.partial = nil
+	}
+}
+
+// Tail 返回 stderr 的最后几行
+func (w *logWriter) Tail() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return strings.Join(w.tail, "\n")
+}
+
+func (w *logWriter) line(line string) {
+	line = strings.TrimRight(line, "\r")
+	if strings.TrimSpace(line) == "" {
+		return
+	}
+	log.Printf("[%s] %s", w.prefix, line)
+
+	w.tail = append(w.tail, line)
+	if len(w.tail) > stderrTailLines {
+		w.tail = w.tail[1:]
+	}
+}
@@ -0,0 +1,310 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"octane/pkg/benchmark"
+	"octane/pkg/cgroup"
+	"octane/pkg/types"
+	"strconv"
+	"strings"
+	"time"
+)
+
+func init() {
+	benchmark.Register(benchmark.New("memory/bandwidth", "memory",
+		"Sequential, random and copy bandwidth", 40*time.Second, runMemoryBandwidthBenchmark))
+	benchmark.Register(benchmark.New("memory/latency", "memory",
+		"Cache and main memory latency via pointer chasing", 5*time.Second, runMemoryLatencyBenchmark))
+	benchmark.Register(benchmark.New("memory/stability", "memory",
+		"Pattern write/verify stability check", 5*time.Second, runMemoryStabilityBenchmark))
+}
+
+// defaultMemorySize 是未指定大小时的内存测试工作集
+const defaultMemorySize = 512 * 1024 * 1024
+
+// ExecuteMemoryTest 执行内存性能测试
+func ExecuteMemoryTest(ctx context.Context, size string, duration string) (*types.MemoryResults, error) {
+	// 解析测试内存大小，为空时使用默认值
+	bytes := 0
+	if size != "" {
+		var err error
+		if bytes, err = ParseSize(size); err != nil {
+			return nil, err
+		}
+	}
+
+	// 解析持续时间
+	testDuration, err := time.ParseDuration(duration)
+	if err != nil {
+		return nil, fmt.Errorf("invalid duration format: %v", err)
+	}
+
+	list, err := benchmark.Select("memory")
+	if err != nil {
+		return nil, err
+	}
+
+	results := &types.MemoryResults{
+		TestSuite: "octane-memory-test",
+		Duration:  duration,
+	}
+
+	fmt.Printf("Running memory tests on %d MB for %v...\n", memorySize(bytes)>>20, testDuration)
+
+	runs, err := benchmark.RunAll(ctx, list, benchmark.Options{Size: bytes}, testDuration, printProgress)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, run := range runs {
+		applyMemoryMetrics(results, run)
+	}
+
+	return results, nil
+}
+
+// applyMemoryMetrics 将基准测试指标写入 MemoryResults
+func applyMemoryMetrics(results *types.MemoryResults, run *benchmark.Result) {
+	for _, m := range run.Metrics {
+		switch m.Name {
+		case "sequential_read":
+			results.Bandwidth.SequentialRead = m.Value
+		case "sequential_write":
+			results.Bandwidth.SequentialWrite = m.Value
+		case "random_read":
+			results.Bandwidth.RandomRead = m.Value
+		case "random_write":
+			results.Bandwidth.RandomWrite = m.Value
+		case "copy":
+			results.Bandwidth.Copy = m.Value
+		case "l1_cache":
+			results.Latency.L1Cache = m.Value
+		case "l2_cache":
+			results.Latency.L2Cache = m.Value
+		case "l3_cache":
+			results.Latency.L3Cache = m.Value
+		case "main_memory":
+			results.Latency.MainMemory = m.Value
+		case "errors_detected":
+			results.Stability.ErrorsDetected = int(m.Value)
+			results.Stability.TestDuration = run.Duration.Round(time.Millisecond).String()
+		case "passes":
+			results.Stability.Passes = int(m.Value)
+		case "memory_tested":
+			results.Stability.MemoryTested = m.Value
+		}
+	}
+}
+
+// memorySize 返回内存测试工作集大小
+func memorySize(size int) int {
+	if size > 0 {
+		return size
+	}
+	// 带宽测试同时分配源和目标缓冲区，留出一半余量避免触发容器的 OOM
+	if limit := cgroup.Self().Memory; limit > 0 && limit/4 < defaultMemorySize {
+		return int(limit / 4)
+	}
+	return defaultMemorySize
+}
+
+// runMemoryBandwidthBenchmark 运行内存带宽测试
+func runMemoryBandwidthBenchmark(ctx context.Context, opts benchmark.Options) ([]benchmark.Metric, error) {
+	src := make([]byte, memorySize(opts.Size))
+	dst := make([]byte, len(src))
+	part := opts.Duration / 4
+
+	write := runMemoryWriteTest(ctx, src, part)
+	read := runMemoryReadTest(ctx, src, part)
+	copyRate := runMemoryCopyTest(ctx, src, dst, part)
+	randomRead, randomWrite := runMemoryRandomTest(ctx, src, part)
+
+	return []benchmark.Metric{
+		{Name: "sequential_write", Value: write, Unit: "MB/s", HigherIsBetter: true},
+		{Name: "sequential_read", Value: read, Unit: "MB/s", HigherIsBetter: true},
+		{Name: "copy", Value: copyRate, Unit: "MB/s", HigherIsBetter: true},
+		{Name: "random_read", Value: randomRead, Unit: "MB/s", HigherIsBetter: true},
+		{Name: "random_write", Value: randomWrite, Unit: "MB/s", HigherIsBetter: true},
+	}, nil
+}
+
+// runMemoryLatencyBenchmark 使用指针追逐测量各级缓存和主存延迟
+func runMemoryLatencyBenchmark(ctx context.Context, opts benchmark.Options) ([]benchmark.Metric, error) {
+	part := opts.Duration / 4
+
+	return []benchmark.Metric{
+		{Name: "l1_cache", Value: measureChaseLatency(ctx, 16*1024, part), Unit: "ns"},
+		{Name: "l2_cache", Value: measureChaseLatency(ctx, 256*1024, part), Unit: "ns"},
+		{Name: "l3_cache", Value: measureChaseLatency(ctx, 4*1024*1024, part), Unit: "ns"},
+		{Name: "main_memory", Value: measureChaseLatency(ctx, 256*1024*1024, part), Unit: "ns"},
+	}, nil
+}
+
+// runMemoryStabilityBenchmark 写入校验模式并回读
+func runMemoryStabilityBenchmark(ctx context.Context, opts benchmark.Options) ([]benchmark.Metric, error) {
+	buf := make([]byte, memorySize(opts.Size))
+	errors, passes := runMemoryStabilityTest(ctx, buf, opts.Duration)
+
+	return []benchmark.Metric{
+		{Name: "errors_detected", Value: float64(errors), Unit: "errors"},
+		{Name: "passes", Value: float64(passes), Unit: "passes", HigherIsBetter: true},
+		{Name: "memory_tested", Value: float64(len(buf)) / (1024 * 1024), Unit: "MB", HigherIsBetter: true},
+	}, nil
+}
+
+// runMemoryWriteTest 顺序写入测试，返回 MB/s
+func runMemoryWriteTest(ctx context.Context, buf []byte, duration time.Duration) float64 {
+	start := time.Now()
+	total := 0
+	for pass := 0; running(ctx, start, duration); pass++ {
+		value := byte(pass)
+		for i := range buf {
+			buf[i] = value
+		}
+		total += len(buf)
+	}
+
+	return float64(total) / time.Since(start).Seconds() / (1024 * 1024)
+}
+
+// runMemoryReadTest 顺序读取测试，返回 MB/s
+func runMemoryReadTest(ctx context.Context, buf []byte, duration time.Duration) float64 {
+	start := time.Now()
+	total := 0
+	var sum uint64
+	for running(ctx, start, duration) {
+		for i := 0; i < len(buf); i += 8 {
+			sum += uint64(buf[i])
+		}
+		total += len(buf)
+	}
+	_ = sum
+
+	return float64(total) / time.Since(start).Seconds() / (1024 * 1024)
+}
+
+// runMemoryCopyTest 内存拷贝测试，返回 MB/s
+func runMemoryCopyTest(ctx context.Context, src, dst []byte, duration time.Duration) float64 {
+	start := time.Now()
+	total := 0
+	for running(ctx, start, duration) {
+		copy(dst, src)
+		total += len(src)
+	}
+
+	return float64(total) / time.Since(start).Seconds() / (1024 * 1024)
+}
+
+// runMemoryRandomTest 随机读写测试（64字节粒度），返回读写 MB/s
+func runMemoryRandomTest(ctx context.Context, buf []byte, duration time.Duration) (float64, float64) {
+	const lineSize = 64
+	lines := len(buf) / lineSize
+	if lines == 0 {
+		return 0, 0
+	}
+	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
+
+	measure := func(write bool) float64 {
+		start := time.Now()
+		accesses := 0
+		var sum byte
+		for running(ctx, start, duration/2) {
+			for i := 0; i < 100000; i++ {
+				offset := rng.Intn(lines) * lineSize
+				if write {
+					buf[offset] = byte(i)
+				} else {
+					sum += buf[offset]
+				}
+			}
+			accesses += 100000
+		}
+		_ = sum
+		return float64(accesses*lineSize) / time.Since(start).Seconds() / (1024 * 1024)
+	}
+
+	return measure(false), measure(true)
+}
+
+// measureChaseLatency 在指定大小的工作集上执行随机指针追逐，返回每次访问的纳秒数
+func measureChaseLatency(ctx context.Context, workingSet int, duration time.Duration) float64 {
+	n := workingSet / 8
+	if n < 2 {
+		return 0
+	}
+
+	// 构造一个随机的单环排列，避免硬件预取
+	next := make([]int, n)
+	perm := rand.Perm(n)
+	for i := 0; i < n-1; i++ {
+		next[perm[i]] = perm[i+1]
+	}
+	next[perm[n-1]] = perm[0]
+
+	start := time.Now()
+	steps := 0
+	idx := 0
+	for running(ctx, start, duration) {
+		for i := 0; i < 10000; i++ {
+			idx = next[idx]
+		}
+		steps += 10000
+	}
+	_ = idx
+	if steps == 0 {
+		return 0
+	}
+
+	return float64(time.Since(start).Nanoseconds()) / float64(steps)
+}
+
+// runMemoryStabilityTest 写入校验模式并回读，返回错误数和完成的轮数
+func runMemoryStabilityTest(ctx context.Context, buf []byte, duration time.Duration) (int, int) {
+	patterns := []byte{0x00, 0xFF, 0xAA, 0x55}
+	errors := 0
+	passes := 0
+
+	start := time.Now()
+	for running(ctx, start, duration) {
+		pattern := patterns[passes%len(patterns)]
+		for i := range buf {
+			buf[i] = pattern
+		}
+		for i := range buf {
+			if buf[i] != pattern {
+				errors++
+			}
+		}
+		passes++
+	}
+
+	return errors, passes
+}
+
+// ParseSize 解析形如 "512MB"、"1GB"、"4K" 的大小字符串，返回字节数
+func ParseSize(size string) (int, error) {
+	s := strings.ToUpper(strings.TrimSpace(size))
+	s = strings.TrimSuffix(s, "B")
+	s = strings.TrimSuffix(s, "I")
+
+	multiplier := 1
+	switch {
+	case strings.HasSuffix(s, "K"):
+		multiplier = 1024
+	case strings.HasSuffix(s, "M"):
+		multiplier = 1024 * 1024
+	case strings.HasSuffix(s, "G"):
+		multiplier = 1024 * 1024 * 1024
+	}
+	if multiplier > 1 {
+		s = s[:len(s)-1]
+	}
+
+	value, err := strconv.ParseFloat(s, 64)
+	if err != nil || value <= 0 {
+		return 0, fmt.Errorf("invalid size format: %s", size)
+	}
+	return int(value * float64(multiplier)), nil
+}
@@ -0,0 +1,8 @@
+//go:build windows
+
+package executor
+
+import "os/exec"
+
+// setProcessGroup 在 Windows 上使用默认的终止行为
+func setProcessGroup(cmd *exec.Cmd) {}
@@ -1,34 +1,178 @@
 package executor
 
 import (
-    "os/exec"
-    "path/filepath"
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"sort"
+	"strings"
 )
 
-// PythonExecutor 结构体用于执行Python脚本
-type PythonExecutor struct {
-    ScriptPath string // Python脚本的路径
+// PythonEnv 是显式指定 Python 解释器的环境变量
+const PythonEnv = "OCTANE_PYTHON"
+
+// RequirementsFile 是脚本目录中依赖清单的文件名
+const RequirementsFile = "requirements.txt"
+
+// importNames 记录包名与导入名不一致的依赖
+var importNames = map[string]string{
+	"pyyaml":        "yaml",
+	"py_cpuinfo":    "cpuinfo",
+	"speedtest_cli": "speedtest",
+}
+
+// FindPython 查找 Python 解释器。
+// 依次尝试 OCTANE_PYTHON、当前激活的 venv、工作目录下的 .venv/venv，最后是 PATH 中的 python3。
+// 不查找脚本解压目录中的 venv，解压目录中只应有二进制自带的文件。
+func FindPython() (string, error) {
+	if python := os.Getenv(PythonEnv); python != "" {
+		path, err := exec.LookPath(python)
+		if err != nil {
+			return "", &InterpreterError{Tried: []string{PythonEnv + "=" + python}}
+		}
+		return path, nil
+	}
+
+	var tried []string
+	var venvs []string
+	if venv := os.Getenv("VIRTUAL_ENV"); venv != "" {
+		venvs = append(venvs, venv)
+	}
+	venvs = append(venvs, ".venv", "venv")
+	for _, venv := range venvs {
+		python := venvPython(venv)
+		tried = append(tried, python)
+		if info, err := os.Stat(python); err == nil && !info.IsDir() {
+			return python, nil
+		}
+	}
+
+	tried = append(tried, "python3")
+	if path, err := exec.LookPath("python3"); err == nil {
+		return path, nil
+	}
+	return "", &InterpreterError{Tried: tried}
+}
+
+// venvPython 返回虚拟环境中的解释器路径
+func venvPython(venv string) string {
+	if runtime.GOOS == "windows" {
+		return filepath.Join(venv, "Scripts", "python.exe")
+	}
+	return filepath.Join(venv, "bin", "python")
+}
+
+// ReadRequirements 读取 requirements.txt，返回依赖的导入名
+func ReadRequirements(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var modules []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = strings.TrimSpace(line[:i])
+		}
+		if line == "" || strings.HasPrefix(line, "-") {
+			continue
+		}
+		// 去掉版本约束和 extras，例如 torch==2.0.1、requests[socks]>=2
+		fields := strings.FieldsFunc(line, func(r rune) bool {
+			return strings.ContainsRune("=<>!~[; ", r)
+		})
+		// "==1.0"、">=" 等没有包名的行跳过
+		if len(fields) == 0 || !strings.HasPrefix(line, fields[0]) {
+			continue
+		}
+		modules = append(modules, importName(fields[0]))
+	}
+	return modules, scanner.Err()
+}
+
+// importName 将包名规范化为导入名，保留大小写（如 GPUtil）
+func importName(pkg string) string {
+	name := strings.ReplaceAll(pkg, "-", "_")
+	if mapped, ok := importNames[strings.ToLower(name)]; ok {
+		return mapped
+	}
+	return name
 }
 
-// NewPythonExecutor 创建一个新的Python执行器
-func NewPythonExecutor(scriptPath string) *PythonExecutor {
-    return &PythonExecutor{
-        ScriptPath: scriptPath,
-    }
+// importPattern 匹配脚本中的顶层 import 语句
+var importPattern = regexp.MustCompile(`(?m)^\s*(?:import|from)\s+([A-Za-z_][A-Za-z0-9_]*)`)
+
+// scriptImports 返回脚本导入的顶层模块
+func scriptImports(script string) ([]string, error) {
+	data, err := os.ReadFile(script)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	var imports []string
+	for _, match := range importPattern.FindAllStringSubmatch(string(data), -1) {
+		if !seen[match[1]] {
+			seen[match[1]] = true
+			imports = append(imports, match[1])
+		}
+	}
+	return imports, nil
 }
 
-// Execute 执行Python脚本并返回输出
-func (pe *PythonExecutor) Execute(args ...string) (string, error) {
-    cmd := exec.Command("python3", append([]string{pe.ScriptPath}, args...)...)
-    output, err := cmd.CombinedOutput()
-    return string(output), err
+// checkModulesScript 打印参数中无法导入的模块
+const checkModulesScript = `import importlib.util, sys
+print(" ".join(m for m in sys.argv[1:] if importlib.util.find_spec(m) is None))`
+
+// MissingModules 返回脚本导入的、列在 requirements.txt 中但未安装的模块
+func MissingModules(ctx context.Context, python, script, requirements string) ([]string, error) {
+	required, err := ReadRequirements(requirements)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	imports, err := scriptImports(script)
+	if err != nil {
+		return nil, err
+	}
+
+	var modules []string
+	for _, module := range imports {
+		for _, req := range required {
+			if strings.EqualFold(module, req) {
+				modules = append(modules, module)
+				break
+			}
+		}
+	}
+	return CheckModules(ctx, python, modules)
 }
 
-// GetScriptPath 获取Python脚本的绝对路径
-func (pe *PythonExecutor) GetScriptPath() (string, error) {
-    absPath, err := filepath.Abs(pe.ScriptPath)
-    if err != nil {
-        return "", err
-    }
-    return absPath, nil
-}
\ No newline at end of file
+// CheckModules 返回解释器中无法导入的模块
+func CheckModules(ctx context.Context, python string, modules []string) ([]string, error) {
+	if len(modules) == 0 {
+		return nil, nil
+	}
+
+	stdout, stderr, err := RunCommand(ctx, Command{
+		Path: python,
+		Args: append([]string{"-c", checkModulesScript}, modules...),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("checking python modules: %v: %s", err, strings.TrimSpace(string(stderr)))
+	}
+
+	missing := strings.Fields(string(stdout))
+	sort.Strings(missing)
+	return missing, nil
+}
@@ -0,0 +1,37 @@
+package executor
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestReadRequirements(t *testing.T) {
+	requirements := `# benchmark dependencies
+numpy>=1.24
+torch==2.0.1  # CUDA build
+requests[socks]>=2
+PyYAML
+py-cpuinfo~=9.0
+GPUtil; platform_system != "Windows"
+-r extra.txt
+--index-url https://download.pytorch.org/whl/cu121
+==1.0
+>=
+
+`
+	path := filepath.Join(t.TempDir(), "requirements.txt")
+	if err := os.WriteFile(path, []byte(requirements), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	modules, err := ReadRequirements(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"numpy", "torch", "requests", "yaml", "cpuinfo", "GPUtil"}
+	if !slices.Equal(modules, want) {
+		t.Errorf("ReadRequirements = %q, want %q", modules, want)
+	}
+}
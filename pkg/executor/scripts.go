@@ -0,0 +1,73 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"octane/pkg/benchmark"
+	"octane/pkg/types"
+	"path/filepath"
+	"time"
+)
+
+func init() {
+	benchmark.Register(benchmark.New("gpu/python", "gpu",
+		"GPU compute test via scripts/python/gpu_test.py", time.Minute, scriptBenchmark("gpu_test.py")))
+	benchmark.Register(benchmark.New("network/python", "network",
+		"Network speed test via scripts/python/network_test.py", time.Minute, scriptBenchmark("network_test.py")))
+}
+
+// scriptBenchmark 返回执行指定 Python 脚本的基准测试函数
+func scriptBenchmark(script string) benchmark.RunFunc {
+	return func(ctx context.Context, opts benchmark.Options) ([]benchmark.Metric, error) {
+		envelope, err := NewExecutor(filepath.Join(opts.ScriptDir, script)).Run(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return envelope.Metrics, nil
+	}
+}
+
+// ExecuteGPUTest 通过 gpu_test.py 执行GPU计算测试
+func ExecuteGPUTest(ctx context.Context, scriptDir string) (*types.GPUResults, error) {
+	run, err := runScriptBenchmark(ctx, "gpu/python", scriptDir)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.GPUResults{
+		TestSuite: "octane-gpu-test",
+		Duration:  run.Duration.Round(time.Second).String(),
+	}, nil
+}
+
+// ExecuteNetworkTest 通过 network_test.py 执行网络测试
+func ExecuteNetworkTest(ctx context.Context, scriptDir string) (*types.NetworkResults, error) {
+	run, err := runScriptBenchmark(ctx, "network/python", scriptDir)
+	if err != nil {
+		return nil, err
+	}
+
+	results := &types.NetworkResults{
+		TestSuite: "octane-network-test",
+		Duration:  run.Duration.Round(time.Second).String(),
+	}
+	if download := run.Value("download"); download > 0 {
+		results.Bandwidth.International = map[string]types.BandwidthResult{
+			"speedtest": {
+				Download: download,
+				Upload:   run.Value("upload"),
+				Latency:  run.Value("latency"),
+			},
+		}
+	}
+	return results, nil
+}
+
+// runScriptBenchmark 运行已注册的脚本基准测试
+func runScriptBenchmark(ctx context.Context, name string, scriptDir string) (*benchmark.Result, error) {
+	b, ok := benchmark.Get(name)
+	if !ok {
+		return nil, fmt.Errorf("benchmark %s not registered", name)
+	}
+	return benchmark.Run(ctx, b, benchmark.Options{ScriptDir: scriptDir})
+}
@@ -0,0 +1,298 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"octane/pkg/benchmark"
+	"octane/pkg/types"
+	"os"
+	"sort"
+	"time"
+)
+
+func init() {
+	benchmark.Register(benchmark.New("storage/sequential", "storage",
+		"Sequential 1M and 4K read/write throughput", 10*time.Second, runStorageSequentialBenchmark))
+	benchmark.Register(benchmark.New("storage/random", "storage",
+		"Random 4K IOPS and latency", 30*time.Second, runStorageRandomBenchmark))
+}
+
+// defaultStorageSize 是未指定大小时的测试文件大小
+const defaultStorageSize = 256 * 1024 * 1024
+
+// ExecuteStorageTest 在指定目录下执行存储性能测试
+func ExecuteStorageTest(ctx context.Context, dir string, size string, duration string) (*types.StorageResults, error) {
+	fileSize, err := ParseSize(size)
+	if err != nil {
+		return nil, err
+	}
+
+	testDuration, err := time.ParseDuration(duration)
+	if err != nil {
+		return nil, fmt.Errorf("invalid duration format: %v", err)
+	}
+
+	list, err := benchmark.Select("storage")
+	if err != nil {
+		return nil, err
+	}
+
+	fmt.Printf("Running storage tests on %s with a %s test file...\n", dir, size)
+
+	runs, err := benchmark.RunAll(ctx, list, benchmark.Options{Dir: dir, Size: fileSize}, testDuration, printProgress)
+	if err != nil {
+		return nil, err
+	}
+
+	device := types.DeviceResults{Name: dir}
+	for _, run := range runs {
+		applyStorageMetrics(&device, run)
+	}
+
+	return &types.StorageResults{
+		TestSuite: "octane-storage-test",
+		Duration:  duration,
+		Devices:   []types.DeviceResults{device},
+	}, nil
+}
+
+// applyStorageMetrics 将基准测试指标写入 DeviceResults
+func applyStorageMetrics(device *types.DeviceResults, run *benchmark.Result) {
+	for _, m := range run.Metrics {
+		switch m.Name {
+		case "read_1mb":
+			device.Tests.Sequential.Read1MB = m.Value
+		case "write_1mb":
+			device.Tests.Sequential.Write1MB = m.Value
+		case "read_4k":
+			device.Tests.Sequential.Read4K = m.Value
+		case "write_4k":
+			device.Tests.Sequential.Write4K = m.Value
+		case "read_4k_iops":
+			device.Tests.Random.Read4KIops = m.Value
+		case "write_4k_iops":
+			device.Tests.Random.Write4KIops = m.Value
+		case "mixed_70_30":
+			device.Tests.Random.Mixed70_30 = m.Value
+		case "read_avg":
+			device.Tests.Latency.ReadAvg = m.Value
+		case "write_avg":
+			device.Tests.Latency.WriteAvg = m.Value
+		case "read_99p":
+			device.Tests.Latency.Read99p = m.Value
+		case "write_99p":
+			device.Tests.Latency.Write99p = m.Value
+		}
+	}
+}
+
+// createTestFile 在测试目录下创建临时测试文件，调用方负责删除
+func createTestFile(dir string) (*os.File, error) {
+	if dir == "" {
+		dir = os.TempDir()
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create test directory: %v", err)
+	}
+
+	file, err := os.CreateTemp(dir, "octane-storage-*.tmp")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create test file: %v", err)
+	}
+	return file, nil
+}
+
+// removeTestFile 关闭并删除测试文件
+func removeTestFile(file *os.File) {
+	file.Close()
+	os.Remove(file.Name())
+}
+
+// storageSize 返回测试文件大小
+func storageSize(size int) int {
+	if size <= 0 {
+		return defaultStorageSize
+	}
+	return size
+}
+
+// runStorageSequentialBenchmark 顺序读写测试
+func runStorageSequentialBenchmark(ctx context.Context, opts benchmark.Options) ([]benchmark.Metric, error) {
+	file, err := createTestFile(opts.Dir)
+	if err != nil {
+		return nil, err
+	}
+	defer removeTestFile(file)
+
+	size := storageSize(opts.Size)
+	write1M, err := runSequentialWrite(ctx, file, size, 1024*1024)
+	if err != nil {
+		return nil, err
+	}
+	read1M, err := runSequentialRead(ctx, file, size, 1024*1024)
+	if err != nil {
+		return nil, err
+	}
+	write4K, err := runSequentialWrite(ctx, file, size/16, 4096)
+	if err != nil {
+		return nil, err
+	}
+	read4K, err := runSequentialRead(ctx, file, size/16, 4096)
+	if err != nil {
+		return nil, err
+	}
+
+	return []benchmark.Metric{
+		{Name: "write_1mb", Value: write1M, Unit: "MB/s", HigherIsBetter: true},
+		{Name: "read_1mb", Value: read1M, Unit: "MB/s", HigherIsBetter: true},
+		{Name: "write_4k", Value: write4K, Unit: "MB/s", HigherIsBetter: true},
+		{Name: "read_4k", Value: read4K, Unit: "MB/s", HigherIsBetter: true},
+	}, nil
+}
+
+// runStorageRandomBenchmark 4K随机读写测试
+func runStorageRandomBenchmark(ctx context.Context, opts benchmark.Options) ([]benchmark.Metric, error) {
+	file, err := createTestFile(opts.Dir)
+	if err != nil {
+		return nil, err
+	}
+	defer removeTestFile(file)
+
+	// 预先写满测试文件，避免读取空洞
+	size := storageSize(opts.Size)
+	if _, err := runSequentialWrite(ctx, file, size, 1024*1024); err != nil {
+		return nil, err
+	}
+
+	part := opts.Duration / 3
+	readLat, err := runRandomIO(ctx, file, size, part, 1.0)
+	if err != nil {
+		return nil, err
+	}
+	writeLat, err := runRandomIO(ctx, file, size, part, 0.0)
+	if err != nil {
+		return nil, err
+	}
+	mixedLat, err := runRandomIO(ctx, file, size, part, 0.7)
+	if err != nil {
+		return nil, err
+	}
+
+	return []benchmark.Metric{
+		{Name: "read_4k_iops", Value: iops(readLat, part), Unit: "IOPS", HigherIsBetter: true},
+		{Name: "write_4k_iops", Value: iops(writeLat, part), Unit: "IOPS", HigherIsBetter: true},
+		{Name: "mixed_70_30", Value: iops(mixedLat, part), Unit: "IOPS", HigherIsBetter: true},
+		{Name: "read_avg", Value: averageMillis(readLat), Unit: "ms"},
+		{Name: "write_avg", Value: averageMillis(writeLat), Unit: "ms"},
+		{Name: "read_99p", Value: percentileMillis(readLat, 99), Unit: "ms"},
+		{Name: "write_99p", Value: percentileMillis(writeLat, 99), Unit: "ms"},
+	}, nil
+}
+
+// runSequentialWrite 以给定块大小顺序写入并同步到磁盘，返回 MB/s
+func runSequentialWrite(ctx context.Context, file *os.File, total int, blockSize int) (float64, error) {
+	block := make([]byte, blockSize)
+	rand.Read(block)
+
+	start := time.Now()
+	written := 0
+	for written < total {
+		if err := ctx.Err(); err != nil {
+			return 0, err
+		}
+		n, err := file.WriteAt(block, int64(written))
+		if err != nil {
+			return 0, fmt.Errorf("sequential write failed: %v", err)
+		}
+		written += n
+	}
+	if err := file.Sync(); err != nil {
+		return 0, fmt.Errorf("sync failed: %v", err)
+	}
+
+	return float64(written) / time.Since(start).Seconds() / (1024 * 1024), nil
+}
+
+// runSequentialRead 以给定块大小顺序读取，返回 MB/s
+func runSequentialRead(ctx context.Context, file *os.File, total int, blockSize int) (float64, error) {
+	block := make([]byte, blockSize)
+
+	start := time.Now()
+	read := 0
+	for read < total {
+		if err := ctx.Err(); err != nil {
+			return 0, err
+		}
+		n, err := file.ReadAt(block, int64(read))
+		if n == 0 && err != nil {
+			return 0, fmt.Errorf("sequential read failed: %v", err)
+		}
+		read += n
+	}
+
+	return float64(read) / time.Since(start).Seconds() / (1024 * 1024), nil
+}
+
+// runRandomIO 执行4K随机读写，readRatio 为读操作占比，返回每次操作的延迟
+func runRandomIO(ctx context.Context, file *os.File, total int, duration time.Duration, readRatio float64) ([]time.Duration, error) {
+	const blockSize = 4096
+	blocks := total / blockSize
+	if blocks == 0 {
+		return nil, fmt.Errorf("test file too small for 4K random IO")
+	}
+
+	block := make([]byte, blockSize)
+	rand.Read(block)
+	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
+
+	var latencies []time.Duration
+	start := time.Now()
+	for running(ctx, start, duration) {
+		offset := int64(rng.Intn(blocks)) * blockSize
+		opStart := time.Now()
+		if rng.Float64() < readRatio {
+			if _, err := file.ReadAt(block, offset); err != nil {
+				return nil, fmt.Errorf("random read failed: %v", err)
+			}
+		} else {
+			if _, err := file.WriteAt(block, offset); err != nil {
+				return nil, fmt.Errorf("random write failed: %v", err)
+			}
+		}
+		latencies = append(latencies, time.Since(opStart))
+	}
+
+	return latencies, nil
+}
+
+// iops 根据操作数和持续时间计算IOPS
+func iops(latencies []time.Duration, duration time.Duration) float64 {
+	if duration <= 0 {
+		return 0
+	}
+	return float64(len(latencies)) / duration.Seconds()
+}
+
+// averageMillis 计算平均延迟（毫秒）
+func averageMillis(latencies []time.Duration) float64 {
+	if len(latencies) == 0 {
+		return 0
+	}
+	var total time.Duration
+	for _, l := range latencies {
+		total += l
+	}
+	return float64(total) / float64(len(latencies)) / float64(time.Millisecond)
+}
+
+// percentileMillis 计算延迟的百分位数（毫秒）
+func percentileMillis(latencies []time.Duration, p float64) float64 {
+	if len(latencies) == 0 {
+		return 0
+	}
+	sorted := append([]time.Duration(nil), latencies...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	idx := int(float64(len(sorted)-1) * p / 100)
+	return float64(sorted[idx]) / float64(time.Millisecond)
+}
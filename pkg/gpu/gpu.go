@@ -0,0 +1,178 @@
+package gpu
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"octane/pkg/executor"
+	"octane/pkg/types"
+	"strconv"
+	"strings"
+)
+
+// 支持的遥测工具
+const (
+	ToolNvidiaSMI = "nvidia-smi"
+	ToolROCmSMI   = "rocm-smi"
+)
+
+// rocmArgs 是 rocm-smi 输出遥测所需的参数，不加 --show* 时 --json 只输出摘要
+var rocmArgs = []string{
+	"--showproductname", "--showbus", "--showdriverversion", "--showtemp",
+	"--showpower", "--showmaxpower", "--showclocks", "--showuse",
+	"--showmeminfo", "vram", "--json",
+}
+
+// Snapshot 是一次查询得到的所有显卡读数
+type Snapshot struct {
+	Driver string // 驱动版本
+	CUDA   string // CUDA 版本，仅 NVIDIA
+	GPUs   []Reading
+}
+
+// Reading 是一块显卡的读数，工具未提供的值为 0
+type Reading struct {
+	Index        int
+	PCIBus       string // 规范化为 0000:01:00.0
+	Name         string
+	Architecture string
+
+	Temperature float64 // °C
+	Power       float64 // W，当前功耗
+	PowerLimit  float64 // W，默认功耗上限（TDP），没有时为当前上限
+	Utilization float64 // %
+
+	GraphicsClock    int // MHz，当前频率
+	MemoryClock      int // MHz
+	BaseClock        int // MHz，默认应用频率
+	MaxGraphicsClock int // MHz
+	MaxMemoryClock   int // MHz
+
+	MemoryTotal int // MB
+	MemoryUsed  int // MB
+}
+
+// Source 是遥测读数的来源，通常为 Tool
+type Source interface {
+	Name() string
+	Query(ctx context.Context) (*Snapshot, error)
+}
+
+// Tool 是找到的遥测工具
+type Tool struct {
+	Path string
+	Kind string // nvidia-smi 或 rocm-smi
+}
+
+// Find 依次查找 nvidia-smi 和 rocm-smi，优先使用 toolsDir 中的可执行文件
+func Find(toolsDir string) (Tool, error) {
+	for _, kind := range []string{ToolNvidiaSMI, ToolROCmSMI} {
+		if path, err := executor.FindTool(toolsDir, kind); err == nil {
+			return Tool{Path: path, Kind: kind}, nil
+		}
+	}
+	return Tool{}, errors.New("neither nvidia-smi nor rocm-smi was found")
+}
+
+// Name 返回工具名称
+func (t Tool) Name() string {
+	return t.Kind
+}
+
+// Query 运行工具并解析输出
+func (t Tool) Query(ctx context.Context) (*Snapshot, error) {
+	args := []string{"-q", "-x"}
+	if t.Kind == ToolROCmSMI {
+		args = rocmArgs
+	}
+	stdout, stderr, err := executor.RunCommand(ctx, executor.Command{Path: t.Path, Args: args})
+	if err != nil {
+		if msg := strings.TrimSpace(string(stderr)); msg != "" {
+			return nil, fmt.Errorf("%s: %v: %s", t.Kind, err, msg)
+		}
+		return nil, fmt.Errorf("%s: %v", t.Kind, err)
+	}
+
+	switch t.Kind {
+	case ToolNvidiaSMI:
+		return ParseNvidiaSMI(stdout)
+	case ToolROCmSMI:
+		return ParseROCmSMI(stdout)
+	}
+	return nil, fmt.Errorf("unsupported GPU tool %q", t.Kind)
+}
+
+// Apply 将读数合并到 sysfs 得到的显卡列表中，按 PCI 地址匹配，未匹配的读数追加到末尾
+func Apply(gpus []types.GPUInfo, snapshot *Snapshot) []types.GPUInfo {
+	for _, reading := range snapshot.GPUs {
+		i := -1
+		for j := range gpus {
+			if reading.PCIBus != "" && NormalizeBus(gpus[j].PCIBus) == reading.PCIBus {
+				i = j
+				break
+			}
+		}
+		if i < 0 {
+			gpus = append(gpus, types.GPUInfo{Index: reading.Index, PCIBus: reading.PCIBus})
+			i = len(gpus) - 1
+		}
+
+		gpu := &gpus[i]
+		if gpu.Name == "" || strings.Contains(gpu.Name, " device ") {
+			// sysfs 中未收录的设备只有 PCI ID，改用工具给出的型号
+			gpu.Name = reading.Name
+		}
+		if reading.Architecture != "" {
+			gpu.Architecture = reading.Architecture
+		}
+		if snapshot.Driver != "" {
+			gpu.Driver.Version = snapshot.Driver
+		}
+		gpu.Driver.CUDAVersion = snapshot.CUDA
+		gpu.Temperature = int(reading.Temperature + 0.5)
+		gpu.Power.Current = int(reading.Power + 0.5)
+		if reading.PowerLimit > 0 {
+			gpu.Power.TDP = int(reading.PowerLimit + 0.5)
+		}
+		if reading.BaseClock > 0 {
+			gpu.Frequencies.Base = reading.BaseClock
+		}
+		if reading.MaxGraphicsClock > 0 {
+			gpu.Frequencies.Boost = reading.MaxGraphicsClock
+		}
+		if reading.MaxMemoryClock > 0 {
+			gpu.Frequencies.Memory = reading.MaxMemoryClock
+		}
+		if reading.MemoryTotal > 0 {
+			gpu.Memory.Total = reading.MemoryTotal
+		}
+	}
+	return gpus
+}
+
+// NormalizeBus 将 nvidia-smi 的 "00000000:01:00.0" 等地址统一为 sysfs 中的 "0000:01:00.0"
+func NormalizeBus(address string) string {
+	address = strings.ToLower(strings.TrimSpace(address))
+	domain, rest, ok := strings.Cut(address, ":")
+	if !ok || strings.Count(rest, ":") != 1 {
+		return address
+	}
+	if len(domain) > 4 {
+		domain = domain[len(domain)-4:]
+	}
+	return strings.Repeat("0", 4-len(domain)) + domain + ":" + rest
+}
+
+// number 取出 "62.45 W"、"1410 MHz"、"(800Mhz)" 等值开头的数字，"N/A" 等无法解析时返回 0
+func number(s string) float64 {
+	s = strings.TrimLeft(strings.TrimSpace(s), "(")
+	end := 0
+	for end < len(s) && (s[end] >= '0' && s[end] <= '9' || s[end] == '.' || s[end] == '-' && end == 0) {
+		end++
+	}
+	v, err := strconv.ParseFloat(s[:end], 64)
+	if err != nil {
+		return 0
+	}
+	return v
+}
@@ -0,0 +1,94 @@
+package gpu
+
+import (
+	"encoding/xml"
+	"fmt"
+)
+
+// nvidiaLog 是 nvidia-smi -q -x 输出中用到的部分
+type nvidiaLog struct {
+	DriverVersion string      `xml:"driver_version"`
+	CUDAVersion   string      `xml:"cuda_version"`
+	GPUs          []nvidiaGPU `xml:"gpu"`
+}
+
+type nvidiaGPU struct {
+	ID           string `xml:"id,attr"`
+	ProductName  string `xml:"product_name"`
+	Architecture string `xml:"product_architecture"`
+	BusID        string `xml:"pci>pci_bus_id"`
+
+	MemoryTotal string `xml:"fb_memory_usage>total"`
+	MemoryUsed  string `xml:"fb_memory_usage>used"`
+	Utilization string `xml:"utilization>gpu_util"`
+	Temperature string `xml:"temperature>gpu_temp"`
+
+	// 530 之后的驱动使用 gpu_power_readings，之前为 power_readings
+	Power       nvidiaPower `xml:"gpu_power_readings"`
+	LegacyPower nvidiaPower `xml:"power_readings"`
+
+	Clocks        nvidiaClocks `xml:"clocks"`
+	DefaultClocks nvidiaClocks `xml:"default_applications_clocks"`
+	MaxClocks     nvidiaClocks `xml:"max_clocks"`
+}
+
+type nvidiaPower struct {
+	Draw         string `xml:"power_draw"`
+	Limit        string `xml:"power_limit"`
+	CurrentLimit string `xml:"current_power_limit"`
+	DefaultLimit string `xml:"default_power_limit"`
+}
+
+type nvidiaClocks struct {
+	Graphics string `xml:"graphics_clock"`
+	Memory   string `xml:"mem_clock"`
+}
+
+// ParseNvidiaSMI 解析 nvidia-smi -q -x 的 XML 输出，显卡按输出顺序编号
+func ParseNvidiaSMI(data []byte) (*Snapshot, error) {
+	var log nvidiaLog
+	if err := xml.Unmarshal(data, &log); err != nil {
+		return nil, fmt.Errorf("parsing nvidia-smi output: %v", err)
+	}
+	if len(log.GPUs) == 0 {
+		return nil, fmt.Errorf("nvidia-smi reported no GPUs")
+	}
+
+	snapshot := &Snapshot{Driver: log.DriverVersion, CUDA: log.CUDAVersion}
+	for i, g := range log.GPUs {
+		bus := g.BusID
+		if bus == "" {
+			bus = g.ID
+		}
+		power := g.Power
+		if number(power.Draw) == 0 {
+			power = g.LegacyPower
+		}
+		limit := number(power.DefaultLimit)
+		if limit == 0 {
+			limit = number(power.CurrentLimit)
+		}
+		if limit == 0 {
+			limit = number(power.Limit)
+		}
+
+		snapshot.GPUs = append(snapshot.GPUs, Reading{
+			Index:            i,
+			PCIBus:           NormalizeBus(bus),
+			Name:             g.ProductName,
+			Architecture:     g.Architecture,
+			Temperature:      number(g.Temperature),
+			Power:            number(power.Draw),
+			PowerLimit:       limit,
+			Utilization:      number(g.Utilization),
+			GraphicsClock:    int(number(g.Clocks.Graphics)),
+			MemoryClock:      int(number(g.Clocks.Memory)),
+			BaseClock:        int(number(g.DefaultClocks.Graphics)),
+			MaxGraphicsClock: int(number(g.MaxClocks.Graphics)),
+			MaxMemoryClock:   int(number(g.MaxClocks.Memory)),
+			MemoryTotal:      int(number(g.MemoryTotal)),
+			MemoryUsed:       int(number(g.MemoryUsed)),
+		})
+	}
+	return snapshot, nil
+}
@@ -0,0 +1,91 @@
+package gpu
+
+import (
+	"os"
+	"testing"
+)
+
+func TestParseNvidiaSMI(t *testing.T) {
+	tests := []struct {
+		file   string
+		driver string
+		cuda   string
+		gpus   []Reading
+	}{
+		{
+			file:   "testdata/nvidia-smi-q.xml",
+			driver: "535.129.03",
+			cuda:   "12.2",
+			gpus: []Reading{
+				{
+					Index: 0, PCIBus: "0000:07:00.0", Name: "NVIDIA A100-SXM4-80GB", Architecture: "Ampere",
+					Temperature: 61, Power: 312.58, PowerLimit: 400, Utilization: 87,
+					GraphicsClock: 1410, MemoryClock: 1593, BaseClock: 1275, MaxGraphicsClock: 1410, MaxMemoryClock: 1593,
+					MemoryTotal: 81920, MemoryUsed: 40962,
+				},
+				{
+					// 当前上限被调低到 350 W，TDP 仍取默认上限
+					Index: 1, PCIBus: "0000:0f:00.0", Name: "NVIDIA A100-SXM4-80GB", Architecture: "Ampere",
+					Temperature: 34, Power: 62.45, PowerLimit: 400, Utilization: 0,
+					GraphicsClock: 210, MemoryClock: 1593, BaseClock: 1275, MaxGraphicsClock: 1410, MaxMemoryClock: 1593,
+					MemoryTotal: 81920, MemoryUsed: 4,
+				},
+			},
+		},
+		{
+			// 530 之前的驱动只有 power_readings
+			file:   "testdata/nvidia-smi-q-legacy.xml",
+			driver: "470.161.03",
+			cuda:   "11.4",
+			gpus: []Reading{
+				{
+					Index: 0, PCIBus: "0000:3b:00.0", Name: "Tesla T4",
+					Temperature: 38, Power: 9.84, PowerLimit: 70, Utilization: 0,
+					GraphicsClock: 300, MemoryClock: 405, BaseClock: 585, MaxGraphicsClock: 1590, MaxMemoryClock: 5001,
+					MemoryTotal: 15109, MemoryUsed: 0,
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			data, err := os.ReadFile(tt.file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			snapshot, err := ParseNvidiaSMI(data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if snapshot.Driver != tt.driver || snapshot.CUDA != tt.cuda {
+				t.Errorf("driver %q CUDA %q, want %q %q", snapshot.Driver, snapshot.CUDA, tt.driver, tt.cuda)
+			}
+			checkReadings(t, snapshot.GPUs, tt.gpus)
+		})
+	}
+}
+
+func TestParseNvidiaSMIErrors(t *testing.T) {
+	for name, data := range map[string]string{
+		"not xml": "NVIDIA-SMI has failed because it couldn't communicate with the NVIDIA driver.",
+		"no gpus": `<?xml version="1.0" ?><nvidia_smi_log><driver_version>535.129.03</driver_version><attached_gpus>0</attached_gpus></nvidia_smi_log>`,
+	} {
+		if _, err := ParseNvidiaSMI([]byte(data)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+// checkReadings 逐块比较显卡读数
+func checkReadings(t *testing.T, got, want []Reading) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("got %d GPUs, want %d: %+v", len(got), len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("GPU %d:\n got  %+v\n want %+v", i, got[i], want[i])
+		}
+	}
+}
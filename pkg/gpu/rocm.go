@@ -0,0 +1,93 @@
+package gpu
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ParseROCmSMI 解析 rocm-smi --json 的输出。输出中每块显卡是一个 "cardN" 对象，
+// "system" 对象包含驱动版本。各版本 rocm-smi 的键名大小写和措辞不同，按关键字匹配。
+func ParseROCmSMI(data []byte) (*Snapshot, error) {
+	var doc map[string]map[string]any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("parsing rocm-smi output: %v", err)
+	}
+
+	snapshot := &Snapshot{}
+	for key, value := range lowerKeys(doc["system"]) {
+		if strings.Contains(key, "driver version") {
+			snapshot.Driver = value
+		}
+	}
+
+	for name, fields := range doc {
+		index, err := strconv.Atoi(strings.TrimPrefix(name, "card"))
+		if !strings.HasPrefix(name, "card") || err != nil {
+			continue
+		}
+		card := lowerKeys(fields)
+		reading := Reading{
+			Index:         index,
+			PCIBus:        NormalizeBus(lookup(card, "pci bus")),
+			Name:          lookup(card, "card series", "card model"),
+			Temperature:   lookupNumber(card, "temperature (sensor edge)", "temperature (sensor junction)", "temperature"),
+			Power:         lookupNumber(card, "average graphics package power", "current socket graphics package power"),
+			PowerLimit:    lookupNumber(card, "max graphics package power"),
+			Utilization:   lookupNumber(card, "gpu use"),
+			GraphicsClock: int(lookupNumber(card, "sclk clock speed")),
+			MemoryClock:   int(lookupNumber(card, "mclk clock speed")),
+			MemoryTotal:   int(lookupNumber(card, "vram total memory") / (1 << 20)),
+			MemoryUsed:    int(lookupNumber(card, "vram total used memory") / (1 << 20)),
+		}
+		snapshot.GPUs = append(snapshot.GPUs, reading)
+	}
+	if len(snapshot.GPUs) == 0 {
+		return nil, fmt.Errorf("rocm-smi reported no GPUs")
+	}
+
+	sort.Slice(snapshot.GPUs, func(i, j int) bool { return snapshot.GPUs[i].Index < snapshot.GPUs[j].Index })
+	return snapshot, nil
+}
+
+// lowerKeys 将键转为小写，值转为字符串；较新的 rocm-smi 会把部分数值输出为数字
+func lowerKeys(fields map[string]any) map[string]string {
+	out := make(map[string]string, len(fields))
+	for key, value := range fields {
+		s := fmt.Sprint(value)
+		if f, ok := value.(float64); ok {
+			// 避免显存字节数等大数被格式化为科学计数法
+			s = strconv.FormatFloat(f, 'f', -1, 64)
+		}
+		out[strings.ToLower(key)] = strings.TrimSpace(s)
+	}
+	return out
+}
+
+// lookup 按顺序返回第一个包含关键字的键的值，同一关键字匹配多个键时取键名最小的一个
+func lookup(fields map[string]string, keywords ...string) string {
+	for _, keyword := range keywords {
+		match := ""
+		for key := range fields {
+			if strings.Contains(key, keyword) && (match == "" || key < match) {
+				match = key
+			}
+		}
+		if match != "" {
+			return fields[match]
+		}
+	}
+	return ""
+}
+
+// lookupNumber 按顺序返回第一个包含关键字且值不为 0 的数值，如 edge 温度为 "N/A" 时取 junction 温度
+func lookupNumber(fields map[string]string, keywords ...string) float64 {
+	for _, keyword := range keywords {
+		if v := number(lookup(fields, keyword)); v != 0 {
+			return v
+		}
+	}
+	return 0
+}
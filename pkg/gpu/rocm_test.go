@@ -0,0 +1,72 @@
+package gpu
+
+import (
+	"os"
+	"testing"
+)
+
+func TestParseROCmSMI(t *testing.T) {
+	tests := []struct {
+		file   string
+		driver string
+		gpus   []Reading
+	}{
+		{
+			file:   "testdata/rocm-smi.json",
+			driver: "6.2.4",
+			gpus: []Reading{
+				{
+					Index: 0, PCIBus: "0000:29:00.0", Name: "AMD INSTINCT MI250 (MCM) OAM AC MBA",
+					Temperature: 38, Power: 92, PowerLimit: 560, Utilization: 3,
+					GraphicsClock: 800, MemoryClock: 1600, MemoryTotal: 65520, MemoryUsed: 10,
+				},
+				{
+					// edge 温度为 N/A 时取 junction 温度，功耗为 N/A 时为 0
+					Index: 1, PCIBus: "0000:2c:00.0", Name: "AMD INSTINCT MI250 (MCM) OAM AC MBA",
+					Temperature: 52, Power: 0, PowerLimit: 0, Utilization: 96,
+					GraphicsClock: 1700, MemoryClock: 1600, MemoryTotal: 65520, MemoryUsed: 50176,
+				},
+			},
+		},
+		{
+			// ROCm 6 的键名大写不同，MI300 报告 socket 功耗，部分数值为 JSON 数字
+			file:   "testdata/rocm-smi-6.json",
+			driver: "6.7.0",
+			gpus: []Reading{
+				{
+					Index: 0, PCIBus: "0000:0c:00.0", Name: "AMD Instinct MI300X OAM",
+					Temperature: 47, Power: 152, PowerLimit: 750, Utilization: 0,
+					GraphicsClock: 132, MemoryClock: 900, MemoryTotal: 196592, MemoryUsed: 284,
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			data, err := os.ReadFile(tt.file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			snapshot, err := ParseROCmSMI(data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if snapshot.Driver != tt.driver || snapshot.CUDA != "" {
+				t.Errorf("driver %q CUDA %q, want %q and no CUDA", snapshot.Driver, snapshot.CUDA, tt.driver)
+			}
+			checkReadings(t, snapshot.GPUs, tt.gpus)
+		})
+	}
+}
+
+func TestParseROCmSMIErrors(t *testing.T) {
+	for name, data := range map[string]string{
+		"not json": "ERROR: GPU[0] : Unable to read temperature",
+		"no cards": `{"system": {"Driver version": "6.2.4"}}`,
+	} {
+		if _, err := ParseROCmSMI([]byte(data)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package gpu
+
+import (
+	"context"
+	"octane/pkg/types"
+	"sync"
+	"time"
+)
+
+// DefaultInterval 是未指定间隔时的采样间隔，nvidia-smi -q 单次查询可能需要数百毫秒
+const DefaultInterval = 2 * time.Second
+
+// Sampler 在测试期间周期运行遥测工具，记录温度、功耗和利用率
+type Sampler struct {
+	source Source
+	reason string
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	mu          sync.Mutex
+	samples     int
+	temperature float64 // 温度之和，用于计算平均值
+	power       float64
+	utilization float64
+	sample      types.GPUSample
+}
+
+// StartSampler 立即查询一次作为空闲读数，然后每隔 interval 采样，直到调用 Stop。
+// 首次查询失败时不再采样，Stop 返回不可用状态和原因。
+func StartSampler(ctx context.Context, source Source, interval time.Duration) *Sampler {
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	s := &Sampler{source: source, cancel: cancel, done: make(chan struct{})}
+	s.sample.Tool = source.Name()
+
+	snapshot, err := source.Query(ctx)
+	if err != nil {
+		s.reason = err.Error()
+		close(s.done)
+		return s
+	}
+	s.observe(snapshot)
+	s.sample.Temperature.Idle = s.sample.Temperature.Max
+	s.sample.Power.Idle = s.sample.Power.Peak
+
+	go s.loop(ctx, interval)
+	return s
+}
+
+func (s *Sampler) loop(ctx context.Context, interval time.Duration) {
+	defer close(s.done)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			// 单次查询失败（如工具超时）时跳过该次采样
+			if snapshot, err := s.source.Query(ctx); err == nil {
+				s.observe(snapshot)
+			}
+		}
+	}
+}
+
+// observe 记录一次查询的读数
+func (s *Sampler) observe(snapshot *Snapshot) {
+	var temperature, power, utilization float64
+	for _, reading := range snapshot.GPUs {
+		temperature = max(temperature, reading.Temperature)
+		power += reading.Power
+		utilization += reading.Utilization
+	}
+	if len(snapshot.GPUs) > 0 {
+		utilization /= float64(len(snapshot.GPUs))
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.samples++
+	s.temperature += temperature
+	s.power += power
+	s.utilization += utilization
+	s.sample.Temperature.Max = max(s.sample.Temperature.Max, temperature)
+	s.sample.Power.Peak = max(s.sample.Power.Peak, power)
+}
+
+// Stop 结束采样，返回整个阶段的汇总
+func (s *Sampler) Stop() types.GPUSample {
+	s.cancel()
+	<-s.done
+	if s.reason != "" {
+		return types.GPUSample{Tool: s.sample.Tool, Reason: s.reason}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sample := s.sample
+	sample.Available = true
+	sample.Samples = s.samples
+	n := float64(s.samples)
+	sample.Temperature.Average = s.temperature / n
+	sample.Power.Average = s.power / n
+	sample.Utilization = s.utilization / n
+	return sample
+}
+
+// FillResults 用 GPU 阶段的遥测补全测试脚本未给出的温度和功耗
+func FillResults(results *types.GPUResults, sample types.GPUSample) {
+	if !sample.Available {
+		return
+	}
+	if results.Temperature.Max == 0 {
+		results.Temperature.Idle = sample.Temperature.Idle
+		results.Temperature.Load = sample.Temperature.Average
+		results.Temperature.Max = sample.Temperature.Max
+	}
+	if results.PowerConsumption.Peak == 0 {
+		results.PowerConsumption.Idle = sample.Power.Idle
+		results.PowerConsumption.Average = sample.Power.Average
+		results.PowerConsumption.Peak = sample.Power.Peak
+	}
+}
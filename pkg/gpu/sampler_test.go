@@ -0,0 +1,104 @@
+package gpu
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeSource 依次返回预设的读数，用完后返回错误并关闭 done
+type fakeSource struct {
+	mu        sync.Mutex
+	snapshots []*Snapshot
+	err       error
+	done      chan struct{}
+}
+
+func (f *fakeSource) Name() string { return "fake-smi" }
+
+func (f *fakeSource) Query(ctx context.Context) (*Snapshot, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.err != nil {
+		return nil, f.err
+	}
+	if len(f.snapshots) == 0 {
+		select {
+		case <-f.done:
+		default:
+			close(f.done)
+		}
+		return nil, errors.New("no more readings")
+	}
+	snapshot := f.snapshots[0]
+	f.snapshots = f.snapshots[1:]
+	return snapshot, nil
+}
+
+// readings 构造两块显卡的读数
+func readings(temperatures, powers, utilizations [2]float64) *Snapshot {
+	snapshot := &Snapshot{}
+	for i := range 2 {
+		snapshot.GPUs = append(snapshot.GPUs, Reading{
+			Index:       i,
+			Temperature: temperatures[i],
+			Power:       powers[i],
+			Utilization: utilizations[i],
+		})
+	}
+	return snapshot
+}
+
+func TestSampler(t *testing.T) {
+	source := &fakeSource{
+		snapshots: []*Snapshot{
+			readings([2]float64{30, 35}, [2]float64{50, 60}, [2]float64{0, 0}),      // 空闲
+			readings([2]float64{70, 80}, [2]float64{250, 300}, [2]float64{100, 80}), // 负载峰值
+			readings([2]float64{60, 65}, [2]float64{200, 200}, [2]float64{50, 50}),
+		},
+		done: make(chan struct{}),
+	}
+
+	sampler := StartSampler(context.Background(), source, time.Millisecond)
+	select {
+	case <-source.done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("sampler did not consume all readings")
+	}
+	sample := sampler.Stop()
+
+	if !sample.Available || sample.Tool != "fake-smi" || sample.Samples != 3 {
+		t.Fatalf("unexpected sample %+v", sample)
+	}
+	checks := []struct {
+		name      string
+		got, want float64
+	}{
+		{"temperature idle", sample.Temperature.Idle, 35},
+		{"temperature average", sample.Temperature.Average, 60},
+		{"temperature max", sample.Temperature.Max, 80},
+		{"power idle", sample.Power.Idle, 110},
+		{"power average", sample.Power.Average, (110 + 550 + 400) / 3.0},
+		{"power peak", sample.Power.Peak, 550},
+		{"utilization", sample.Utilization, (0 + 90 + 50) / 3.0},
+	}
+	for _, c := range checks {
+		if math.Abs(c.got-c.want) > 1e-9 {
+			t.Errorf("%s = %v, want %v", c.name, c.got, c.want)
+		}
+	}
+}
+
+func TestSamplerFirstQueryFails(t *testing.T) {
+	source := &fakeSource{err: errors.New("nvidia-smi: exit status 9"), done: make(chan struct{})}
+	sample := StartSampler(context.Background(), source, time.Millisecond).Stop()
+	if sample.Available || sample.Samples != 0 {
+		t.Errorf("expected an unavailable sample, got %+v", sample)
+	}
+	if sample.Tool != "fake-smi" || sample.Reason != "nvidia-smi: exit status 9" {
+		t.Errorf("tool %q reason %q", sample.Tool, sample.Reason)
+	}
+}
@@ -0,0 +1,137 @@
+package inventory
+
+import (
+	"fmt"
+	"octane/pkg/types"
+	"os"
+	"sort"
+	"strings"
+)
+
+// DMI 从 SMBIOS 表读取产品、主板、BIOS 和内存条信息并填入 info。
+// 表不可读（通常是非 root）或不完整时，用 /sys/class/dmi/id 中可读的字段补全仍为空的字段，并返回原始错误。
+func (c *Collector) DMI(info *types.SystemInfo) error {
+	data, err := os.ReadFile(c.path("sys/firmware/dmi/tables/DMI"))
+	if err == nil {
+		var structures []Structure
+		structures, err = ParseSMBIOS(data)
+		// 表尾损坏时仍使用已解析的结构
+		applySMBIOS(info, structures)
+	}
+	if err != nil {
+		c.dmiFallback(info)
+		return fmt.Errorf("reading SMBIOS table: %v", err)
+	}
+	return nil
+}
+
+// applySMBIOS 将解析出的结构填入系统信息
+func applySMBIOS(info *types.SystemInfo, structures []Structure) {
+	var devices []memoryDevice
+	for i := range structures {
+		s := &structures[i]
+		switch s.Type {
+		case smbiosBIOS:
+			info.BIOS = types.BIOSInfo{
+				Vendor:      s.str(0x04),
+				Version:     s.str(0x05),
+				ReleaseDate: s.str(0x08),
+			}
+		case smbiosSystem:
+			info.Product = types.ProductInfo{
+				Manufacturer: s.str(0x04),
+				Name:         s.str(0x05),
+				Version:      s.str(0x06),
+				SerialNumber: s.str(0x07),
+			}
+			if len(s.Formatted) >= 0x18 {
+				info.Product.UUID = smbiosUUID(s.Formatted[0x08:0x18])
+			}
+		case smbiosBaseboard:
+			// 多主板系统只记录第一块
+			if info.Board.Product == "" {
+				info.Board = types.BoardInfo{
+					Manufacturer: s.str(0x04),
+					Product:      s.str(0x05),
+					Version:      s.str(0x06),
+					SerialNumber: s.str(0x07),
+				}
+			}
+		case smbiosMemoryDevice:
+			devices = append(devices, parseMemoryDevice(s))
+		}
+	}
+
+	if len(devices) > 0 {
+		applyMemoryDevices(&info.Memory, devices)
+	}
+}
+
+// applyMemoryDevices 汇总内存插槽、类型、频率和已安装的内存条
+func applyMemoryDevices(memory *types.MemoryInfo, devices []memoryDevice) {
+	memory.Slots.Total = len(devices)
+	memory.Slots.Used = 0
+	memory.Modules = nil
+
+	for _, device := range devices {
+		if device.Size == 0 {
+			continue
+		}
+		memory.Slots.Used++
+
+		speed := device.Configured
+		if speed == 0 {
+			speed = device.Speed
+		}
+		memory.Modules = append(memory.Modules, types.MemoryModule{
+			Slot:         slotName(device),
+			Size:         device.Size,
+			Type:         device.Type,
+			Speed:        speed,
+			Manufacturer: device.Manufacturer,
+			PartNumber:   device.PartNumber,
+			SerialNumber: device.SerialNumber,
+		})
+
+		if memory.Type == "" {
+			memory.Type = device.Type
+		}
+		// 混插时以最低速率为准，这也是内存控制器实际运行的速率
+		if speed > 0 && (memory.Frequency == 0 || speed < memory.Frequency) {
+			memory.Frequency = speed
+		}
+	}
+
+	sort.SliceStable(memory.Modules, func(i, j int) bool { return memory.Modules[i].Slot < memory.Modules[j].Slot })
+}
+
+// slotName 组合 bank 和 device locator，例如 "P0 CHANNEL A / DIMM 0"
+func slotName(device memoryDevice) string {
+	if device.Bank == "" || strings.Contains(device.Locator, device.Bank) {
+		return device.Locator
+	}
+	return device.Bank + " / " + device.Locator
+}
+
+// dmiFallback 从 /sys/class/dmi/id 读取无需 root 的字段，只填写仍为空的字段，
+// 不覆盖已从 SMBIOS 表解析出的值
+func (c *Collector) dmiFallback(info *types.SystemInfo) {
+	fill := func(field *string, name string) {
+		if *field == "" {
+			*field = c.readString("sys/class/dmi/id", name)
+		}
+	}
+
+	fill(&info.BIOS.Vendor, "bios_vendor")
+	fill(&info.BIOS.Version, "bios_version")
+	fill(&info.BIOS.ReleaseDate, "bios_date")
+	fill(&info.Product.Manufacturer, "sys_vendor")
+	fill(&info.Product.Name, "product_name")
+	fill(&info.Product.Version, "product_version")
+	fill(&info.Product.SerialNumber, "product_serial")
+	fill(&info.Product.UUID, "product_uuid")
+	fill(&info.Board.Manufacturer, "board_vendor")
+	fill(&info.Board.Product, "board_name")
+	fill(&info.Board.Version, "board_version")
+	fill(&info.Board.SerialNumber, "board_serial")
+}
@@ -0,0 +1,178 @@
+package inventory
+
+import (
+	"octane/pkg/types"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// sysfsDMI 是 /sys/class/dmi/id 中的字段，与 SMBIOS 表中的值不同，用于区分数据来源
+var sysfsDMI = map[string]string{
+	"bios_vendor":     "sysfs vendor",
+	"bios_version":    "sysfs version",
+	"bios_date":       "01/01/2020",
+	"sys_vendor":      "sysfs manufacturer",
+	"product_name":    "sysfs product",
+	"board_vendor":    "sysfs board vendor",
+	"board_name":      "sysfs board",
+	"board_version":   "1.02",
+	"product_version": "sysfs product version",
+}
+
+// dmiRoot 在临时目录中构造 sysfs，table 为 testdata/dmi 中的表文件，为空时表不存在
+func dmiRoot(t *testing.T, table string) string {
+	t.Helper()
+	root := t.TempDir()
+	id := filepath.Join(root, "sys/class/dmi/id")
+	if err := os.MkdirAll(id, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for name, value := range sysfsDMI {
+		if err := os.WriteFile(filepath.Join(id, name), []byte(value+"\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if table == "" {
+		return root
+	}
+
+	data, err := os.ReadFile(filepath.Join("testdata/dmi", table))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tables := filepath.Join(root, "sys/firmware/dmi/tables")
+	if err := os.MkdirAll(tables, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(tables, "DMI"), data, 0400); err != nil {
+		t.Fatal(err)
+	}
+	return root
+}
+
+var (
+	smbiosBIOSInfo = types.BIOSInfo{
+		Vendor:      "American Megatrends International, LLC.",
+		Version:     "2.1.V1",
+		ReleaseDate: "03/08/2023",
+	}
+	smbiosProductInfo = types.ProductInfo{
+		Manufacturer: "Supermicro",
+		Name:         "SYS-620P-TR",
+		Version:      "0123456789",
+		SerialNumber: "S448390X3107321",
+		UUID:         "44454c4c-4a00-1035-804b-b4c04f4d3933",
+	}
+	// 表中未设置主板版本
+	smbiosBoardInfo = types.BoardInfo{
+		Manufacturer: "Supermicro",
+		Product:      "X12DPi-NT6",
+		SerialNumber: "OM22BS032451",
+	}
+	dimmA1 = types.MemoryModule{
+		Slot: "P0_Node0_Channel0_Dimm0 / P1-DIMMA1", Size: 65536, Type: "DDR4", Speed: 3200,
+		Manufacturer: "Samsung", PartNumber: "M393AAG40M32-CAE", SerialNumber: "H0LK000812F5",
+	}
+	dimmB1 = types.MemoryModule{
+		Slot: "P0_Node0_Channel1_Dimm0 / P1-DIMMB1", Size: 16384, Type: "DDR4", Speed: 2933,
+		Manufacturer: "Micron", PartNumber: "36ASF2G72PZ-3G2E1", SerialNumber: "2E9F1A77",
+	}
+)
+
+func TestDMI(t *testing.T) {
+	tests := []struct {
+		name    string
+		table   string
+		wantErr bool
+		bios    types.BIOSInfo
+		product types.ProductInfo
+		board   types.BoardInfo
+		slots   types.MemorySlots
+		modules []types.MemoryModule
+	}{
+		{
+			name:    "complete table",
+			table:   "DMI",
+			bios:    smbiosBIOSInfo,
+			product: smbiosProductInfo,
+			board:   smbiosBoardInfo,
+			slots:   types.MemorySlots{Used: 2, Total: 3},
+			modules: []types.MemoryModule{dimmA1, dimmB1},
+		},
+		{
+			// 最后一个内存设备被截断，已解析的结构不能被 sysfs 中的值覆盖，只补全空字段
+			name:    "truncated table",
+			table:   "DMI-truncated",
+			wantErr: true,
+			bios:    smbiosBIOSInfo,
+			product: smbiosProductInfo,
+			board: types.BoardInfo{
+				Manufacturer: "Supermicro",
+				Product:      "X12DPi-NT6",
+				Version:      "1.02",
+				SerialNumber: "OM22BS032451",
+			},
+			slots:   types.MemorySlots{Used: 2, Total: 2},
+			modules: []types.MemoryModule{dimmA1, dimmB1},
+		},
+		{
+			name:    "unreadable table",
+			wantErr: true,
+			bios:    types.BIOSInfo{Vendor: "sysfs vendor", Version: "sysfs version", ReleaseDate: "01/01/2020"},
+			product: types.ProductInfo{Manufacturer: "sysfs manufacturer", Name: "sysfs product", Version: "sysfs product version"},
+			board:   types.BoardInfo{Manufacturer: "sysfs board vendor", Product: "sysfs board", Version: "1.02"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info := &types.SystemInfo{}
+			err := NewCollector(dmiRoot(t, tt.table)).DMI(info)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("DMI() error = %v, want error %v", err, tt.wantErr)
+			}
+			if info.BIOS != tt.bios {
+				t.Errorf("BIOS = %+v, want %+v", info.BIOS, tt.bios)
+			}
+			if info.Product != tt.product {
+				t.Errorf("Product = %+v, want %+v", info.Product, tt.product)
+			}
+			if info.Board != tt.board {
+				t.Errorf("Board = %+v, want %+v", info.Board, tt.board)
+			}
+			if info.Memory.Slots != tt.slots {
+				t.Errorf("Slots = %+v, want %+v", info.Memory.Slots, tt.slots)
+			}
+			if len(info.Memory.Modules) != len(tt.modules) {
+				t.Fatalf("Modules = %+v, want %+v", info.Memory.Modules, tt.modules)
+			}
+			for i := range tt.modules {
+				if info.Memory.Modules[i] != tt.modules[i] {
+					t.Errorf("module %d = %+v, want %+v", i, info.Memory.Modules[i], tt.modules[i])
+				}
+			}
+			if tt.modules != nil && (info.Memory.Type != "DDR4" || info.Memory.Frequency != 2933) {
+				t.Errorf("memory %s at %d MT/s, want DDR4 at the slowest module's 2933", info.Memory.Type, info.Memory.Frequency)
+			}
+		})
+	}
+}
+
+func TestParseSMBIOSTruncated(t *testing.T) {
+	data, err := os.ReadFile("testdata/dmi/DMI-truncated")
+	if err != nil {
+		t.Fatal(err)
+	}
+	structures, err := ParseSMBIOS(data)
+	if err == nil {
+		t.Fatal("expected an error for the truncated table")
+	}
+	var types []uint8
+	for _, s := range structures {
+		types = append(types, s.Type)
+	}
+	if len(types) != 5 || types[0] != smbiosBIOS || types[4] != smbiosMemoryDevice {
+		t.Errorf("parsed structure types %v, want the 5 complete structures", types)
+	}
+}
@@ -0,0 +1,211 @@
+package inventory
+
+import (
+	"bufio"
+	_ "embed"
+	"fmt"
+	"octane/pkg/types"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// pciIDs 是 pci.ids 格式的显卡子集
+//
+//go:embed pci.ids
+var pciIDs string
+
+// PCI 显示控制器的类代码前缀（0x03xxxx）
+const displayClass = "0x03"
+
+// nvidiaArchitectures 按 NVIDIA 芯片代号的前缀给出架构
+var nvidiaArchitectures = []struct {
+	prefix string
+	name   string
+}{
+	{"GB", "Blackwell"},
+	{"GH", "Hopper"},
+	{"AD", "Ada Lovelace"},
+	{"GA", "Ampere"},
+	{"TU", "Turing"},
+	{"GV", "Volta"},
+	{"GP", "Pascal"},
+}
+
+// vendorBrands 是型号名称前加的厂商简称，PCI ID 数据库中方括号内的型号不含厂商
+var vendorBrands = map[string]string{
+	"10de": "NVIDIA",
+	"1002": "AMD",
+	"8086": "Intel",
+}
+
+// pciNames 是解析后的厂商和设备名称
+type pciNames struct {
+	vendors map[string]string
+	devices map[string]string // 键为 "vendor:device"
+}
+
+var (
+	pciOnce  sync.Once
+	pciTable pciNames
+)
+
+// lookupPCI 返回厂商和设备名称，未收录时为空
+func lookupPCI(vendor, device string) (string, string) {
+	pciOnce.Do(func() { pciTable = parsePCIIDs(pciIDs) })
+	return pciTable.vendors[vendor], pciTable.devices[vendor+":"+device]
+}
+
+// parsePCIIDs 解析 pci.ids 格式中的厂商行和设备行，忽略子系统和类代码
+func parsePCIIDs(data string) pciNames {
+	names := pciNames{vendors: make(map[string]string), devices: make(map[string]string)}
+	vendor := ""
+	scanner := bufio.NewScanner(strings.NewReader(data))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		switch {
+		case strings.HasPrefix(line, "\t\t"):
+			// 子系统
+		case strings.HasPrefix(line, "\t"):
+			if id, name, ok := strings.Cut(strings.TrimPrefix(line, "\t"), "  "); ok && vendor != "" {
+				names.devices[vendor+":"+strings.ToLower(id)] = name
+			}
+		case strings.HasPrefix(line, "C "):
+			// 类代码段位于文件末尾
+			return names
+		default:
+			if id, name, ok := strings.Cut(line, "  "); ok {
+				vendor = strings.ToLower(id)
+				names.vendors[vendor] = name
+			}
+		}
+	}
+	return names
+}
+
+// GPU 从 /sys/bus/pci/devices 读取显示控制器，按 PCI 地址排序。
+// 显存只有 amdgpu 等驱动通过 sysfs 提供，NVIDIA 显卡需要 nvidia-smi。
+func (c *Collector) GPU() []types.GPUInfo {
+	entries, err := os.ReadDir(c.path("sys/bus/pci/devices"))
+	if err != nil {
+		return nil
+	}
+
+	cards := c.drmCards()
+	var gpus []types.GPUInfo
+	for _, entry := range entries {
+		address := entry.Name()
+		base := "sys/bus/pci/devices/" + address
+		if !strings.HasPrefix(c.readString(base, "class"), displayClass) {
+			continue
+		}
+
+		vendorID := strings.TrimPrefix(c.readString(base, "vendor"), "0x")
+		deviceID := strings.TrimPrefix(c.readString(base, "device"), "0x")
+		vendor, device := lookupPCI(vendorID, deviceID)
+
+		gpu := types.GPUInfo{
+			Index:    -1,
+			PCIBus:   address,
+			Vendor:   vendor,
+			VendorID: vendorID,
+			DeviceID: deviceID,
+			PCIe: types.PCIeLink{
+				Speed:    linkSpeed(c.readString(base, "current_link_speed")),
+				Width:    int(c.readInt(base, "current_link_width")),
+				MaxSpeed: linkSpeed(c.readString(base, "max_link_speed")),
+				MaxWidth: int(c.readInt(base, "max_link_width")),
+			},
+		}
+		gpu.Name, gpu.Architecture = gpuName(vendorID, vendor, deviceID, device)
+		if card, ok := cards[address]; ok {
+			gpu.Index = card
+		}
+		if driver := c.readLink(base, "driver"); driver != "" {
+			gpu.Driver.Name = driver
+			gpu.Driver.Version = c.readString("sys/module", driver, "version")
+		}
+		if vram := c.readInt(base, "mem_info_vram_total"); vram > 0 {
+			gpu.Memory.Total = int(vram >> 20)
+		}
+		gpus = append(gpus, gpu)
+	}
+
+	sort.Slice(gpus, func(i, j int) bool { return gpus[i].PCIBus < gpus[j].PCIBus })
+	// 没有 DRM 节点的显卡（如未加载驱动）排在已编号的显卡之后
+	next := 0
+	for _, gpu := range gpus {
+		next = max(next, gpu.Index+1)
+	}
+	for i := range gpus {
+		if gpus[i].Index < 0 {
+			gpus[i].Index = next
+			next++
+		}
+	}
+	return gpus
+}
+
+// drmCards 将 /sys/class/drm/cardN 对应的 PCI 地址映射到 N
+func (c *Collector) drmCards() map[string]int {
+	cards := make(map[string]int)
+	matches, _ := filepath.Glob(c.path("sys/class/drm/card*"))
+	for _, match := range matches {
+		n, err := strconv.Atoi(strings.TrimPrefix(filepath.Base(match), "card"))
+		if err != nil {
+			continue // card0-DP-1 等连接器节点
+		}
+		target, err := os.Readlink(filepath.Join(match, "device"))
+		if err != nil {
+			continue
+		}
+		cards[filepath.Base(target)] = n
+	}
+	return cards
+}
+
+// gpuName 根据 PCI ID 数据库中的名称给出型号和架构。
+// 名称形如 "GA102 [GeForce RTX 3090]" 时取方括号中的型号，芯片代号用于判断 NVIDIA 架构。
+func gpuName(vendorID, vendor, deviceID, device string) (string, string) {
+	if device == "" {
+		if vendor == "" {
+			vendor = "PCI vendor " + vendorID
+		}
+		return fmt.Sprintf("%s device %s", vendor, deviceID), ""
+	}
+
+	chip, model := device, device
+	if open := strings.Index(device, " ["); open > 0 && strings.HasSuffix(device, "]") {
+		chip, model = device[:open], device[open+2:len(device)-1]
+		if brand, ok := vendorBrands[vendorID]; ok {
+			model = brand + " " + model
+		}
+	}
+	if vendorID == "10de" {
+		for _, arch := range nvidiaArchitectures {
+			if strings.HasPrefix(chip, arch.prefix) {
+				return model, arch.name
+			}
+		}
+	}
+	return model, ""
+}
+
+// linkSpeed 将 "16.0 GT/s PCIe" 等转换为 GT/s，无法解析时返回 0
+func linkSpeed(s string) float64 {
+	fields := strings.Fields(s)
+	if len(fields) == 0 {
+		return 0
+	}
+	speed, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return 0
+	}
+	return speed
+}
@@ -0,0 +1,78 @@
+package inventory
+
+import (
+	"octane/pkg/octane"
+	"strings"
+	"testing"
+)
+
+func TestGPU(t *testing.T) {
+	gpus := NewCollector("testdata/gpu").GPU()
+	if len(gpus) != 3 {
+		t.Fatalf("got %d GPUs, want 3 (the network controller must be skipped): %+v", len(gpus), gpus)
+	}
+
+	tests := []struct {
+		bus          string
+		index        int
+		name         string
+		vendor       string
+		architecture string
+		driver       string
+		version      string
+		memory       int
+		width        int
+		maxWidth     int
+	}{
+		{"0000:01:00.0", 1, "NVIDIA GeForce RTX 3090", "NVIDIA Corporation", "Ampere", "nvidia", "535.129.03", 0, 4, 16},
+		{"0000:03:00.0", 0, "AMD Radeon RX 7900 XT/7900 XTX/7900M", "Advanced Micro Devices, Inc. [AMD/ATI]", "", "amdgpu", "", 24560, 16, 16},
+		// 没有 DRM 节点，编号排在已有节点的显卡之后；型号不在 pci.ids 中
+		{"0000:05:00.0", 2, "ASPEED Technology, Inc. device 9999", "ASPEED Technology, Inc.", "", "", "", 0, 0, 0},
+	}
+	for i, tt := range tests {
+		gpu := gpus[i]
+		if gpu.PCIBus != tt.bus {
+			t.Fatalf("GPU %d: bus %q, want %q", i, gpu.PCIBus, tt.bus)
+		}
+		if gpu.Index != tt.index {
+			t.Errorf("%s: index %d, want %d", tt.bus, gpu.Index, tt.index)
+		}
+		if gpu.Name != tt.name || gpu.Vendor != tt.vendor || gpu.Architecture != tt.architecture {
+			t.Errorf("%s: got %q / %q / %q, want %q / %q / %q", tt.bus,
+				gpu.Name, gpu.Vendor, gpu.Architecture, tt.name, tt.vendor, tt.architecture)
+		}
+		if gpu.Driver.Name != tt.driver || gpu.Driver.Version != tt.version {
+			t.Errorf("%s: driver %q %q, want %q %q", tt.bus, gpu.Driver.Name, gpu.Driver.Version, tt.driver, tt.version)
+		}
+		if gpu.Memory.Total != tt.memory {
+			t.Errorf("%s: memory %d MB, want %d", tt.bus, gpu.Memory.Total, tt.memory)
+		}
+		if gpu.PCIe.Width != tt.width || gpu.PCIe.MaxWidth != tt.maxWidth {
+			t.Errorf("%s: PCIe x%d (max x%d), want x%d (max x%d)", tt.bus, gpu.PCIe.Width, gpu.PCIe.MaxWidth, tt.width, tt.maxWidth)
+		}
+	}
+	if gpus[0].PCIe.Speed != 16 || gpus[0].PCIe.MaxSpeed != 16 {
+		t.Errorf("link speed %.1f (max %.1f) GT/s, want 16.0", gpus[0].PCIe.Speed, gpus[0].PCIe.MaxSpeed)
+	}
+
+	recommendations := octane.GPULinkRecommendations(gpus)
+	if len(recommendations) != 1 {
+		t.Fatalf("got %d recommendations, want 1 for the x4 link: %+v", len(recommendations), recommendations)
+	}
+	if s := recommendations[0].Suggestion; !strings.Contains(s, "0000:01:00.0") || !strings.Contains(s, "x4 but supports x16") {
+		t.Errorf("unexpected recommendation %q", s)
+	}
+}
+
+func TestParsePCIIDs(t *testing.T) {
+	names := parsePCIIDs("# comment\n10de  NVIDIA Corporation\n\t2204  GA102 [GeForce RTX 3090]\n\t\t10de 1454  Subsystem\nC 03  Display controller\n\t00  VGA compatible controller\n")
+	if names.vendors["10de"] != "NVIDIA Corporation" {
+		t.Errorf("vendor %q", names.vendors["10de"])
+	}
+	if names.devices["10de:2204"] != "GA102 [GeForce RTX 3090]" {
+		t.Errorf("device %q", names.devices["10de:2204"])
+	}
+	if len(names.devices) != 1 {
+		t.Errorf("class section parsed as devices: %v", names.devices)
+	}
+}
@@ -0,0 +1,131 @@
+package inventory
+
+import (
+	"bufio"
+	"fmt"
+	"octane/pkg/cgroup"
+	"octane/pkg/types"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Host 采集操作系统、内核、主机名、运行时间和时区
+func (c *Collector) Host() types.HostInfo {
+	host := types.HostInfo{
+		OS:           c.osName(),
+		Kernel:       c.kernel(),
+		Architecture: c.readString("proc/sys/kernel/arch"),
+		Hostname:     c.readString("proc/sys/kernel/hostname"),
+		Uptime:       c.uptime(),
+		Timezone:     c.timezone(),
+	}
+
+	if host.Architecture == "" && c.live {
+		host.Architecture = runtime.GOARCH
+	}
+
+	host.Container = cgroup.Container(c.Root)
+	limits := cgroup.Detect(c.Root)
+	host.CgroupVersion = limits.Version
+	host.MemoryLimit = int(limits.Memory >> 20)
+	host.CPULimit = limits.CPUs
+	if limits.CPUSet > 0 && (host.CPULimit == 0 || float64(limits.CPUSet) < host.CPULimit) {
+		host.CPULimit = float64(limits.CPUSet)
+	}
+	if host.Hostname == "" {
+		host.Hostname = c.readString("etc/hostname")
+	}
+	return host
+}
+
+// osName 从 os-release 读取发行版名称
+func (c *Collector) osName() string {
+	for _, path := range []string{"etc/os-release", "usr/lib/os-release"} {
+		release := parseOSRelease(c.path(path))
+		if release == nil {
+			continue
+		}
+		if name := release["PRETTY_NAME"]; name != "" {
+			return name
+		}
+		return strings.TrimSpace(release["NAME"] + " " + release["VERSION"])
+	}
+	return ""
+}
+
+// parseOSRelease 解析 os-release 的 KEY=value 行，文件不存在时返回 nil
+func parseOSRelease(path string) map[string]string {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer file.Close()
+
+	release := make(map[string]string)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(strings.TrimSpace(scanner.Text()), "=")
+		if !ok || strings.HasPrefix(key, "#") {
+			continue
+		}
+		if unquoted, err := strconv.Unquote(value); err == nil {
+			value = unquoted
+		} else {
+			value = strings.Trim(value, `'"`)
+		}
+		release[key] = value
+	}
+	return release
+}
+
+// kernel 返回与 uname -sr 相同的内核描述
+func (c *Collector) kernel() string {
+	return strings.TrimSpace(c.readString("proc/sys/kernel/ostype") + " " + c.readString("proc/sys/kernel/osrelease"))
+}
+
+// uptime 读取 /proc/uptime 并格式化为 "3d 4h 12m"
+func (c *Collector) uptime() string {
+	fields := strings.Fields(c.readString("proc/uptime"))
+	if len(fields) == 0 {
+		return ""
+	}
+	seconds, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return ""
+	}
+	return formatUptime(time.Duration(seconds) * time.Second)
+}
+
+// formatUptime 将运行时间格式化为天、小时和分钟
+func formatUptime(d time.Duration) string {
+	days := int(d.Hours()) / 24
+	hours := int(d.Hours()) % 24
+	minutes := int(d.Minutes()) % 60
+	if days > 0 {
+		return fmt.Sprintf("%dd %dh %dm", days, hours, minutes)
+	}
+	return fmt.Sprintf("%dh %dm", hours, minutes)
+}
+
+// timezone 依次读取 /etc/timezone 和 /etc/localtime 链接，本机还可回退到 TZ
+func (c *Collector) timezone() string {
+	if zone := c.readString("etc/timezone"); zone != "" {
+		return zone
+	}
+	if target, err := os.Readlink(c.path("etc/localtime")); err == nil {
+		if _, zone, ok := strings.Cut(target, "zoneinfo/"); ok {
+			return zone
+		}
+	}
+	if !c.live {
+		return ""
+	}
+	if zone := os.Getenv("TZ"); zone != "" {
+		return strings.TrimPrefix(zone, ":")
+	}
+	name, _ := time.Now().Zone()
+	return name
+}
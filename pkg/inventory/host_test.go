@@ -0,0 +1,59 @@
+package inventory
+
+import (
+	"octane/pkg/types"
+	"testing"
+	"time"
+)
+
+func TestHost(t *testing.T) {
+	tests := []struct {
+		root string
+		want types.HostInfo
+	}{
+		// /etc/os-release 优先于 /usr/lib/os-release，proc 中的主机名优先于 /etc/hostname
+		{"testdata/system", types.HostInfo{
+			OS:           "Ubuntu 22.04.4 LTS",
+			Kernel:       "Linux 6.5.0-28-generic",
+			Architecture: "x86_64",
+			Hostname:     "bench-01",
+			Uptime:       "3d 5h 12m",
+			Timezone:     "Asia/Shanghai",
+		}},
+		// 没有 PRETTY_NAME 时拼接 NAME 和 VERSION，时区取自 /etc/localtime 链接
+		{"testdata/system-minimal", types.HostInfo{
+			OS:        "Alpine Linux 3.19.1",
+			Hostname:  "minimal",
+			Uptime:    "1h 30m",
+			Timezone:  "Europe/Berlin",
+			Container: "docker",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.root, func(t *testing.T) {
+			if host := NewCollector(tt.root).Host(); host != tt.want {
+				t.Errorf("Host = %+v\nwant %+v", host, tt.want)
+			}
+		})
+	}
+
+	// 样例目录树中没有的字段不从本机补充
+	if host := NewCollector(t.TempDir()).Host(); host != (types.HostInfo{}) {
+		t.Errorf("Host of an empty tree = %+v", host)
+	}
+}
+
+func TestFormatUptime(t *testing.T) {
+	tests := map[time.Duration]string{
+		0:                             "0h 0m",
+		59 * time.Second:              "0h 0m",
+		90 * time.Minute:              "1h 30m",
+		24 * time.Hour:                "1d 0h 0m",
+		400*time.Hour + 5*time.Minute: "16d 16h 5m",
+	}
+	for d, want := range tests {
+		if got := formatUptime(d); got != want {
+			t.Errorf("formatUptime(%v) = %q, want %q", d, got, want)
+		}
+	}
+}
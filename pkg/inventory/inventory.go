@@ -0,0 +1,86 @@
+package inventory
+
+import (
+	"context"
+	"octane/pkg/types"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Collector 从 /proc、/sys 和 /etc 读取系统清单。
+// Root 为文件系统根目录，测试时可指向一个样例目录树。
+type Collector struct {
+	Root string
+
+	// IncludeVirtual 为 true 时网络清单包含 veth、网桥、tun 等没有物理设备的接口
+	IncludeVirtual bool
+
+	// Named 中的接口总是出现在网络清单中（名称不区分大小写），即使它们是 VLAN 等虚拟接口
+	Named []string
+
+	// live 表示 Root 为真实根目录，此时才读取挂载点用量和接口地址等无法从文件获得的信息
+	live bool
+}
+
+// NewCollector 创建一个读取 root 下文件的采集器，root 为空或 "/" 时读取本机
+func NewCollector(root string) *Collector {
+	if root == "" {
+		root = "/"
+	}
+	return &Collector{Root: root, live: filepath.Clean(root) == "/"}
+}
+
+// Collect 采集主机、固件、虚拟化、内存、存储、显卡和网络信息，单项失败时保留已采集的字段
+func (c *Collector) Collect(ctx context.Context) *types.SystemInfo {
+	info := &types.SystemInfo{
+		Host:    c.Host(),
+		Memory:  c.Memory(),
+		Storage: c.Storage(ctx),
+		GPU:     c.GPU(),
+		Network: c.Network(),
+	}
+	// SMBIOS 表需要 root，不可读时已回退到 sysfs 中的公开字段
+	c.DMI(info)
+	c.Virtualization(info)
+	return info
+}
+
+// path 返回 Root 下的路径
+func (c *Collector) path(parts ...string) string {
+	return filepath.Join(append([]string{c.Root}, parts...)...)
+}
+
+// readString 读取文件并去掉首尾空白，失败时返回空字符串
+func (c *Collector) readString(parts ...string) string {
+	data, err := os.ReadFile(c.path(parts...))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// readInt 读取只包含一个整数的文件，失败时返回 0
+func (c *Collector) readInt(parts ...string) int64 {
+	value, err := strconv.ParseInt(c.readString(parts...), 10, 64)
+	if err != nil {
+		return 0
+	}
+	return value
+}
+
+// readLink 返回符号链接目标的最后一段，例如驱动名
+func (c *Collector) readLink(parts ...string) string {
+	target, err := os.Readlink(c.path(parts...))
+	if err != nil {
+		return ""
+	}
+	return filepath.Base(target)
+}
+
+// exists 判断 Root 下的路径是否存在
+func (c *Collector) exists(parts ...string) bool {
+	_, err := os.Lstat(c.path(parts...))
+	return err == nil
+}
@@ -0,0 +1,45 @@
+package inventory
+
+import (
+	"bufio"
+	"octane/pkg/types"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Memory 从 /proc/meminfo 读取总内存和可用内存
+func (c *Collector) Memory() types.MemoryInfo {
+	meminfo := c.meminfo()
+	return types.MemoryInfo{
+		Total:     int(meminfo["MemTotal"] / 1024),
+		Available: int(meminfo["MemAvailable"] / 1024),
+	}
+}
+
+// meminfo 解析 /proc/meminfo，数值单位为 kB
+func (c *Collector) meminfo() map[string]int64 {
+	values := make(map[string]int64)
+
+	file, err := os.Open(c.path("proc/meminfo"))
+	if err != nil {
+		return values
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		key, rest, ok := strings.Cut(scanner.Text(), ":")
+		if !ok {
+			continue
+		}
+		fields := strings.Fields(rest)
+		if len(fields) == 0 {
+			continue
+		}
+		if value, err := strconv.ParseInt(fields[0], 10, 64); err == nil {
+			values[key] = value
+		}
+	}
+	return values
+}
@@ -0,0 +1,23 @@
+package inventory
+
+import (
+	"octane/pkg/types"
+	"testing"
+)
+
+func TestMemory(t *testing.T) {
+	want := types.MemoryInfo{Total: 15935, Available: 11720}
+	if memory := NewCollector("testdata/system").Memory(); memory.Total != want.Total || memory.Available != want.Available {
+		t.Errorf("Memory = %+v, want total %d MB and available %d MB", memory, want.Total, want.Available)
+	}
+
+	// 没有单位的行（HugePages_Total）也能解析
+	meminfo := NewCollector("testdata/system").meminfo()
+	if meminfo["HugePages_Total"] != 0 || meminfo["Hugepagesize"] != 2048 || len(meminfo) != 8 {
+		t.Errorf("meminfo = %v", meminfo)
+	}
+
+	if memory := NewCollector(t.TempDir()).Memory(); memory.Total != 0 || memory.Available != 0 {
+		t.Errorf("Memory without /proc/meminfo = %+v", memory)
+	}
+}
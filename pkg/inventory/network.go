@@ -0,0 +1,109 @@
+package inventory
+
+import (
+	"net"
+	"octane/pkg/types"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Network 从 /sys/class/net 读取网络接口，跳过回环接口；
+// 除非设置了 IncludeVirtual 或在 Named 中列出，也跳过没有物理设备的虚拟接口和网桥（保留 bond）
+func (c *Collector) Network() []types.NetworkInfo {
+	entries, err := os.ReadDir(c.path("sys/class/net"))
+	if err != nil {
+		return nil
+	}
+
+	var interfaces []types.NetworkInfo
+	for _, entry := range entries {
+		name := entry.Name()
+		base := "sys/class/net/" + name
+		if c.readInt(base, "type") == 772 { // ARPHRD_LOOPBACK
+			continue
+		}
+
+		kind := c.interfaceType(base)
+		if !c.IncludeVirtual && (kind == "virtual" || kind == "bridge") && !c.named(name) {
+			continue
+		}
+
+		iface := types.NetworkInfo{
+			Name:   name,
+			Type:   kind,
+			MAC:    c.readString(base, "address"),
+			Driver: c.readLink(base, "device/driver"),
+			Duplex: c.readString(base, "duplex"),
+			Status: c.readString(base, "operstate"),
+			MTU:    int(c.readInt(base, "mtu")),
+		}
+		// 未连接或虚拟接口读取 speed 会失败或返回 -1
+		if speed := c.readInt(base, "speed"); speed > 0 {
+			iface.Speed = int(speed)
+		}
+		if c.live {
+			iface.IPv4, iface.IPv6 = interfaceAddrs(name)
+		}
+		interfaces = append(interfaces, iface)
+	}
+
+	sort.Slice(interfaces, func(i, j int) bool { return interfaces[i].Name < interfaces[j].Name })
+	return interfaces
+}
+
+// named 判断接口是否在 Named 中
+func (c *Collector) named(name string) bool {
+	for _, n := range c.Named {
+		if strings.EqualFold(n, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// interfaceType 根据 sysfs 属性判断接口类型
+func (c *Collector) interfaceType(base string) string {
+	switch {
+	case c.exists(base, "wireless") || c.exists(base, "phy80211"):
+		return "wireless"
+	case c.exists(base, "bridge"):
+		return "bridge"
+	case c.exists(base, "bonding"):
+		return "bond"
+	case !c.exists(base, "device"):
+		return "virtual"
+	case c.readInt(base, "type") == 32: // ARPHRD_INFINIBAND
+		return "infiniband"
+	default:
+		return "ethernet"
+	}
+}
+
+// interfaceAddrs 返回接口的第一个 IPv4 和 IPv6 地址
+func interfaceAddrs(name string) (string, string) {
+	iface, err := net.InterfaceByName(name)
+	if err != nil {
+		return "", ""
+	}
+	addrs, err := iface.Addrs()
+	if err != nil {
+		return "", ""
+	}
+
+	var ipv4, ipv6 string
+	for _, addr := range addrs {
+		ipnet, ok := addr.(*net.IPNet)
+		if !ok {
+			continue
+		}
+		if ipnet.IP.To4() != nil {
+			if ipv4 == "" {
+				ipv4 = ipnet.IP.String()
+			}
+		} else if ipv6 == "" {
+			ipv6 = ipnet.IP.String()
+		}
+	}
+	return ipv4, ipv6
+}
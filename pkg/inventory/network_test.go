@@ -0,0 +1,56 @@
+package inventory
+
+import (
+	"octane/pkg/types"
+	"strings"
+	"testing"
+)
+
+func TestNetwork(t *testing.T) {
+	eth0 := types.NetworkInfo{Name: "eth0", Type: "ethernet", MAC: "3c:ec:ef:12:34:56", Driver: "ixgbe", Speed: 10000, Duplex: "full", Status: "up", MTU: 9000}
+	// 未连接的接口 speed 为 -1，记为 0
+	eth1 := types.NetworkInfo{Name: "eth1", Type: "ethernet", MAC: "3c:ec:ef:12:34:57", Driver: "ixgbe", Status: "down", MTU: 1500}
+	bond0 := types.NetworkInfo{Name: "bond0", Type: "bond", Speed: 20000, Status: "up", MTU: 1500}
+	ib0 := types.NetworkInfo{Name: "ib0", Type: "infiniband", Speed: 100000, Status: "up", MTU: 2044}
+	wlan0 := types.NetworkInfo{Name: "wlan0", Type: "wireless", MAC: "a4:c3:f0:00:11:22", Status: "dormant", MTU: 1500}
+	br0 := types.NetworkInfo{Name: "br0", Type: "bridge", Status: "up", MTU: 1500}
+	veth := types.NetworkInfo{Name: "veth1a2b3c", Type: "virtual", Speed: 10000, Status: "up", MTU: 1500}
+
+	tests := []struct {
+		name           string
+		includeVirtual bool
+		want           []types.NetworkInfo
+	}{
+		// 回环接口总是跳过，bond 没有 device 节点也保留
+		{"physical", false, []types.NetworkInfo{bond0, eth0, eth1, ib0, wlan0}},
+		{"include virtual", true, []types.NetworkInfo{bond0, br0, eth0, eth1, ib0, veth, wlan0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			collector := NewCollector("testdata/system")
+			collector.IncludeVirtual = tt.includeVirtual
+			interfaces := collector.Network()
+			if len(interfaces) != len(tt.want) {
+				t.Fatalf("Network = %+v, want %d interfaces", interfaces, len(tt.want))
+			}
+			for i := range tt.want {
+				if interfaces[i] != tt.want[i] {
+					t.Errorf("interface %d = %+v\nwant %+v", i, interfaces[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestNetworkNamed(t *testing.T) {
+	// 配置中点名的虚拟接口即使未设置 IncludeVirtual 也出现在清单中，名称不区分大小写
+	collector := NewCollector("testdata/system")
+	collector.Named = []string{"VETH1A2B3C", "eth9"}
+	var names []string
+	for _, iface := range collector.Network() {
+		names = append(names, iface.Name)
+	}
+	if got := strings.Join(names, " "); got != "bond0 eth0 eth1 ib0 veth1a2b3c wlan0" {
+		t.Errorf("interfaces = %s", got)
+	}
+}
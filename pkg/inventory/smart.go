@@ -0,0 +1,200 @@
+package inventory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"octane/pkg/executor"
+	"octane/pkg/types"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// smartTimeout 是单个设备读取 SMART 数据的超时时间
+const smartTimeout = 15 * time.Second
+
+// 反映 SSD 剩余寿命的 ATA 属性，归一化值即剩余百分比
+var ataWearAttributes = []int{
+	177, // Wear_Leveling_Count（Samsung）
+	231, // SSD_Life_Left
+	233, // Media_Wearout_Indicator（Intel）
+}
+
+// SmartHealth 定义从 SMART 数据中提取的健康指标
+type SmartHealth struct {
+	Passed             bool
+	Health             int // %，100 - 已用寿命
+	PercentageUsed     int
+	MediaErrors        int64
+	ReallocatedSectors int64
+	PowerOnHours       int64
+	Temperature        int // °C
+}
+
+// smartctlOutput 是 smartctl --json 输出中用到的字段
+type smartctlOutput struct {
+	Smartctl struct {
+		ExitStatus int `json:"exit_status"`
+		Messages   []struct {
+			String   string `json:"string"`
+			Severity string `json:"severity"`
+		} `json:"messages"`
+	} `json:"smartctl"`
+	SmartStatus *struct {
+		Passed bool `json:"passed"`
+	} `json:"smart_status"`
+	Temperature struct {
+		Current int `json:"current"`
+	} `json:"temperature"`
+	PowerOnTime struct {
+		Hours int64 `json:"hours"`
+	} `json:"power_on_time"`
+	NVMeLog *struct {
+		PercentageUsed int   `json:"percentage_used"`
+		MediaErrors    int64 `json:"media_errors"`
+		Temperature    int   `json:"temperature"`
+		PowerOnHours   int64 `json:"power_on_hours"`
+	} `json:"nvme_smart_health_information_log"`
+	ATAAttributes *struct {
+		Table []struct {
+			ID    int `json:"id"`
+			Value int `json:"value"`
+			Raw   struct {
+				Value int64 `json:"value"`
+			} `json:"raw"`
+		} `json:"table"`
+	} `json:"ata_smart_attributes"`
+}
+
+// ParseSmartctl 解析 smartctl --json -a 的输出
+func ParseSmartctl(data []byte) (*SmartHealth, error) {
+	var out smartctlOutput
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, fmt.Errorf("malformed smartctl output: %v", err)
+	}
+	if out.SmartStatus == nil {
+		for _, msg := range out.Smartctl.Messages {
+			if msg.Severity == "error" {
+				return nil, fmt.Errorf("smartctl: %s", msg.String)
+			}
+		}
+		return nil, fmt.Errorf("smartctl: no SMART status (exit status %d)", out.Smartctl.ExitStatus)
+	}
+
+	health := &SmartHealth{
+		Passed:       out.SmartStatus.Passed,
+		PowerOnHours: out.PowerOnTime.Hours,
+		Temperature:  out.Temperature.Current,
+	}
+
+	if log := out.NVMeLog; log != nil {
+		health.PercentageUsed = log.PercentageUsed
+		health.MediaErrors = log.MediaErrors
+		if health.PowerOnHours == 0 {
+			health.PowerOnHours = log.PowerOnHours
+		}
+		if health.Temperature == 0 {
+			health.Temperature = log.Temperature
+		}
+	}
+
+	if attrs := out.ATAAttributes; attrs != nil {
+		wear := make(map[int]int)
+		for _, attr := range attrs.Table {
+			switch attr.ID {
+			case 5: // Reallocated_Sector_Ct
+				health.ReallocatedSectors = attr.Raw.Value
+			case 187: // Reported_Uncorrect
+				health.MediaErrors = attr.Raw.Value
+			}
+			wear[attr.ID] = attr.Value
+		}
+		for _, id := range ataWearAttributes {
+			if value, ok := wear[id]; ok && value <= 100 {
+				health.PercentageUsed = 100 - value
+				break
+			}
+		}
+	}
+
+	// percentage_used 可以超过 100，表示已超出额定寿命
+	health.Health = max(0, 100-health.PercentageUsed)
+	if !health.Passed {
+		health.Health = 0
+	}
+	return health, nil
+}
+
+// nvmeSmartLog 是 nvme smart-log -o json 输出中用到的字段
+type nvmeSmartLog struct {
+	CriticalWarning int   `json:"critical_warning"`
+	Temperature     int   `json:"temperature"` // K
+	PercentUsed     int   `json:"percent_used"`
+	MediaErrors     int64 `json:"media_errors"`
+	PowerOnHours    int64 `json:"power_on_hours"`
+}
+
+// ParseNVMeSmartLog 解析 nvme-cli 的 smart-log JSON 输出
+func ParseNVMeSmartLog(data []byte) (*SmartHealth, error) {
+	var log nvmeSmartLog
+	if err := json.Unmarshal(data, &log); err != nil {
+		return nil, fmt.Errorf("malformed nvme smart-log output: %v", err)
+	}
+
+	health := &SmartHealth{
+		Passed:         log.CriticalWarning == 0,
+		PercentageUsed: log.PercentUsed,
+		MediaErrors:    log.MediaErrors,
+		PowerOnHours:   log.PowerOnHours,
+		Health:         max(0, 100-log.PercentUsed),
+	}
+	if log.Temperature > 0 {
+		health.Temperature = log.Temperature - 273
+	}
+	if !health.Passed {
+		health.Health = 0
+	}
+	return health, nil
+}
+
+// smart 依次尝试 smartctl 和 nvme-cli 读取设备的 SMART 数据，超时从 ctx 派生，取消 ctx 时立即结束
+func smart(ctx context.Context, name string) (*SmartHealth, error) {
+	ctx, cancel := context.WithTimeout(ctx, smartTimeout)
+	defer cancel()
+
+	device := filepath.Join("/dev", name)
+	if path, err := exec.LookPath("smartctl"); err == nil {
+		// smartctl 的退出码是位掩码，磁盘告警时也非零，因此以 JSON 内容为准
+		stdout, _, _ := executor.RunCommand(ctx, executor.Command{Path: path, Args: []string{"--json", "-a", device}})
+		if health, err := ParseSmartctl(stdout); err == nil {
+			return health, nil
+		}
+	}
+
+	if path, err := exec.LookPath("nvme"); err == nil && strings.HasPrefix(name, "nvme") {
+		stdout, stderr, err := executor.RunCommand(ctx, executor.Command{Path: path, Args: []string{"smart-log", "-o", "json", device}})
+		if err != nil {
+			return nil, fmt.Errorf("nvme smart-log: %v: %s", err, stderr)
+		}
+		return ParseNVMeSmartLog(stdout)
+	}
+	return nil, fmt.Errorf("no SMART data for %s (install smartmontools and run as root)", name)
+}
+
+// applySmart 将 SMART 数据写入存储设备信息
+func applySmart(device *types.StorageInfo, health *SmartHealth) {
+	device.SmartStatus = "failed"
+	if health.Passed {
+		device.SmartStatus = "passed"
+	}
+	device.Health = health.Health
+	device.PercentageUsed = health.PercentageUsed
+	device.MediaErrors = health.MediaErrors
+	device.ReallocatedSectors = health.ReallocatedSectors
+	device.PowerOnHours = health.PowerOnHours
+	if health.Temperature > 0 {
+		device.Temperature = health.Temperature
+	}
+}
@@ -0,0 +1,136 @@
+package inventory
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseSmartctl(t *testing.T) {
+	tests := []struct {
+		file string
+		want SmartHealth
+	}{
+		{"smartctl-nvme.json", SmartHealth{
+			Passed: true, Health: 97, PercentageUsed: 3, PowerOnHours: 6021, Temperature: 41,
+		}},
+		// Wear_Leveling_Count 的归一化值 92 表示已用 8%
+		{"smartctl-sata-ssd.json", SmartHealth{
+			Passed: true, Health: 92, PercentageUsed: 8, ReallocatedSectors: 2, PowerOnHours: 12874, Temperature: 34,
+		}},
+		// 自检失败时健康度为 0，机械盘没有磨损属性
+		{"smartctl-hdd-failing.json", SmartHealth{
+			Passed: false, Health: 0, MediaErrors: 412, ReallocatedSectors: 3968, PowerOnHours: 31207, Temperature: 38,
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			data, err := os.ReadFile(filepath.Join("testdata/smart", tt.file))
+			if err != nil {
+				t.Fatal(err)
+			}
+			health, err := ParseSmartctl(data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if *health != tt.want {
+				t.Errorf("got %+v\nwant %+v", *health, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseSmartctlErrors(t *testing.T) {
+	data, err := os.ReadFile("testdata/smart/smartctl-permission-denied.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ParseSmartctl(data); err == nil || !strings.Contains(err.Error(), "Permission denied") {
+		t.Errorf("expected the smartctl error message, got %v", err)
+	}
+
+	if _, err := ParseSmartctl([]byte(`{"smartctl": {"exit_status": 4}}`)); err == nil || !strings.Contains(err.Error(), "exit status 4") {
+		t.Errorf("expected the exit status without a message, got %v", err)
+	}
+	if _, err := ParseSmartctl(nil); err == nil {
+		t.Error("expected an error for empty output")
+	}
+}
+
+func TestParseNVMeSmartLog(t *testing.T) {
+	tests := []struct {
+		file string
+		want SmartHealth
+	}{
+		// 温度以开尔文输出
+		{"nvme-smart-log.json", SmartHealth{
+			Passed: true, Health: 93, PercentageUsed: 7, PowerOnHours: 17342, Temperature: 43,
+		}},
+		// critical_warning 非零视为失败，percent_used 超过 100 表示超出额定寿命
+		{"nvme-smart-log-worn.json", SmartHealth{
+			Passed: false, Health: 0, PercentageUsed: 112, MediaErrors: 27, PowerOnHours: 43811, Temperature: 58,
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			data, err := os.ReadFile(filepath.Join("testdata/smart", tt.file))
+			if err != nil {
+				t.Fatal(err)
+			}
+			health, err := ParseNVMeSmartLog(data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if *health != tt.want {
+				t.Errorf("got %+v\nwant %+v", *health, tt.want)
+			}
+		})
+	}
+
+	if _, err := ParseNVMeSmartLog([]byte("Error: Permission denied")); err == nil {
+		t.Error("expected an error for non-JSON output")
+	}
+}
+
+// fakeSmartctl 在 PATH 中放一个输出 script 的 smartctl，PATH 中只有它一个工具
+func fakeSmartctl(t *testing.T, script string) {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "smartctl"), []byte("#!/bin/sh\n"+script+"\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir)
+}
+
+func TestSmart(t *testing.T) {
+	fixture, err := filepath.Abs("testdata/smart/smartctl-sata-ssd.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fakeSmartctl(t, "exec /bin/cat "+fixture)
+
+	health, err := smart(context.Background(), "sda")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !health.Passed || health.PercentageUsed != 8 {
+		t.Errorf("got %+v", *health)
+	}
+}
+
+func TestSmartCanceled(t *testing.T) {
+	fakeSmartctl(t, "exec /bin/sleep 30")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if _, err := smart(ctx, "sda"); err == nil {
+		t.Fatal("expected an error when smartctl is killed")
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("smart took %v after the context expired", elapsed)
+	}
+}
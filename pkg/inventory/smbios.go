@@ -0,0 +1,194 @@
+package inventory
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// SMBIOS 结构类型
+const (
+	smbiosBIOS         = 0
+	smbiosSystem       = 1
+	smbiosBaseboard    = 2
+	smbiosMemoryDevice = 17
+	smbiosEndOfTable   = 127
+)
+
+// Structure 是 SMBIOS 表中的一个结构，Formatted 包含 4 字节头部
+type Structure struct {
+	Type      uint8
+	Handle    uint16
+	Formatted []byte
+	Strings   []string
+}
+
+// byteAt 返回格式化区域中偏移处的字节，超出结构长度时返回 0
+func (s *Structure) byteAt(offset int) uint8 {
+	if offset >= len(s.Formatted) {
+		return 0
+	}
+	return s.Formatted[offset]
+}
+
+// word 返回格式化区域中偏移处的小端 16 位值，超出结构长度时返回 0
+func (s *Structure) word(offset int) uint16 {
+	if offset+2 > len(s.Formatted) {
+		return 0
+	}
+	return binary.LittleEndian.Uint16(s.Formatted[offset:])
+}
+
+// dword 返回格式化区域中偏移处的小端 32 位值，超出结构长度时返回 0
+func (s *Structure) dword(offset int) uint32 {
+	if offset+4 > len(s.Formatted) {
+		return 0
+	}
+	return binary.LittleEndian.Uint32(s.Formatted[offset:])
+}
+
+// str 返回偏移处字节引用的字符串，编号从 1 开始，0 表示无
+func (s *Structure) str(offset int) string {
+	index := int(s.byteAt(offset))
+	if index == 0 || index > len(s.Strings) {
+		return ""
+	}
+	return strings.TrimSpace(s.Strings[index-1])
+}
+
+// ParseSMBIOS 解析 /sys/firmware/dmi/tables/DMI 中的原始结构表
+func ParseSMBIOS(data []byte) ([]Structure, error) {
+	var structures []Structure
+	for offset := 0; offset+4 <= len(data); {
+		typ := data[offset]
+		length := int(data[offset+1])
+		if length < 4 || offset+length > len(data) {
+			return structures, fmt.Errorf("structure at offset %d has invalid length %d", offset, length)
+		}
+
+		s := Structure{
+			Type:      typ,
+			Handle:    binary.LittleEndian.Uint16(data[offset+2:]),
+			Formatted: data[offset : offset+length],
+		}
+
+		// 字符串区域由以 NUL 结尾的字符串组成，以两个连续的 NUL 结束
+		end := offset + length
+		for {
+			if end >= len(data) {
+				return structures, fmt.Errorf("structure at offset %d has unterminated strings", offset)
+			}
+			if data[end] == 0 {
+				break
+			}
+			start := end
+			for end < len(data) && data[end] != 0 {
+				end++
+			}
+			s.Strings = append(s.Strings, string(data[start:end]))
+			end++
+		}
+		// 没有字符串的结构以两个 NUL 结束
+		if len(s.Strings) == 0 {
+			end++
+		}
+		offset = end + 1
+
+		structures = append(structures, s)
+		if typ == smbiosEndOfTable {
+			break
+		}
+	}
+
+	if len(structures) == 0 {
+		return nil, errors.New("no SMBIOS structures found")
+	}
+	return structures, nil
+}
+
+// memoryTypes 将 SMBIOS 内存类型编号映射为名称
+var memoryTypes = map[uint8]string{
+	0x07: "RAM",
+	0x0F: "SDRAM",
+	0x12: "DDR",
+	0x13: "DDR2",
+	0x18: "DDR3",
+	0x1A: "DDR4",
+	0x1B: "LPDDR",
+	0x1C: "LPDDR2",
+	0x1D: "LPDDR3",
+	0x1E: "LPDDR4",
+	0x20: "HBM",
+	0x21: "HBM2",
+	0x22: "DDR5",
+	0x23: "LPDDR5",
+	0x24: "HBM3",
+}
+
+// memoryDevice 是一个 type 17 内存设备
+type memoryDevice struct {
+	Locator      string
+	Bank         string
+	Size         int // MB，0 表示未安装
+	Type         string
+	Speed        int // MT/s，最大速率
+	Configured   int // MT/s，当前配置的速率
+	Manufacturer string
+	SerialNumber string
+	PartNumber   string
+}
+
+// parseMemoryDevice 解析 type 17 结构
+func parseMemoryDevice(s *Structure) memoryDevice {
+	device := memoryDevice{
+		Locator:      s.str(0x10),
+		Bank:         s.str(0x11),
+		Type:         memoryTypes[s.byteAt(0x12)],
+		Manufacturer: s.str(0x17),
+		SerialNumber: s.str(0x18),
+		PartNumber:   s.str(0x1A),
+	}
+
+	switch size := s.word(0x0C); {
+	case size == 0 || size == 0xFFFF:
+		// 未安装或未知
+	case size == 0x7FFF:
+		device.Size = int(s.dword(0x1C) & 0x7FFFFFFF)
+	case size&0x8000 != 0:
+		device.Size = int(size&0x7FFF) / 1024
+	default:
+		device.Size = int(size)
+	}
+
+	device.Speed = int(s.word(0x15))
+	if device.Speed == 0xFFFF {
+		device.Speed = int(s.dword(0x54))
+	}
+	device.Configured = int(s.word(0x20))
+	if device.Configured == 0xFFFF {
+		device.Configured = int(s.dword(0x58))
+	}
+	return device
+}
+
+// smbiosUUID 按 SMBIOS 2.6 规定的字节序格式化 type 1 中的 UUID
+func smbiosUUID(b []byte) string {
+	if len(b) != 16 {
+		return ""
+	}
+	allSame := true
+	for _, v := range b[1:] {
+		if v != b[0] {
+			allSame = false
+			break
+		}
+	}
+	// 全 0 表示未设置，全 FF 表示未知
+	if allSame && (b[0] == 0x00 || b[0] == 0xFF) {
+		return ""
+	}
+	return fmt.Sprintf("%08x-%04x-%04x-%x-%x",
+		binary.LittleEndian.Uint32(b[0:4]), binary.LittleEndian.Uint16(b[4:6]),
+		binary.LittleEndian.Uint16(b[6:8]), b[8:10], b[10:16])
+}
@@ -0,0 +1,14 @@
+//go:build !windows
+
+package inventory
+
+import "syscall"
+
+// filesystemUsed 返回挂载点所在文件系统的已用字节数
+func filesystemUsed(path string) (uint64, error) {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(path, &stat); err != nil {
+		return 0, err
+	}
+	return (stat.Blocks - stat.Bfree) * uint64(stat.Bsize), nil
+}
@@ -0,0 +1,8 @@
+package inventory
+
+import "errors"
+
+// filesystemUsed 在 Windows 上不可用，清单采集只支持 Linux 的 /proc 和 /sys
+func filesystemUsed(path string) (uint64, error) {
+	return 0, errors.New("not supported on windows")
+}
@@ -0,0 +1,151 @@
+package inventory
+
+import (
+	"bufio"
+	"context"
+	"octane/pkg/types"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// sectorSize 是 /sys/block/*/size 使用的扇区大小
+const sectorSize = 512
+
+// Storage 从 /sys/block 读取物理块设备，跳过 loop、device-mapper 等没有底层设备的虚拟设备。
+// ctx 用于读取本机 SMART 数据的 smartctl 和 nvme-cli。
+func (c *Collector) Storage(ctx context.Context) []types.StorageInfo {
+	entries, err := os.ReadDir(c.path("sys/block"))
+	if err != nil {
+		return nil
+	}
+
+	mounts := c.mounts()
+	var devices []types.StorageInfo
+	for _, entry := range entries {
+		name := entry.Name()
+		if !c.exists("sys/block", name, "device") {
+			continue
+		}
+
+		device := types.StorageInfo{
+			Name:      name,
+			Model:     c.readString("sys/block", name, "device/model"),
+			Type:      storageType(name, c.readInt("sys/block", name, "queue/rotational")),
+			Interface: storageInterface(name),
+			Capacity:  int(c.readInt("sys/block", name, "size") * sectorSize >> 20),
+		}
+		device.Temperature = c.hwmonTemperature("sys/block", name, "device")
+		if c.live {
+			device.Used = usedSpace(mounts, name)
+			if health, err := smart(ctx, name); err == nil {
+				applySmart(&device, health)
+			}
+		}
+		devices = append(devices, device)
+	}
+
+	sort.Slice(devices, func(i, j int) bool { return devices[i].Name < devices[j].Name })
+	return devices
+}
+
+// hwmonTemperature 读取设备 hwmon 节点的温度（°C），NVMe 和 drivetemp 驱动的 SATA 盘无需 root 即可读取
+func (c *Collector) hwmonTemperature(parts ...string) int {
+	base := c.path(parts...)
+	for _, pattern := range []string{"hwmon*/temp1_input", "hwmon/hwmon*/temp1_input"} {
+		matches, _ := filepath.Glob(filepath.Join(base, pattern))
+		for _, path := range matches {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				continue
+			}
+			if millidegrees, err := strconv.Atoi(strings.TrimSpace(string(data))); err == nil {
+				return millidegrees / 1000
+			}
+		}
+	}
+	return 0
+}
+
+// storageType 根据设备名和 rotational 标志判断介质类型
+func storageType(name string, rotational int64) string {
+	switch {
+	case strings.HasPrefix(name, "nvme"):
+		return "NVMe SSD"
+	case rotational == 1:
+		return "HDD"
+	default:
+		return "SSD"
+	}
+}
+
+// storageInterface 根据内核设备名推断接口类型
+func storageInterface(name string) string {
+	switch {
+	case strings.HasPrefix(name, "nvme"):
+		return "NVMe"
+	case strings.HasPrefix(name, "sd"):
+		return "SATA/SAS"
+	case strings.HasPrefix(name, "vd"):
+		return "virtio"
+	case strings.HasPrefix(name, "xvd"):
+		return "Xen"
+	case strings.HasPrefix(name, "mmcblk"):
+		return "MMC"
+	default:
+		return ""
+	}
+}
+
+// mounts 解析 /proc/mounts，返回 /dev 下的设备名到挂载点的映射
+func (c *Collector) mounts() map[string][]string {
+	mounts := make(map[string][]string)
+
+	file, err := os.Open(c.path("proc/mounts"))
+	if err != nil {
+		return mounts
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 || !strings.HasPrefix(fields[0], "/dev/") {
+			continue
+		}
+		device := strings.TrimPrefix(fields[0], "/dev/")
+		mounts[device] = append(mounts[device], fields[1])
+	}
+	return mounts
+}
+
+// usedSpace 汇总磁盘及其分区上已挂载文件系统的已用空间（MB），同一分区只统计一次
+func usedSpace(mounts map[string][]string, disk string) int {
+	var used uint64
+	for device, points := range mounts {
+		if device != disk && !isPartition(disk, device) {
+			continue
+		}
+		if bytes, err := filesystemUsed(points[0]); err == nil {
+			used += bytes
+		}
+	}
+	return int(used >> 20)
+}
+
+// isPartition 判断 device 是否为 disk 的分区，如 sda1 或 nvme0n1p2。
+// 磁盘名以数字结尾时分区号前必须有 "p"，否则 nvme0n10 会被当作 nvme0n1 的分区。
+func isPartition(disk, device string) bool {
+	suffix, ok := strings.CutPrefix(device, disk)
+	if !ok || disk == "" {
+		return false
+	}
+	if last := disk[len(disk)-1]; last >= '0' && last <= '9' {
+		if suffix, ok = strings.CutPrefix(suffix, "p"); !ok {
+			return false
+		}
+	}
+	return suffix != "" && strings.Trim(suffix, "0123456789") == ""
+}
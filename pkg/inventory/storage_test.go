@@ -0,0 +1,58 @@
+package inventory
+
+import (
+	"context"
+	"octane/pkg/types"
+	"testing"
+)
+
+func TestStorage(t *testing.T) {
+	devices := NewCollector("testdata/system").Storage(context.Background())
+
+	// loop0 和 dm-0 没有 device 节点，不计入；样例目录树不读取挂载用量和 SMART
+	want := []types.StorageInfo{
+		{Name: "nvme0n1", Model: "Samsung SSD 980 PRO 1TB", Type: "NVMe SSD", Interface: "NVMe", Capacity: 953869, Temperature: 41},
+		{Name: "sda", Model: "ST4000NM0035-1V4", Type: "HDD", Interface: "SATA/SAS", Capacity: 3815447, Temperature: 34},
+		{Name: "vda", Type: "HDD", Interface: "virtio", Capacity: 20480},
+	}
+	if len(devices) != len(want) {
+		t.Fatalf("Storage = %+v, want %d devices", devices, len(want))
+	}
+	for i := range want {
+		if devices[i] != want[i] {
+			t.Errorf("device %d = %+v\nwant %+v", i, devices[i], want[i])
+		}
+	}
+
+	if devices := NewCollector(t.TempDir()).Storage(context.Background()); devices != nil {
+		t.Errorf("Storage without /sys/block = %+v", devices)
+	}
+}
+
+func TestIsPartition(t *testing.T) {
+	tests := []struct {
+		disk, device string
+		want         bool
+	}{
+		{"sda", "sda1", true},
+		{"sda", "sda12", true},
+		{"sda", "sda", false},
+		{"sda", "sdab", false},
+		{"sda", "sdb1", false},
+		{"vda", "vdap1", false},
+		{"nvme0n1", "nvme0n1p2", true},
+		{"nvme0n1", "nvme0n1p", false},
+		// 磁盘名以数字结尾时分区号前必须有 "p"
+		{"nvme0n1", "nvme0n10", false},
+		{"nvme0n1", "nvme0n10p1", false},
+		{"nvme0n10", "nvme0n10p1", true},
+		{"mmcblk0", "mmcblk0p1", true},
+		{"mmcblk0", "mmcblk01", false},
+		{"loop1", "loop10", false},
+	}
+	for _, tt := range tests {
+		if got := isPartition(tt.disk, tt.device); got != tt.want {
+			t.Errorf("isPartition(%q, %q) = %v, want %v", tt.disk, tt.device, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package inventory
+
+import (
+	"octane/pkg/types"
+	"strings"
+)
+
+// azureAssetTag 是 Azure 虚拟机固定的机箱资产标签
+const azureAssetTag = "7783-7084-3265-9085-8269-3286-77"
+
+// vendorMatch 将 DMI 字符串中的片段映射为名称
+type vendorMatch struct {
+	fragment string
+	name     string
+}
+
+// hypervisors 按 DMI 厂商和产品字符串识别虚拟化平台，先匹配的优先
+var hypervisors = []vendorMatch{
+	{"kvm", "kvm"},
+	{"qemu", "kvm"},
+	{"amazon ec2", "kvm"},
+	{"google compute engine", "kvm"},
+	{"vmware", "vmware"},
+	{"virtualbox", "virtualbox"},
+	{"innotek", "virtualbox"},
+	{"xen", "xen"},
+	{"microsoft corporation", "hyperv"},
+	{"parallels", "parallels"},
+	{"bochs", "bochs"},
+	{"bhyve", "bhyve"},
+}
+
+// clouds 按 DMI 字符串识别云厂商
+var clouds = []vendorMatch{
+	{"amazon ec2", "aws"},
+	{"google compute engine", "gcp"},
+	{"alibaba cloud", "alibaba"},
+	{"tencent cloud", "tencent"},
+	{"huawei cloud", "huawei"},
+	{"digitalocean", "digitalocean"},
+	{"hetzner", "hetzner"},
+	{"linode", "linode"},
+	{"vultr", "vultr"},
+	{"oraclecloud", "oracle"},
+	{"openstack", "openstack"},
+}
+
+// Virtualization 根据 CPU 标志、/sys/hypervisor 和 DMI 字符串识别虚拟化平台和云厂商，
+// 需要在 DMI 之后调用
+func (c *Collector) Virtualization(info *types.SystemInfo) {
+	host := &info.Host
+	strs := []string{
+		info.Product.Manufacturer, info.Product.Name, info.Product.Version,
+		info.BIOS.Vendor, info.BIOS.Version, info.Board.Manufacturer,
+	}
+
+	host.Hypervisor = matchVendor(hypervisors, strs)
+	if host.Hypervisor == "" {
+		host.Hypervisor = c.readString("sys", "hypervisor", "type")
+	}
+	// Hyper-V 的 DMI 厂商与物理 Surface 等设备相同，需同时确认产品名
+	if host.Hypervisor == "hyperv" && !strings.Contains(strings.ToLower(info.Product.Name), "virtual machine") {
+		host.Hypervisor = ""
+	}
+	// 识别不出厂商但 CPU 处于虚拟机中
+	if host.Hypervisor == "" && c.cpuFlag("hypervisor") {
+		host.Hypervisor = "unknown"
+	}
+
+	host.Cloud = matchVendor(clouds, strs)
+	if host.Cloud == "" {
+		switch {
+		case c.readString("sys", "class", "dmi", "id", "chassis_asset_tag") == azureAssetTag:
+			host.Cloud = "azure"
+		case strings.HasPrefix(strings.ToLower(c.readString("sys", "hypervisor", "uuid")), "ec2"):
+			// 旧的 Xen 实例在 DMI 中只有 "Xen HVM domU"
+			host.Cloud = "aws"
+		case strings.Contains(strings.ToLower(info.BIOS.Version), "amazon"):
+			host.Cloud = "aws"
+		}
+	}
+}
+
+// matchVendor 返回第一个在任意字符串中出现的片段对应的名称
+func matchVendor(matches []vendorMatch, strs []string) string {
+	for _, match := range matches {
+		for _, s := range strs {
+			if strings.Contains(strings.ToLower(s), match.fragment) {
+				return match.name
+			}
+		}
+	}
+	return ""
+}
+
+// cpuFlag 判断 /proc/cpuinfo 的 flags 中是否包含指定标志
+func (c *Collector) cpuFlag(flag string) bool {
+	for _, line := range strings.Split(c.readString("proc", "cpuinfo"), "\n") {
+		key, value, ok := strings.Cut(line, ":")
+		if !ok || strings.TrimSpace(key) != "flags" {
+			continue
+		}
+		for _, f := range strings.Fields(value) {
+			if f == flag {
+				return true
+			}
+		}
+		// 所有处理器的标志相同，只看第一个
+		return false
+	}
+	return false
+}
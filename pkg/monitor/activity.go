@@ -0,0 +1,275 @@
+package monitor
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"octane/pkg/types"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// userHZ 是 /proc 中 CPU 时间的单位，Linux 上固定为 100
+const userHZ = 100
+
+// sectorSize 是 /proc/diskstats 中扇区计数的单位
+const sectorSize = 512
+
+// activity 是一次系统活动快照
+type activity struct {
+	at      time.Time
+	cpu     CPUTimes
+	procs   map[int]process
+	disk    uint64 // 读写字节数
+	network uint64 // 收发字节数，不含回环接口
+}
+
+// process 是 /proc/[pid]/stat 中的进程名和累计 CPU 时间
+type process struct {
+	name  string
+	ticks uint64
+}
+
+// readActivity 读取 root 下 /proc 的活动计数，单项不可读时计为 0
+func readActivity(root string) (*activity, error) {
+	cpu, err := ReadCPUTimes(root)
+	if err != nil {
+		return nil, err
+	}
+	a := &activity{at: time.Now(), cpu: cpu}
+	a.procs = readProcesses(root)
+	a.disk = readDiskBytes(root)
+	a.network = readNetworkBytes(root)
+	return a, nil
+}
+
+// readProcesses 读取所有进程的 CPU 时间，跳过 octane 自身
+func readProcesses(root string) map[int]process {
+	procs := make(map[int]process)
+	entries, err := os.ReadDir(filepath.Join(root, "proc"))
+	if err != nil {
+		return procs
+	}
+
+	self := os.Getpid()
+	for _, entry := range entries {
+		pid, err := strconv.Atoi(entry.Name())
+		if err != nil || pid == self {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(root, "proc", entry.Name(), "stat"))
+		if err != nil {
+			continue
+		}
+		if p, ok := parseProcessStat(string(data)); ok {
+			procs[pid] = p
+		}
+	}
+	return procs
+}
+
+// parseProcessStat 解析 /proc/[pid]/stat。进程名可能包含空格和括号，因此以最后一个 ')' 分隔。
+func parseProcessStat(stat string) (process, bool) {
+	open := strings.IndexByte(stat, '(')
+	end := strings.LastIndexByte(stat, ')')
+	if open < 0 || end < open {
+		return process{}, false
+	}
+
+	// ')' 之后从 state（第 3 个字段）开始，utime 和 stime 是第 14、15 个字段
+	fields := strings.Fields(stat[end+1:])
+	if len(fields) < 13 {
+		return process{}, false
+	}
+	utime, err1 := strconv.ParseUint(fields[11], 10, 64)
+	stime, err2 := strconv.ParseUint(fields[12], 10, 64)
+	if err1 != nil || err2 != nil {
+		return process{}, false
+	}
+	return process{name: stat[open+1 : end], ticks: utime + stime}, true
+}
+
+// readDiskBytes 返回整块磁盘累计读写的字节数，分区、loop 和 ram 设备不计入以免重复
+func readDiskBytes(root string) uint64 {
+	file, err := os.Open(filepath.Join(root, "proc", "diskstats"))
+	if err != nil {
+		return 0
+	}
+	defer file.Close()
+
+	var total uint64
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 10 {
+			continue
+		}
+		name := fields[2]
+		if strings.HasPrefix(name, "loop") || strings.HasPrefix(name, "ram") {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(root, "sys", "block", name)); err != nil {
+			continue
+		}
+		read, _ := strconv.ParseUint(fields[5], 10, 64)
+		written, _ := strconv.ParseUint(fields[9], 10, 64)
+		total += (read + written) * sectorSize
+	}
+	return total
+}
+
+// readNetworkBytes 返回除回环接口外所有接口累计收发的字节数
+func readNetworkBytes(root string) uint64 {
+	file, err := os.Open(filepath.Join(root, "proc", "net", "dev"))
+	if err != nil {
+		return 0
+	}
+	defer file.Close()
+
+	var total uint64
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		name, counters, ok := strings.Cut(scanner.Text(), ":")
+		if !ok || strings.TrimSpace(name) == "lo" {
+			continue
+		}
+		fields := strings.Fields(counters)
+		if len(fields) < 9 {
+			continue
+		}
+		rx, _ := strconv.ParseUint(fields[0], 10, 64)
+		tx, _ := strconv.ParseUint(fields[8], 10, 64)
+		total += rx + tx
+	}
+	return total
+}
+
+// readLoad1 读取 1 分钟平均负载
+func readLoad1(root string) float64 {
+	data, err := os.ReadFile(filepath.Join(root, "proc", "loadavg"))
+	if err != nil {
+		return 0
+	}
+	fields := strings.Fields(string(data))
+	if len(fields) == 0 {
+		return 0
+	}
+	load, _ := strconv.ParseFloat(fields[0], 64)
+	return load
+}
+
+// backgroundLoad 计算两次快照之间的后台负载，top 为保留的最繁忙进程数
+func backgroundLoad(before, after *activity, top int) types.BackgroundLoad {
+	seconds := after.at.Sub(before.at).Seconds()
+	if seconds <= 0 {
+		return types.BackgroundLoad{}
+	}
+
+	var load types.BackgroundLoad
+	if total := float64(after.cpu.Total()) - float64(before.cpu.Total()); total > 0 {
+		idle := float64(after.cpu.Idle-before.cpu.Idle) + float64(after.cpu.IOWait-before.cpu.IOWait)
+		load.CPU = max(0, (total-idle)/total*100)
+	}
+	if after.disk >= before.disk {
+		load.Disk = float64(after.disk-before.disk) / seconds / (1 << 20)
+	}
+	if after.network >= before.network {
+		load.Network = float64(after.network-before.network) / seconds / (1 << 20)
+	}
+
+	for pid, p := range after.procs {
+		prev, ok := before.procs[pid]
+		if !ok || p.ticks <= prev.ticks {
+			continue
+		}
+		load.Processes = append(load.Processes, types.ProcessLoad{
+			PID:  pid,
+			Name: p.name,
+			CPU:  float64(p.ticks-prev.ticks) / userHZ / seconds * 100,
+		})
+	}
+	sort.Slice(load.Processes, func(i, j int) bool { return load.Processes[i].CPU > load.Processes[j].CPU })
+	if len(load.Processes) > top {
+		load.Processes = load.Processes[:top]
+	}
+	return load
+}
+
+// SampleActivity 在 interval 内采样系统后台负载：整机 CPU 占用、磁盘和网络吞吐以及最繁忙的进程
+func SampleActivity(ctx context.Context, root string, interval time.Duration, top int) (types.BackgroundLoad, error) {
+	before, err := readActivity(root)
+	if err != nil {
+		return types.BackgroundLoad{}, err
+	}
+
+	timer := time.NewTimer(interval)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-ctx.Done():
+		return types.BackgroundLoad{}, ctx.Err()
+	}
+
+	after, err := readActivity(root)
+	if err != nil {
+		return types.BackgroundLoad{}, err
+	}
+	load := backgroundLoad(before, after, top)
+	load.Load1 = readLoad1(root)
+	return load, nil
+}
+
+// QuietOptions 定义系统空闲的判定阈值和等待方式
+type QuietOptions struct {
+	Root     string        // 文件系统根目录，通常为 "/"
+	CPU      float64       // %，整机 CPU 占用上限
+	Disk     float64       // MB/s，磁盘读写上限
+	Network  float64       // MB/s，网络收发上限
+	Timeout  time.Duration // 等待系统空闲的最长时间
+	Interval time.Duration // 每次采样的时长
+	Top      int           // 记录的最繁忙进程数
+}
+
+// Busy 返回负载超出阈值的原因，空闲时返回空字符串
+func (o QuietOptions) Busy(load types.BackgroundLoad) string {
+	var reasons []string
+	if o.CPU > 0 && load.CPU > o.CPU {
+		reasons = append(reasons, fmt.Sprintf("cpu %.0f%% > %.0f%%", load.CPU, o.CPU))
+	}
+	if o.Disk > 0 && load.Disk > o.Disk {
+		reasons = append(reasons, fmt.Sprintf("disk %.1f MB/s > %.1f MB/s", load.Disk, o.Disk))
+	}
+	if o.Network > 0 && load.Network > o.Network {
+		reasons = append(reasons, fmt.Sprintf("network %.1f MB/s > %.1f MB/s", load.Network, o.Network))
+	}
+	return strings.Join(reasons, ", ")
+}
+
+// WaitQuiet 反复采样直到系统空闲或超时，返回最后一次采样结果。
+// 超时后 load.Contended 为 true；上下文取消时返回上下文的错误。
+func WaitQuiet(ctx context.Context, opts QuietOptions) (types.BackgroundLoad, error) {
+	interval := opts.Interval
+	if interval <= 0 {
+		interval = 2 * time.Second
+	}
+
+	start := time.Now()
+	for {
+		load, err := SampleActivity(ctx, opts.Root, interval, opts.Top)
+		if err != nil {
+			return load, err
+		}
+		load.Waited = time.Since(start).Round(time.Second).String()
+		if opts.Busy(load) == "" {
+			return load, nil
+		}
+		if time.Since(start)+interval > opts.Timeout {
+			load.Contended = true
+			return load, nil
+		}
+	}
+}
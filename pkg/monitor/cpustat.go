@@ -0,0 +1,70 @@
+package monitor
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// CPUTimes 是 /proc/stat 中汇总 cpu 行的各项时间（单位为 USER_HZ）
+type CPUTimes struct {
+	User, Nice, System, Idle, IOWait, IRQ, SoftIRQ, Steal uint64
+}
+
+// Total 返回所有时间之和。guest 时间已计入 user，不再重复累加。
+func (t CPUTimes) Total() uint64 {
+	return t.User + t.Nice + t.System + t.Idle + t.IOWait + t.IRQ + t.SoftIRQ + t.Steal
+}
+
+// ReadCPUTimes 读取 root 下 /proc/stat 的汇总 cpu 行
+func ReadCPUTimes(root string) (CPUTimes, error) {
+	file, err := os.Open(filepath.Join(root, "proc/stat"))
+	if err != nil {
+		return CPUTimes{}, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || fields[0] != "cpu" {
+			continue
+		}
+		return parseCPULine(fields[1:])
+	}
+	if err := scanner.Err(); err != nil {
+		return CPUTimes{}, err
+	}
+	return CPUTimes{}, fmt.Errorf("no cpu line in /proc/stat")
+}
+
+// parseCPULine 解析 cpu 行的数值，旧内核缺少的字段视为 0
+func parseCPULine(fields []string) (CPUTimes, error) {
+	values := make([]uint64, 8)
+	for i := 0; i < len(values) && i < len(fields); i++ {
+		v, err := strconv.ParseUint(fields[i], 10, 64)
+		if err != nil {
+			return CPUTimes{}, fmt.Errorf("invalid /proc/stat value %q", fields[i])
+		}
+		values[i] = v
+	}
+	return CPUTimes{
+		User: values[0], Nice: values[1], System: values[2], Idle: values[3],
+		IOWait: values[4], IRQ: values[5], SoftIRQ: values[6], Steal: values[7],
+	}, nil
+}
+
+// Share 返回两次采样之间 steal 和 iowait 时间占总 CPU 时间的百分比。
+// 内核的 iowait 计数可能回退，差值为负时按 0 计，避免无符号减法回绕。
+func Share(before, after CPUTimes) (steal, iowait float64) {
+	total := float64(after.Total()) - float64(before.Total())
+	if total <= 0 {
+		return 0, 0
+	}
+	steal = max(0, float64(after.Steal)-float64(before.Steal)) / total * 100
+	iowait = max(0, float64(after.IOWait)-float64(before.IOWait)) / total * 100
+	return steal, iowait
+}
@@ -0,0 +1,42 @@
+package monitor
+
+import (
+	"math"
+	"testing"
+)
+
+func TestShare(t *testing.T) {
+	base := CPUTimes{User: 1000, System: 500, Idle: 8000, IOWait: 300, Steal: 200}
+	tests := []struct {
+		name          string
+		after         CPUTimes
+		steal, iowait float64
+	}{
+		// 共 1000 个时钟周期，其中 steal 100、iowait 50
+		{"busy", CPUTimes{User: 1600, System: 700, Idle: 8050, IOWait: 350, Steal: 300}, 10, 5},
+		// iowait 计数回退 100，总时间仍然增加：iowait 按 0 计，而不是回绕成巨大的百分比
+		{"iowait decreases", CPUTimes{User: 1600, System: 700, Idle: 8200, IOWait: 200, Steal: 300}, 10, 0},
+		{"no time passed", base, 0, 0},
+		{"counters reset", CPUTimes{User: 10, Idle: 20}, 0, 0},
+	}
+	for _, tt := range tests {
+		steal, iowait := Share(base, tt.after)
+		if math.Abs(steal-tt.steal) > 1e-9 || math.Abs(iowait-tt.iowait) > 1e-9 {
+			t.Errorf("%s: Share = %v, %v; want %v, %v", tt.name, steal, iowait, tt.steal, tt.iowait)
+		}
+	}
+}
+
+func TestParseCPULine(t *testing.T) {
+	// 2.6.11 之前的内核没有 steal 字段
+	times, err := parseCPULine([]string{"10", "1", "5", "100", "2", "0", "3"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (CPUTimes{User: 10, Nice: 1, System: 5, Idle: 100, IOWait: 2, SoftIRQ: 3}); times != want {
+		t.Errorf("parseCPULine = %+v, want %+v", times, want)
+	}
+	if _, err := parseCPULine([]string{"10", "-1"}); err == nil {
+		t.Error("expected an error for a negative value")
+	}
+}
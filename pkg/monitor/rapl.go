@@ -0,0 +1,229 @@
+package monitor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"octane/pkg/types"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RAPL 能耗域
+const (
+	DomainPackage = "package"
+	DomainCore    = "core"
+	DomainDRAM    = "dram"
+)
+
+// RAPLZone 是 powercap 中的一个 RAPL 计数器
+type RAPLZone struct {
+	Path     string // 区域目录，例如 /sys/class/powercap/intel-rapl:0
+	Domain   string // package、core 或 dram
+	MaxRange uint64 // µJ，计数器在此值处回绕
+}
+
+// FindRAPL 查找 root 下 /sys/class/powercap/intel-rapl:* 中的 package、core 和 dram 计数器。
+// 多路服务器每个插槽各有一组，uncore 和 psys 不计入。
+func FindRAPL(root string) ([]RAPLZone, error) {
+	dirs, err := filepath.Glob(filepath.Join(root, "sys", "class", "powercap", "intel-rapl:*"))
+	if err != nil {
+		return nil, err
+	}
+
+	var zones []RAPLZone
+	for _, dir := range dirs {
+		name, err := os.ReadFile(filepath.Join(dir, "name"))
+		if err != nil {
+			continue
+		}
+		zone := RAPLZone{Path: dir, Domain: raplDomain(strings.TrimSpace(string(name)))}
+		if zone.Domain == "" {
+			continue
+		}
+		zone.MaxRange, _ = readUint(filepath.Join(dir, "max_energy_range_uj"))
+		zones = append(zones, zone)
+	}
+	if len(zones) == 0 {
+		return nil, errors.New("no RAPL energy counters (requires an Intel or AMD CPU with the powercap driver)")
+	}
+	return zones, nil
+}
+
+// raplDomain 将区域名映射为能耗域，例如 package-0 → package
+func raplDomain(name string) string {
+	switch {
+	case strings.HasPrefix(name, "package"):
+		return DomainPackage
+	case name == "core":
+		return DomainCore
+	case name == "dram":
+		return DomainDRAM
+	}
+	return ""
+}
+
+// Read 读取当前的累计能耗（µJ）
+func (z RAPLZone) Read() (uint64, error) {
+	return readUint(filepath.Join(z.Path, "energy_uj"))
+}
+
+// EnergyDelta 返回两次读数之间消耗的能量，计数器回绕时按 maxRange 补齐
+func EnergyDelta(before, after, maxRange uint64) uint64 {
+	if after >= before {
+		return after - before
+	}
+	if maxRange < before {
+		// 最大范围未知或不正确，无法确定回绕前的剩余量
+		return after
+	}
+	return maxRange - before + after
+}
+
+// readUint 读取只包含一个无符号整数的文件
+func readUint(path string) (uint64, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseUint(strings.TrimSpace(string(data)), 10, 64)
+}
+
+// PowerSampler 在测试期间周期读取 RAPL 计数器并累计能耗。
+// 周期读取保证计数器在两次读数之间最多回绕一次。
+type PowerSampler struct {
+	zones  []RAPLZone
+	reason string
+	start  time.Time
+	stop   chan struct{}
+	done   chan struct{}
+
+	mu     sync.Mutex
+	last   []uint64
+	energy map[string]uint64 // µJ，按能耗域累计
+}
+
+// StartPowerSampler 开始采样 root 下的 RAPL 计数器；计数器不存在或不可读时 Stop 返回不可用状态
+func StartPowerSampler(root string) *PowerSampler {
+	s := &PowerSampler{
+		start:  time.Now(),
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+		energy: make(map[string]uint64),
+	}
+
+	zones, err := FindRAPL(root)
+	if err == nil {
+		s.last = make([]uint64, len(zones))
+		for i, zone := range zones {
+			if s.last[i], err = zone.Read(); err != nil {
+				break
+			}
+		}
+	}
+	if err != nil {
+		if errors.Is(err, os.ErrPermission) {
+			err = errors.New("RAPL energy counters are readable by root only")
+		}
+		s.reason = err.Error()
+		close(s.done)
+		return s
+	}
+	s.zones = zones
+
+	go s.loop()
+	return s
+}
+
+func (s *PowerSampler) loop() {
+	defer close(s.done)
+
+	ticker := time.NewTicker(sampleInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+			s.observe()
+		}
+	}
+}
+
+// observe 读取所有计数器，累计自上次读数以来的能耗
+func (s *PowerSampler) observe() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, zone := range s.zones {
+		now, err := zone.Read()
+		if err != nil {
+			continue
+		}
+		s.energy[zone.Domain] += EnergyDelta(s.last[i], now, zone.MaxRange)
+		s.last[i] = now
+	}
+}
+
+// Stop 结束采样，返回各能耗域的总能耗和平均功率
+func (s *PowerSampler) Stop() types.PowerSample {
+	if s.zones == nil {
+		return types.PowerSample{Reason: s.reason}
+	}
+	close(s.stop)
+	<-s.done
+	s.observe()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	seconds := time.Since(s.start).Seconds()
+	sample := types.PowerSample{Available: true, Seconds: seconds}
+	for domain, uj := range s.energy {
+		reading := types.EnergyReading{Joules: float64(uj) / 1e6}
+		if seconds > 0 {
+			reading.Watts = reading.Joules / seconds
+		}
+		switch domain {
+		case DomainPackage:
+			sample.Package = reading
+		case DomainCore:
+			sample.Core = reading
+		case DomainDRAM:
+			sample.DRAM = reading
+		}
+	}
+	return sample
+}
+
+// powerKey 是上下文中功耗采样开关的键
+type powerKey struct{}
+
+// WithPowerMonitoring 返回设置了功耗采样开关的上下文
+func WithPowerMonitoring(ctx context.Context, enabled bool) context.Context {
+	return context.WithValue(ctx, powerKey{}, enabled)
+}
+
+// PowerMonitoring 判断上下文是否开启了功耗采样，未设置时关闭
+func PowerMonitoring(ctx context.Context) bool {
+	enabled, _ := ctx.Value(powerKey{}).(bool)
+	return enabled
+}
+
+// FormatPower 返回功耗采样的简短描述
+func FormatPower(sample types.PowerSample) string {
+	if !sample.Available {
+		return "unavailable: " + sample.Reason
+	}
+	s := fmt.Sprintf("package %.1f W (%.0f J)", sample.Package.Watts, sample.Package.Joules)
+	if sample.Core.Joules > 0 {
+		s += fmt.Sprintf(", core %.1f W", sample.Core.Watts)
+	}
+	if sample.DRAM.Joules > 0 {
+		s += fmt.Sprintf(", dram %.1f W", sample.DRAM.Watts)
+	}
+	return s
+}
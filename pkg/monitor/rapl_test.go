@@ -0,0 +1,113 @@
+package monitor
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+)
+
+// powercapRoot 将 testdata/powercap 复制到临时目录，使测试可以改写计数器
+func powercapRoot(t *testing.T) string {
+	t.Helper()
+	root := t.TempDir()
+	if err := os.CopyFS(root, os.DirFS("testdata/powercap")); err != nil {
+		t.Fatal(err)
+	}
+	return root
+}
+
+// setEnergy 改写区域的 energy_uj 计数器
+func setEnergy(t *testing.T, root, zone, value string) {
+	t.Helper()
+	path := filepath.Join(root, "sys/class/powercap", zone, "energy_uj")
+	if err := os.WriteFile(path, []byte(value+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFindRAPL(t *testing.T) {
+	zones, err := FindRAPL("testdata/powercap")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// uncore、psys 和 intel-rapl-mmio 不计入
+	var got []string
+	for _, zone := range zones {
+		got = append(got, filepath.Base(zone.Path)+"="+zone.Domain)
+	}
+	sort.Strings(got)
+	want := []string{"intel-rapl:0:0=core", "intel-rapl:0:1=dram", "intel-rapl:0=package", "intel-rapl:1:0=dram", "intel-rapl:1=package"}
+	if len(got) != len(want) {
+		t.Fatalf("zones = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("zones = %v, want %v", got, want)
+		}
+	}
+	for _, zone := range zones {
+		if zone.MaxRange == 0 {
+			t.Errorf("%s: max_energy_range_uj not read", zone.Path)
+		}
+	}
+
+	if _, err := FindRAPL(t.TempDir()); err == nil {
+		t.Error("expected an error without powercap")
+	}
+}
+
+func TestEnergyDelta(t *testing.T) {
+	tests := []struct {
+		before, after, maxRange, want uint64
+	}{
+		{1000, 5000, 262143328850, 4000},
+		// 回绕：回绕前剩余 328850 µJ，回绕后 671150 µJ
+		{262143000000, 671150, 262143328850, 1000000},
+		// 最大范围未知时只计入回绕后的部分
+		{262143000000, 671150, 0, 671150},
+		{7, 7, 100, 0},
+	}
+	for _, tt := range tests {
+		if got := EnergyDelta(tt.before, tt.after, tt.maxRange); got != tt.want {
+			t.Errorf("EnergyDelta(%d, %d, %d) = %d, want %d", tt.before, tt.after, tt.maxRange, got, tt.want)
+		}
+	}
+}
+
+func TestPowerSamplerWrap(t *testing.T) {
+	root := powercapRoot(t)
+	sampler := StartPowerSampler(root)
+
+	// package-0 和第一路的 dram 在 max_energy_range_uj 处回绕
+	setEnergy(t, root, "intel-rapl:0", "671150")
+	setEnergy(t, root, "intel-rapl:1", "2001000")
+	setEnergy(t, root, "intel-rapl:0:0", "505000")
+	setEnergy(t, root, "intel-rapl:0:1", "387")
+	setEnergy(t, root, "intel-rapl:1:0", "1001000")
+	sample := sampler.Stop()
+
+	if !sample.Available {
+		t.Fatalf("sampler unavailable: %s", sample.Reason)
+	}
+	if sample.Package.Joules != 3 {
+		t.Errorf("package = %g J, want 3", sample.Package.Joules)
+	}
+	if sample.Core.Joules != 0.5 {
+		t.Errorf("core = %g J, want 0.5", sample.Core.Joules)
+	}
+	if sample.DRAM.Joules != 1 {
+		t.Errorf("dram = %g J, want 1", sample.DRAM.Joules)
+	}
+	if sample.Seconds <= 0 || sample.Package.Watts <= 0 {
+		t.Errorf("expected a duration and average power, got %+v", sample)
+	}
+}
+
+func TestPowerSamplerUnavailable(t *testing.T) {
+	sample := StartPowerSampler(t.TempDir()).Stop()
+	if sample.Available || sample.Reason == "" {
+		t.Errorf("expected an unavailable sample with a reason, got %+v", sample)
+	}
+}
@@ -0,0 +1,153 @@
+package monitor
+
+import (
+	"context"
+	"octane/pkg/types"
+	"sync"
+	"time"
+)
+
+// sampleInterval 是测试期间采样 /proc/stat 的间隔
+const sampleInterval = time.Second
+
+// StealSampler 在测试期间周期采样 CPU steal 和 iowait
+type StealSampler struct {
+	root   string
+	first  CPUTimes
+	ok     bool
+	stop   chan struct{}
+	done   chan struct{}
+	mu     sync.Mutex
+	last   CPUTimes
+	peak   float64
+	sample types.StealSample
+}
+
+// StartStealSampler 开始采样，root 通常为 "/"；/proc/stat 不可读时 Stop 返回零值
+func StartStealSampler(root string) *StealSampler {
+	s := &StealSampler{
+		root: root,
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+
+	first, err := ReadCPUTimes(root)
+	if err != nil {
+		close(s.done)
+		return s
+	}
+	s.first, s.last, s.ok = first, first, true
+
+	go s.loop()
+	return s
+}
+
+func (s *StealSampler) loop() {
+	defer close(s.done)
+
+	ticker := time.NewTicker(sampleInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+			s.observe()
+		}
+	}
+}
+
+// observe 记录一个采样区间，并更新峰值 steal
+func (s *StealSampler) observe() {
+	now, err := ReadCPUTimes(s.root)
+	if err != nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if steal, _ := Share(s.last, now); steal > s.peak {
+		s.peak = steal
+	}
+	s.last = now
+}
+
+// Stop 结束采样，返回整个测试期间的平均值和采样区间内的峰值
+func (s *StealSampler) Stop() types.StealSample {
+	if !s.ok {
+		return types.StealSample{}
+	}
+	close(s.stop)
+	<-s.done
+	s.observe()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	steal, iowait := Share(s.first, s.last)
+	return types.StealSample{Steal: steal, IOWait: iowait, PeakSteal: max(s.peak, steal)}
+}
+
+// recorderKey 是上下文中 Recorder 的键
+type recorderKey struct{}
+
+// Recorder 收集一组测试的 steal 和能耗采样结果
+type Recorder struct {
+	mu      sync.Mutex
+	samples map[string]types.StealSample
+	power   map[string]types.PowerSample
+}
+
+// WithRecorder 返回携带新 Recorder 的上下文，在其中运行的基准测试会记录采样结果
+func WithRecorder(ctx context.Context) (context.Context, *Recorder) {
+	r := &Recorder{
+		samples: make(map[string]types.StealSample),
+		power:   make(map[string]types.PowerSample),
+	}
+	return context.WithValue(ctx, recorderKey{}, r), r
+}
+
+// Record 将测试的采样结果写入上下文中的 Recorder，没有 Recorder 时忽略
+func Record(ctx context.Context, name string, sample types.StealSample) {
+	r, ok := ctx.Value(recorderKey{}).(*Recorder)
+	if !ok {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.samples[name] = sample
+}
+
+// RecordPower 将测试的能耗写入上下文中的 Recorder，没有 Recorder 时忽略
+func RecordPower(ctx context.Context, name string, sample types.PowerSample) {
+	r, ok := ctx.Value(recorderKey{}).(*Recorder)
+	if !ok {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.power[name] = sample
+}
+
+// Samples 返回已记录的采样结果副本
+func (r *Recorder) Samples() map[string]types.StealSample {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	samples := make(map[string]types.StealSample, len(r.samples))
+	for name, sample := range r.samples {
+		samples[name] = sample
+	}
+	return samples
+}
+
+// Power 返回已记录的能耗副本
+func (r *Recorder) Power() map[string]types.PowerSample {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	power := make(map[string]types.PowerSample, len(r.power))
+	for name, sample := range r.power {
+		power[name] = sample
+	}
+	return power
+}
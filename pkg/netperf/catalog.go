@@ -0,0 +1,249 @@
+package netperf
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/url"
+	"octane/pkg/executor"
+	"octane/pkg/types"
+	"os"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// 目标协议
+const (
+	ProtocolTCPPeer      = "tcp-peer"      // octane network serve
+	ProtocolIperf3       = "iperf3"        // iperf3 -s
+	ProtocolHTTPDownload = "http-download" // HTTP(S) 文件下载
+)
+
+// Target 是网络测试目录中的一个目标
+type Target struct {
+	Name        string  `yaml:"name"`
+	Region      string  `yaml:"region"`       // domestic 或 international
+	Protocol    string  `yaml:"protocol"`     // tcp-peer、iperf3 或 http-download
+	Address     string  `yaml:"address"`      // host[:port]，http-download 为 URL
+	MinDownload float64 `yaml:"min_download"` // Mbps，期望的最低下载带宽，0 表示不检查
+
+	// http-download 的参数
+	Connections int    `yaml:"connections"` // 并行连接数，0 表示使用 streams 的设置
+	RangeSize   string `yaml:"range_size"`  // 如 "8MB"，设置时按段发出 Range 请求
+}
+
+// Catalog 是网络测试目标的目录
+type Catalog struct {
+	Targets []Target `yaml:"targets"`
+}
+
+// LoadCatalog 读取 YAML 格式的目标目录，文件不存在时返回空目录
+func LoadCatalog(path string) (*Catalog, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &Catalog{}, nil
+		}
+		return nil, err
+	}
+	return ParseCatalog(data)
+}
+
+// ParseCatalog 解析并校验目标目录
+func ParseCatalog(data []byte) (*Catalog, error) {
+	var catalog Catalog
+	if err := yaml.Unmarshal(data, &catalog); err != nil {
+		return nil, fmt.Errorf("malformed network catalog: %v", err)
+	}
+	if err := catalog.Validate(); err != nil {
+		return nil, err
+	}
+	return &catalog, nil
+}
+
+// Validate 检查目标的名称、地区、协议和地址，未填写的地区视为 domestic
+func (c *Catalog) Validate() error {
+	seen := make(map[string]bool)
+	for i := range c.Targets {
+		t := &c.Targets[i]
+		if t.Name == "" {
+			return fmt.Errorf("network target %d has no name", i+1)
+		}
+		if seen[t.Name] {
+			return fmt.Errorf("duplicate network target %s", t.Name)
+		}
+		seen[t.Name] = true
+
+		switch t.Region {
+		case "":
+			t.Region = RegionDomestic
+		case RegionDomestic, RegionInternational:
+		default:
+			return fmt.Errorf("network target %s: invalid region %q (want domestic or international)", t.Name, t.Region)
+		}
+
+		switch t.Protocol {
+		case ProtocolTCPPeer, ProtocolIperf3:
+			if t.Address == "" {
+				return fmt.Errorf("network target %s has no address", t.Name)
+			}
+		case ProtocolHTTPDownload:
+			u, err := url.Parse(t.Address)
+			if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				return fmt.Errorf("network target %s: invalid URL %q", t.Name, t.Address)
+			}
+			if t.RangeSize != "" {
+				if _, err := executor.ParseSize(t.RangeSize); err != nil {
+					return fmt.Errorf("network target %s: %v", t.Name, err)
+				}
+			}
+		default:
+			return fmt.Errorf("network target %s: unknown protocol %q (want tcp-peer, iperf3 or http-download)", t.Name, t.Protocol)
+		}
+		if t.MinDownload < 0 {
+			return fmt.Errorf("network target %s: min_download must not be negative", t.Name)
+		}
+	}
+	return nil
+}
+
+// Iperf3Target 将 iperf3 服务端配置转换为目录目标
+func Iperf3Target(server Iperf3Server) Target {
+	host, port := server.addr()
+	address := net.JoinHostPort(host, port)
+	name := server.Name
+	if name == "" {
+		name = address
+	}
+	region := server.Region
+	if region == "" {
+		region = RegionDomestic
+	}
+	return Target{Name: name, Region: region, Protocol: ProtocolIperf3, Address: address}
+}
+
+// CatalogRunner 按目录测试网络目标
+type CatalogRunner struct {
+	Options     Options // tcp-peer 和 http-download 的参数，Peer 字段不使用
+	Iperf3      *Iperf3 // iperf3 客户端，为空时 iperf3 目标失败
+	Concurrency int     // 同时测试的目标数，同一地址的目标总是依次测试
+}
+
+// Run 并发测试所有目标，结果按地区写入 Domestic 或 International。
+// 失败的目标记录在 Errors 中，低于期望带宽的目标记录在 Warnings 中；全部失败时返回错误。
+func (r *CatalogRunner) Run(ctx context.Context, targets []Target) (*types.NetworkResults, error) {
+	if len(targets) == 0 {
+		return nil, errors.New("no network targets configured")
+	}
+
+	start := time.Now()
+	results := &types.NetworkResults{TestSuite: "octane-network-catalog"}
+	results.Bandwidth.Domestic = make(map[string]types.BandwidthResult)
+	results.Bandwidth.International = make(map[string]types.BandwidthResult)
+
+	// 同一地址的测试互相干扰，iperf3 服务端也只接受一个测试，因此按地址串行
+	addresses := make(map[string]*sync.Mutex)
+	for _, t := range targets {
+		if addresses[t.Address] == nil {
+			addresses[t.Address] = &sync.Mutex{}
+		}
+	}
+
+	sem := make(chan struct{}, max(1, r.Concurrency))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, t := range targets {
+		wg.Add(1)
+		go func(t Target) {
+			defer wg.Done()
+			lock := addresses[t.Address]
+			lock.Lock()
+			defer lock.Unlock()
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			defer func() { <-sem }()
+
+			result, err := r.runTarget(ctx, t)
+			if ctx.Err() != nil {
+				return
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				log.Printf("network target %s: %v", t.Name, err)
+				if results.Errors == nil {
+					results.Errors = make(map[string]string)
+				}
+				results.Errors[t.Name] = err.Error()
+				return
+			}
+			if t.MinDownload > 0 && result.Download < t.MinDownload {
+				results.Warnings = append(results.Warnings, fmt.Sprintf("%s: download %.0f Mbps is below the expected %.0f Mbps",
+					t.Name, result.Download, t.MinDownload))
+			}
+			if t.Region == RegionInternational {
+				results.Bandwidth.International[t.Name] = *result
+			} else {
+				results.Bandwidth.Domestic[t.Name] = *result
+			}
+		}(t)
+	}
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if len(results.Errors) == len(targets) {
+		var errs []error
+		for _, t := range targets {
+			errs = append(errs, fmt.Errorf("%s: %s", t.Name, results.Errors[t.Name]))
+		}
+		return nil, errors.Join(errs...)
+	}
+	sort.Strings(results.Warnings)
+	results.Duration = time.Since(start).Round(time.Second).String()
+	return results, nil
+}
+
+// runTarget 按协议测试单个目标
+func (r *CatalogRunner) runTarget(ctx context.Context, t Target) (*types.BandwidthResult, error) {
+	switch t.Protocol {
+	case ProtocolTCPPeer:
+		opts := r.Options
+		opts.Peer = t.Address
+		return Run(ctx, opts)
+	case ProtocolIperf3:
+		if r.Iperf3 == nil {
+			return nil, errors.New("iperf3 is not installed")
+		}
+		host, port, err := net.SplitHostPort(t.Address)
+		if err != nil {
+			host, port = t.Address, strconv.Itoa(Iperf3Port)
+		}
+		server := Iperf3Server{Name: t.Name, Host: host, Region: t.Region}
+		server.Port, _ = strconv.Atoi(port)
+		return r.Iperf3.Run(ctx, server)
+	case ProtocolHTTPDownload:
+		opts := r.Options.withDefaults()
+		download := HTTPOptions{Connections: t.Connections, Duration: opts.Duration}
+		if download.Connections <= 0 {
+			download.Connections = opts.Streams
+		}
+		if t.RangeSize != "" {
+			size, _ := executor.ParseSize(t.RangeSize)
+			download.RangeSize = int64(size)
+		}
+		return HTTPDownload(ctx, t.Address, download)
+	}
+	return nil, fmt.Errorf("unknown protocol %q", t.Protocol)
+}
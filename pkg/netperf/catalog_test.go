@@ -0,0 +1,138 @@
+package netperf
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseCatalog(t *testing.T) {
+	tests := []struct {
+		name string
+		yaml string
+		err  string
+	}{
+		{"malformed", "targets: {", "malformed network catalog"},
+		{"no name", "targets:\n  - protocol: tcp-peer\n    address: peer", "network target 1 has no name"},
+		{"duplicate", "targets:\n  - {name: a, protocol: tcp-peer, address: p1}\n  - {name: a, protocol: tcp-peer, address: p2}", "duplicate network target a"},
+		{"bad region", "targets:\n  - {name: a, region: asia, protocol: tcp-peer, address: p}", `invalid region "asia"`},
+		{"no address", "targets:\n  - {name: a, protocol: iperf3}", "network target a has no address"},
+		{"bad URL", "targets:\n  - {name: a, protocol: http-download, address: ftp://mirror/file}", `invalid URL "ftp://mirror/file"`},
+		{"URL without host", "targets:\n  - {name: a, protocol: http-download, address: 'https:///file'}", "invalid URL"},
+		{"bad range size", "targets:\n  - {name: a, protocol: http-download, address: 'https://mirror/file', range_size: lots}", "network target a:"},
+		{"unknown protocol", "targets:\n  - {name: a, protocol: ping, address: p}", `unknown protocol "ping"`},
+		{"no protocol", "targets:\n  - {name: a, address: p}", `unknown protocol ""`},
+		{"negative minimum", "targets:\n  - {name: a, protocol: tcp-peer, address: p, min_download: -1}", "min_download must not be negative"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseCatalog([]byte(tt.yaml)); err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("ParseCatalog = %v, want an error containing %q", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestParseCatalogRegions(t *testing.T) {
+	catalog, err := ParseCatalog([]byte(`targets:
+  - {name: peer, protocol: tcp-peer, address: 10.0.0.2}
+  - {name: mirror, region: international, protocol: http-download, address: "https://mirror.example.com/1G.bin", range_size: 8MB}
+  - {name: dc1, region: domestic, protocol: iperf3, address: "iperf.dc1.example.com:5201"}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 未填写地区的目标视为 domestic
+	want := []string{RegionDomestic, RegionInternational, RegionDomestic}
+	for i, target := range catalog.Targets {
+		if target.Region != want[i] {
+			t.Errorf("%s: region %q, want %q", target.Name, target.Region, want[i])
+		}
+	}
+
+	if target := Iperf3Target(Iperf3Server{Host: "iperf.example.com"}); target.Name != "iperf.example.com:5201" || target.Region != RegionDomestic {
+		t.Errorf("Iperf3Target = %+v", target)
+	}
+}
+
+// fileHandler 提供 4 KiB 的文件，/broken 返回 500
+func fileHandler(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == "/broken" {
+		http.Error(w, "gone", http.StatusInternalServerError)
+		return
+	}
+	w.Write([]byte(strings.Repeat("x", 4096)))
+}
+
+func TestCatalogRunnerSerializesAddress(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(fileHandler))
+	defer ts.Close()
+
+	// a1 和 a2 的地址相同，必须依次测试，总耗时至少为两个测试时长；b 可以与它们同时测试
+	targets := []Target{
+		{Name: "a1", Region: RegionDomestic, Protocol: ProtocolHTTPDownload, Address: ts.URL + "/same", Connections: 1},
+		{Name: "a2", Region: RegionDomestic, Protocol: ProtocolHTTPDownload, Address: ts.URL + "/same", Connections: 1},
+		{Name: "b", Region: RegionInternational, Protocol: ProtocolHTTPDownload, Address: ts.URL + "/other", Connections: 1},
+	}
+	const duration = 200 * time.Millisecond
+	runner := &CatalogRunner{Options: Options{Duration: duration}, Concurrency: 3}
+	start := time.Now()
+	results, err := runner.Run(context.Background(), targets)
+	if err != nil {
+		t.Fatal(err)
+	}
+	elapsed := time.Since(start)
+
+	if elapsed < 2*duration {
+		t.Errorf("run took %v, want at least %v for the two targets at the same address", elapsed, 2*duration)
+	}
+	if len(results.Bandwidth.Domestic) != 2 || len(results.Bandwidth.International) != 1 || len(results.Errors) != 0 {
+		t.Errorf("results: domestic %v, international %v, errors %v",
+			results.Bandwidth.Domestic, results.Bandwidth.International, results.Errors)
+	}
+}
+
+func TestCatalogRunnerFailures(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(fileHandler))
+	defer ts.Close()
+	runner := &CatalogRunner{Options: Options{Duration: 100 * time.Millisecond}, Concurrency: 4}
+
+	// 部分目标失败时其余结果照常返回，失败记录在 Errors 中，带宽不足记录在 Warnings 中
+	results, err := runner.Run(context.Background(), []Target{
+		{Name: "ok", Region: RegionDomestic, Protocol: ProtocolHTTPDownload, Address: ts.URL + "/file", Connections: 1},
+		{Name: "slow", Region: RegionInternational, Protocol: ProtocolHTTPDownload, Address: ts.URL + "/slow", Connections: 1, MinDownload: 1e9},
+		{Name: "broken", Region: RegionDomestic, Protocol: ProtocolHTTPDownload, Address: ts.URL + "/broken", Connections: 1},
+		{Name: "iperf", Region: RegionDomestic, Protocol: ProtocolIperf3, Address: "203.0.113.10"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := results.Bandwidth.Domestic["ok"]; !ok || len(results.Bandwidth.Domestic) != 1 {
+		t.Errorf("domestic = %v, want ok only", results.Bandwidth.Domestic)
+	}
+	if _, ok := results.Bandwidth.International["slow"]; !ok {
+		t.Errorf("international = %v, want slow", results.Bandwidth.International)
+	}
+	if !strings.Contains(results.Errors["broken"], "500") || results.Errors["iperf"] != "iperf3 is not installed" || len(results.Errors) != 2 {
+		t.Errorf("errors = %v", results.Errors)
+	}
+	if len(results.Warnings) != 1 || !strings.HasPrefix(results.Warnings[0], "slow: download") {
+		t.Errorf("warnings = %v", results.Warnings)
+	}
+
+	// 全部失败时返回包含每个目标的错误
+	_, err = runner.Run(context.Background(), []Target{
+		{Name: "broken", Protocol: ProtocolHTTPDownload, Address: ts.URL + "/broken", Connections: 1},
+		{Name: "iperf", Protocol: ProtocolIperf3, Address: "203.0.113.10"},
+	})
+	if err == nil || !strings.Contains(err.Error(), "broken: ") || !strings.Contains(err.Error(), "iperf: iperf3 is not installed") {
+		t.Errorf("all targets failing: got %v", err)
+	}
+
+	if _, err := runner.Run(context.Background(), nil); err == nil {
+		t.Error("expected an error without targets")
+	}
+}
@@ -0,0 +1,229 @@
+package netperf
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"octane/pkg/types"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// dialTimeout 是连接对端的超时时间
+const dialTimeout = 10 * time.Second
+
+// Options 定义对端网络测试的参数
+type Options struct {
+	Peer       string        // 对端地址 host[:port]
+	Streams    int           // TCP 并行连接数
+	Duration   time.Duration // 上传和下载各自的时长
+	Pings      int           // 测量延迟的往返次数
+	UDPRate    float64       // Mbps，UDP 发送速率，0 表示不测 UDP
+	PacketSize int           // UDP 包大小（字节）
+}
+
+// withDefaults 填充未设置的参数
+func (o Options) withDefaults() Options {
+	if o.Streams <= 0 {
+		o.Streams = 4
+	}
+	if o.Duration <= 0 {
+		o.Duration = 10 * time.Second
+	}
+	if o.Pings <= 0 {
+		o.Pings = 10
+	}
+	if o.PacketSize <= 0 {
+		o.PacketSize = 1200
+	}
+	o.Peer = PeerAddr(o.Peer)
+	return o
+}
+
+// PeerAddr 为没有端口的对端地址补上默认端口
+func PeerAddr(peer string) string {
+	if _, _, err := net.SplitHostPort(peer); err == nil {
+		return peer
+	}
+	return net.JoinHostPort(strings.Trim(peer, "[]"), strconv.Itoa(DefaultPort))
+}
+
+// Run 依次测量 TCP 延迟、下载、上传以及（设置了速率时）UDP 抖动和丢包
+func Run(ctx context.Context, opts Options) (*types.BandwidthResult, error) {
+	opts = opts.withDefaults()
+	result := &types.BandwidthResult{}
+
+	latency, err := Latency(ctx, opts.Peer, opts.Pings)
+	if err != nil {
+		return nil, fmt.Errorf("latency: %v", err)
+	}
+	result.Latency = latency
+
+	if result.Download, err = Download(ctx, opts.Peer, opts.Streams, opts.Duration); err != nil {
+		return nil, fmt.Errorf("download: %v", err)
+	}
+	if result.Upload, err = Upload(ctx, opts.Peer, opts.Streams, opts.Duration); err != nil {
+		return nil, fmt.Errorf("upload: %v", err)
+	}
+
+	if opts.UDPRate > 0 {
+		udp, err := UDP(ctx, opts.Peer, opts.UDPRate, opts.PacketSize, opts.Duration)
+		if err != nil {
+			return nil, fmt.Errorf("udp: %v", err)
+		}
+		result.Jitter = udp.Jitter
+		result.PacketLoss = udp.Loss
+	}
+	return result, nil
+}
+
+// dial 连接对端并发送协议头部，上下文取消时关闭连接
+func dial(ctx context.Context, peer string, args ...string) (net.Conn, func() bool, error) {
+	dialer := net.Dialer{Timeout: dialTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp", peer)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	header := strings.Join(append([]string{protocolMagic, protocolVersion}, args...), " ") + "\n"
+	if _, err := io.WriteString(conn, header); err != nil {
+		conn.Close()
+		return nil, nil, err
+	}
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	return conn, stop, nil
+}
+
+// Latency 通过回显连接测量 count 次 TCP 往返，返回中位数（毫秒）
+func Latency(ctx context.Context, peer string, count int) (float64, error) {
+	conn, stop, err := dial(ctx, peer, modeEcho)
+	if err != nil {
+		return 0, err
+	}
+	defer stop()
+	defer conn.Close()
+
+	reader := bufio.NewReader(conn)
+	rtts := make([]float64, 0, count)
+	for i := 0; i < count; i++ {
+		conn.SetDeadline(time.Now().Add(dialTimeout))
+		start := time.Now()
+		if _, err := fmt.Fprintf(conn, "%d\n", i); err != nil {
+			return 0, ctxErr(ctx, err)
+		}
+		if _, err := reader.ReadString('\n'); err != nil {
+			return 0, ctxErr(ctx, err)
+		}
+		rtts = append(rtts, float64(time.Since(start))/float64(time.Millisecond))
+	}
+
+	sort.Float64s(rtts)
+	return rtts[len(rtts)/2], nil
+}
+
+// Download 用 streams 个并行连接从对端接收 d 时间的数据，返回吞吐（Mbps）
+func Download(ctx context.Context, peer string, streams int, d time.Duration) (float64, error) {
+	var total atomic.Int64
+	start := time.Now()
+	err := parallel(streams, func() error {
+		conn, stop, err := dial(ctx, peer, modeDownload, strconv.FormatInt(d.Milliseconds(), 10))
+		if err != nil {
+			return err
+		}
+		defer stop()
+		defer conn.Close()
+
+		conn.SetReadDeadline(time.Now().Add(d + dialTimeout))
+		n, err := io.Copy(io.Discard, conn)
+		total.Add(n)
+		return ctxErr(ctx, err)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return mbps(total.Load(), time.Since(start)), nil
+}
+
+// Upload 用 streams 个并行连接向对端发送 d 时间的数据，返回对端实际收到数据的吞吐（Mbps）
+func Upload(ctx context.Context, peer string, streams int, d time.Duration) (float64, error) {
+	var total atomic.Int64
+	start := time.Now()
+	err := parallel(streams, func() error {
+		conn, stop, err := dial(ctx, peer, modeUpload)
+		if err != nil {
+			return err
+		}
+		defer stop()
+		defer conn.Close()
+
+		buf := make([]byte, bufferSize)
+		conn.SetWriteDeadline(start.Add(d))
+		for {
+			if _, err := conn.Write(buf); err != nil {
+				if errors.Is(err, os.ErrDeadlineExceeded) {
+					break
+				}
+				return ctxErr(ctx, err)
+			}
+		}
+
+		// 半关闭后等待对端确认收到的字节数，发送缓冲区中的数据也计入耗时
+		if tcp, ok := conn.(*net.TCPConn); ok {
+			tcp.CloseWrite()
+		}
+		conn.SetReadDeadline(time.Now().Add(dialTimeout))
+		line, err := bufio.NewReader(conn).ReadString('\n')
+		if err != nil {
+			return ctxErr(ctx, err)
+		}
+		n, err := strconv.ParseInt(strings.TrimSpace(line), 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid byte count %q from peer", strings.TrimSpace(line))
+		}
+		total.Add(n)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return mbps(total.Load(), time.Since(start)), nil
+}
+
+// parallel 并发运行 n 个任务，合并返回所有任务的错误
+func parallel(n int, task func() error) error {
+	var wg sync.WaitGroup
+	errs := make([]error, n)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = task()
+		}(i)
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+// ctxErr 在上下文已取消时返回上下文的错误，而不是连接被关闭导致的错误
+func ctxErr(ctx context.Context, err error) error {
+	if err != nil && ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return err
+}
+
+// mbps 将字节数和耗时换算为 Mbps
+func mbps(bytes int64, elapsed time.Duration) float64 {
+	if elapsed <= 0 {
+		return 0
+	}
+	return float64(bytes) * 8 / 1e6 / elapsed.Seconds()
+}
@@ -0,0 +1,340 @@
+package netperf
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"net/http"
+	"net/url"
+	"octane/pkg/types"
+	"sort"
+	"strings"
+	"time"
+)
+
+// 解析器传输方式
+const (
+	DNSSystem = "system" // 系统解析器（/etc/resolv.conf、nsswitch）
+	DNSUDP    = "udp"
+	DNSTCP    = "tcp"
+	DNSTLS    = "tls"   // DNS over TLS
+	DNSHTTPS  = "https" // DNS over HTTPS
+)
+
+// DNS 报文中用到的常量
+const (
+	dnsHeader    = 12
+	dnsTypeA     = 1
+	dnsClassIN   = 1
+	dnsMaxPacket = 4096
+)
+
+var dnsRcodes = map[int]string{
+	1: "FORMERR",
+	2: "SERVFAIL",
+	3: "NXDOMAIN",
+	4: "NOTIMP",
+	5: "REFUSED",
+}
+
+// Resolver 是被测试的 DNS 解析器
+type Resolver struct {
+	Name      string // 配置中的写法，作为结果中的解析器名称
+	Transport string // system、udp、tcp、tls 或 https
+	Address   string // host:port，https 为 DoH 的 URL
+}
+
+// ParseResolver 解析 "system"、"udp://host[:port]"、"tcp://host[:port]"、"tls://host[:port]"
+// 或 DoH 的 "https://..." URL。没有前缀的地址按 UDP 处理。
+func ParseResolver(s string) (Resolver, error) {
+	if s == DNSSystem {
+		return Resolver{Name: s, Transport: DNSSystem}, nil
+	}
+
+	transport, address, found := strings.Cut(s, "://")
+	if !found {
+		transport, address = DNSUDP, s
+	}
+	if address == "" {
+		return Resolver{}, fmt.Errorf("resolver %q has no address", s)
+	}
+
+	port := "53"
+	switch transport {
+	case DNSUDP, DNSTCP:
+	case DNSTLS:
+		port = "853"
+	case DNSHTTPS:
+		u, err := url.Parse(s)
+		if err != nil || u.Host == "" {
+			return Resolver{}, fmt.Errorf("invalid DoH URL %q", s)
+		}
+		return Resolver{Name: s, Transport: DNSHTTPS, Address: s}, nil
+	default:
+		return Resolver{}, fmt.Errorf("resolver %q: unknown transport %q (want udp, tcp, tls or https)", s, transport)
+	}
+	if _, _, err := net.SplitHostPort(address); err != nil {
+		address = net.JoinHostPort(strings.Trim(address, "[]"), port)
+	}
+	return Resolver{Name: s, Transport: transport, Address: address}, nil
+}
+
+// Query 查询一次 name 的 A 记录，返回耗时。每次查询都新建连接，
+// 因此 TCP、DoT 和 DoH 的耗时包含建立连接和 TLS 握手。
+func (r Resolver) Query(ctx context.Context, name string, timeout time.Duration) (time.Duration, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	if r.Transport == DNSSystem {
+		if _, err := net.DefaultResolver.LookupHost(ctx, name); err != nil {
+			return 0, err
+		}
+		return time.Since(start), nil
+	}
+
+	query, id, err := dnsQuery(name)
+	if err != nil {
+		return 0, err
+	}
+	var response []byte
+	switch r.Transport {
+	case DNSUDP:
+		response, err = r.exchangeUDP(ctx, query)
+	case DNSTCP, DNSTLS:
+		response, err = r.exchangeStream(ctx, query)
+	case DNSHTTPS:
+		response, err = r.exchangeHTTPS(ctx, query)
+	default:
+		err = fmt.Errorf("unknown transport %q", r.Transport)
+	}
+	if err != nil {
+		return 0, ctxErr(ctx, err)
+	}
+	elapsed := time.Since(start)
+	if err := dnsCheck(response, id); err != nil {
+		return 0, err
+	}
+	return elapsed, nil
+}
+
+// exchangeUDP 通过 UDP 发送查询并读取一个响应
+func (r Resolver) exchangeUDP(ctx context.Context, query []byte) ([]byte, error) {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "udp", r.Address)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	if _, err := conn.Write(query); err != nil {
+		return nil, err
+	}
+	buf := make([]byte, dnsMaxPacket)
+	n, err := conn.Read(buf)
+	if err != nil {
+		return nil, err
+	}
+	return buf[:n], nil
+}
+
+// exchangeStream 通过 TCP 或 TLS 发送带两字节长度前缀的查询
+func (r Resolver) exchangeStream(ctx context.Context, query []byte) ([]byte, error) {
+	var conn net.Conn
+	var err error
+	if r.Transport == DNSTLS {
+		host, _, _ := net.SplitHostPort(r.Address)
+		dialer := tls.Dialer{Config: &tls.Config{ServerName: host}}
+		conn, err = dialer.DialContext(ctx, "tcp", r.Address)
+	} else {
+		var dialer net.Dialer
+		conn, err = dialer.DialContext(ctx, "tcp", r.Address)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	msg := binary.BigEndian.AppendUint16(nil, uint16(len(query)))
+	if _, err := conn.Write(append(msg, query...)); err != nil {
+		return nil, err
+	}
+	var length [2]byte
+	if _, err := io.ReadFull(conn, length[:]); err != nil {
+		return nil, err
+	}
+	response := make([]byte, binary.BigEndian.Uint16(length[:]))
+	if _, err := io.ReadFull(conn, response); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// dohClient 不复用连接，使每次 DoH 查询的耗时与 DoT 一样包含建立连接
+var dohClient = &http.Client{Transport: &http.Transport{
+	Proxy:             http.ProxyFromEnvironment,
+	DisableKeepAlives: true,
+}}
+
+// exchangeHTTPS 按 RFC 8484 以 POST 发送 DoH 查询
+func (r Resolver) exchangeHTTPS(ctx context.Context, query []byte) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.Address, bytes.NewReader(query))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/dns-message")
+	req.Header.Set("Accept", "application/dns-message")
+
+	resp, err := dohClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+}
+
+// dnsQuery 构造一个递归查询 name 的 A 记录的报文，返回报文和随机的 ID
+func dnsQuery(name string) ([]byte, uint16, error) {
+	var idBytes [2]byte
+	rand.Read(idBytes[:])
+	id := binary.BigEndian.Uint16(idBytes[:])
+
+	msg := make([]byte, dnsHeader, dnsHeader+len(name)+6)
+	binary.BigEndian.PutUint16(msg[0:], id)
+	binary.BigEndian.PutUint16(msg[2:], 0x0100) // RD
+	binary.BigEndian.PutUint16(msg[4:], 1)      // QDCOUNT
+
+	name = strings.TrimSuffix(name, ".")
+	if name == "" || len(name) > 253 {
+		return nil, 0, fmt.Errorf("invalid name %q", name)
+	}
+	for _, label := range strings.Split(name, ".") {
+		if len(label) == 0 || len(label) > 63 {
+			return nil, 0, fmt.Errorf("invalid name %q", name)
+		}
+		msg = append(msg, byte(len(label)))
+		msg = append(msg, label...)
+	}
+	msg = append(msg, 0)
+	msg = binary.BigEndian.AppendUint16(msg, dnsTypeA)
+	msg = binary.BigEndian.AppendUint16(msg, dnsClassIN)
+	return msg, id, nil
+}
+
+// dnsCheck 检查响应的 ID、QR 标志和响应码
+func dnsCheck(response []byte, id uint16) error {
+	if len(response) < dnsHeader {
+		return errors.New("short DNS response")
+	}
+	if binary.BigEndian.Uint16(response[0:]) != id {
+		return errors.New("DNS response ID mismatch")
+	}
+	flags := binary.BigEndian.Uint16(response[2:])
+	if flags&0x8000 == 0 {
+		return errors.New("DNS response is not a reply")
+	}
+	if rcode := int(flags & 0x000f); rcode != 0 {
+		if name, ok := dnsRcodes[rcode]; ok {
+			return fmt.Errorf("DNS %s", name)
+		}
+		return fmt.Errorf("DNS rcode %d", rcode)
+	}
+	return nil
+}
+
+// DNSOptions 定义 DNS 测试的参数
+type DNSOptions struct {
+	Queries int           // 每个名称对每个解析器的查询次数，第一次视为冷查询
+	Timeout time.Duration // 单次查询的超时
+}
+
+// DNS 对每个解析器依次查询每个名称 Queries 次。第一次查询为冷查询（解析器可能没有缓存），
+// 之后的查询命中解析器缓存，中位数记为 Cached。
+func DNS(ctx context.Context, names []string, resolvers []Resolver, opts DNSOptions) ([]types.DNSResult, error) {
+	if opts.Queries <= 0 {
+		opts.Queries = 5
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = 2 * time.Second
+	}
+
+	var results []types.DNSResult
+	for _, resolver := range resolvers {
+		for _, name := range names {
+			result := types.DNSResult{Name: name, Resolver: resolver.Name, Queries: opts.Queries}
+			var times, cached []time.Duration
+			for i := 0; i < opts.Queries; i++ {
+				elapsed, err := resolver.Query(ctx, name, opts.Timeout)
+				if ctx.Err() != nil {
+					return nil, ctx.Err()
+				}
+				if err != nil {
+					result.Failures++
+					result.Error = err.Error()
+					continue
+				}
+				times = append(times, elapsed)
+				if i == 0 {
+					result.Cold = millis(elapsed)
+				} else {
+					cached = append(cached, elapsed)
+				}
+			}
+			result.FailureRate = float64(result.Failures) / float64(result.Queries) * 100
+			result.Median = percentileMillis(times, 50)
+			result.P95 = percentileMillis(times, 95)
+			result.Cached = percentileMillis(cached, 50)
+			results = append(results, result)
+		}
+	}
+	return results, nil
+}
+
+// DNSResolution 按名称汇总所有解析器成功查询的中位数（毫秒），没有成功查询的名称不出现
+func DNSResolution(results []types.DNSResult) map[string]float64 {
+	medians := make(map[string][]float64)
+	for _, r := range results {
+		if r.Failures < r.Queries {
+			medians[r.Name] = append(medians[r.Name], r.Median)
+		}
+	}
+	resolution := make(map[string]float64, len(medians))
+	for name, values := range medians {
+		sort.Float64s(values)
+		resolution[name] = values[len(values)/2]
+	}
+	return resolution
+}
+
+// percentileMillis 按最近秩法计算耗时的百分位数（毫秒）：取第 ceil(p/100·n) 小的值，
+// 样本很少时 P95 也是最大值，而不是向下取到较小的样本
+func percentileMillis(durations []time.Duration, p float64) float64 {
+	if len(durations) == 0 {
+		return 0
+	}
+	sorted := append([]time.Duration(nil), durations...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	idx := int(math.Ceil(p/100*float64(len(sorted)))) - 1
+	return millis(sorted[min(max(idx, 0), len(sorted)-1)])
+}
+
+// millis 将耗时换算为毫秒
+func millis(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
@@ -0,0 +1,277 @@
+package netperf
+
+import (
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// dnsStub 是回环地址上同时监听 UDP 和 TCP 的 DNS 桩服务器，按查询的名称决定如何响应：
+// nxdomain.test 和 servfail.test 返回对应的响应码，mismatch.test 返回错误的 ID，
+// timeout.test 不响应，其他名称正常响应。
+type dnsStub struct {
+	udp net.PacketConn
+	tcp net.Listener
+	wg  sync.WaitGroup
+}
+
+func startDNSStub(t *testing.T) *dnsStub {
+	t.Helper()
+	udp, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// TCP 与 UDP 使用相同的端口，使同一个地址可以用两种方式查询
+	tcp, err := net.Listen("tcp", udp.LocalAddr().String())
+	if err != nil {
+		udp.Close()
+		t.Skipf("cannot listen on TCP %s: %v", udp.LocalAddr(), err)
+	}
+	s := &dnsStub{udp: udp, tcp: tcp}
+	s.wg.Add(2)
+	go s.serveUDP()
+	go s.serveTCP()
+	t.Cleanup(func() {
+		udp.Close()
+		tcp.Close()
+		s.wg.Wait()
+	})
+	return s
+}
+
+func (s *dnsStub) addr() string {
+	return s.udp.LocalAddr().String()
+}
+
+func (s *dnsStub) serveUDP() {
+	defer s.wg.Done()
+	buf := make([]byte, dnsMaxPacket)
+	for {
+		n, addr, err := s.udp.ReadFrom(buf)
+		if err != nil {
+			return
+		}
+		if response := dnsStubReply(buf[:n]); response != nil {
+			s.udp.WriteTo(response, addr)
+		}
+	}
+}
+
+func (s *dnsStub) serveTCP() {
+	defer s.wg.Done()
+	for {
+		conn, err := s.tcp.Accept()
+		if err != nil {
+			return
+		}
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			defer conn.Close()
+			conn.SetDeadline(time.Now().Add(5 * time.Second))
+			var length [2]byte
+			if _, err := io.ReadFull(conn, length[:]); err != nil {
+				return
+			}
+			query := make([]byte, binary.BigEndian.Uint16(length[:]))
+			if _, err := io.ReadFull(conn, query); err != nil {
+				return
+			}
+			response := dnsStubReply(query)
+			if response == nil {
+				// 不响应，等待客户端超时关闭连接
+				io.Copy(io.Discard, conn)
+				return
+			}
+			msg := binary.BigEndian.AppendUint16(nil, uint16(len(response)))
+			conn.Write(append(msg, response...))
+		}()
+	}
+}
+
+// dnsStubReply 根据查询的名称构造响应，不响应时返回 nil
+func dnsStubReply(query []byte) []byte {
+	if len(query) <= dnsHeader {
+		return nil
+	}
+	var labels []string
+	for i := dnsHeader; i < len(query) && query[i] != 0; i += int(query[i]) + 1 {
+		end := min(i+1+int(query[i]), len(query))
+		labels = append(labels, string(query[i+1:end]))
+	}
+
+	response := append([]byte(nil), query...)
+	rcode := 0
+	switch strings.Join(labels, ".") {
+	case "timeout.test":
+		return nil
+	case "mismatch.test":
+		binary.BigEndian.PutUint16(response[0:], binary.BigEndian.Uint16(query[0:])+1)
+	case "nxdomain.test":
+		rcode = 3
+	case "servfail.test":
+		rcode = 2
+	}
+	binary.BigEndian.PutUint16(response[2:], 0x8180|uint16(rcode)) // QR RD RA
+	return response
+}
+
+func TestResolverQuery(t *testing.T) {
+	stub := startDNSStub(t)
+	tests := []struct {
+		name string
+		// 为空表示查询成功。"timeout" 表示超时：连接的截止时间与 ctx 同时到期，
+		// 返回的可能是 context.DeadlineExceeded，也可能是连接的 i/o timeout
+		err string
+	}{
+		{"example.test", ""},
+		{"example.test.", ""},
+		{"nxdomain.test", "DNS NXDOMAIN"},
+		{"servfail.test", "DNS SERVFAIL"},
+		{"mismatch.test", "DNS response ID mismatch"},
+		{"timeout.test", "timeout"},
+	}
+	for _, transport := range []string{DNSUDP, DNSTCP} {
+		resolver, err := ParseResolver(transport + "://" + stub.addr())
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, tt := range tests {
+			t.Run(transport+"/"+tt.name, func(t *testing.T) {
+				elapsed, err := resolver.Query(context.Background(), tt.name, 200*time.Millisecond)
+				if tt.err == "" {
+					if err != nil || elapsed <= 0 {
+						t.Fatalf("Query = %v, %v; want a positive duration", elapsed, err)
+					}
+					return
+				}
+				if tt.err == "timeout" {
+					if !isTimeout(err) {
+						t.Fatalf("Query error = %v, want a timeout", err)
+					}
+					return
+				}
+				if err == nil || err.Error() != tt.err {
+					t.Fatalf("Query error = %v, want %q", err, tt.err)
+				}
+			})
+		}
+	}
+}
+
+func TestResolverQueryTimeout(t *testing.T) {
+	stub := startDNSStub(t)
+	resolver := Resolver{Name: "stub", Transport: DNSUDP, Address: stub.addr()}
+	start := time.Now()
+	_, err := resolver.Query(context.Background(), "timeout.test", 100*time.Millisecond)
+	if !isTimeout(err) {
+		t.Fatalf("Query error = %v, want a timeout", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Query took %v with a 100ms timeout", elapsed)
+	}
+}
+
+func TestDNS(t *testing.T) {
+	stub := startDNSStub(t)
+	resolvers := []Resolver{{Name: "stub", Transport: DNSUDP, Address: stub.addr()}}
+	results, err := DNS(context.Background(), []string{"example.test", "nxdomain.test"}, resolvers,
+		DNSOptions{Queries: 3, Timeout: 200 * time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("got %d results, want 2", len(results))
+	}
+	if ok := results[0]; ok.Failures != 0 || ok.Median <= 0 || ok.Cold <= 0 || ok.Cached <= 0 {
+		t.Errorf("example.test: %+v", ok)
+	}
+	if nx := results[1]; nx.Failures != 3 || nx.FailureRate != 100 || nx.Error != "DNS NXDOMAIN" {
+		t.Errorf("nxdomain.test: %+v", nx)
+	}
+
+	resolution := DNSResolution(results)
+	if _, ok := resolution["nxdomain.test"]; ok || resolution["example.test"] <= 0 {
+		t.Errorf("DNSResolution = %v", resolution)
+	}
+}
+
+func TestParseResolver(t *testing.T) {
+	tests := []struct {
+		in        string
+		transport string
+		address   string
+		err       bool
+	}{
+		{in: "system", transport: DNSSystem},
+		{in: "1.1.1.1", transport: DNSUDP, address: "1.1.1.1:53"},
+		{in: "dns.example", transport: DNSUDP, address: "dns.example:53"},
+		{in: "9.9.9.9:5353", transport: DNSUDP, address: "9.9.9.9:5353"},
+		{in: "[2606:4700:4700::1111]", transport: DNSUDP, address: "[2606:4700:4700::1111]:53"},
+		{in: "2606:4700:4700::1111", transport: DNSUDP, address: "[2606:4700:4700::1111]:53"},
+		{in: "tcp://[2001:db8::53]:5300", transport: DNSTCP, address: "[2001:db8::53]:5300"},
+		{in: "tcp://8.8.8.8", transport: DNSTCP, address: "8.8.8.8:53"},
+		{in: "tls://dns.google", transport: DNSTLS, address: "dns.google:853"},
+		{in: "tls://1.1.1.1:8853", transport: DNSTLS, address: "1.1.1.1:8853"},
+		{in: "https://dns.google/dns-query", transport: DNSHTTPS, address: "https://dns.google/dns-query"},
+		{in: "https:///dns-query", err: true},
+		{in: "https://", err: true},
+		{in: "udp://", err: true},
+		{in: "", err: true},
+		{in: "quic://dns.adguard.com", err: true},
+	}
+	for _, tt := range tests {
+		r, err := ParseResolver(tt.in)
+		if tt.err {
+			if err == nil {
+				t.Errorf("ParseResolver(%q) = %+v, want an error", tt.in, r)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseResolver(%q): %v", tt.in, err)
+			continue
+		}
+		if r.Name != tt.in || r.Transport != tt.transport || r.Address != tt.address {
+			t.Errorf("ParseResolver(%q) = %+v, want transport %q address %q", tt.in, r, tt.transport, tt.address)
+		}
+	}
+}
+
+func TestPercentileMillis(t *testing.T) {
+	ms := func(values ...int) []time.Duration {
+		durations := make([]time.Duration, len(values))
+		for i, v := range values {
+			durations[i] = time.Duration(v) * time.Millisecond
+		}
+		return durations
+	}
+	tests := []struct {
+		name      string
+		durations []time.Duration
+		p         float64
+		want      float64
+	}{
+		{"empty", nil, 95, 0},
+		{"one sample", ms(7), 95, 7},
+		// 最近秩法：样本少时 P95 为最大值，中位数取较小的一个
+		{"two samples p95", ms(30, 10), 95, 30},
+		{"two samples median", ms(30, 10), 50, 10},
+		{"three samples median", ms(3, 1, 2), 50, 2},
+		{"five samples p95", ms(5, 1, 4, 2, 3), 95, 5},
+		{"ten samples p90", ms(1, 2, 3, 4, 5, 6, 7, 8, 9, 10), 90, 9},
+		{"ten samples p95", ms(1, 2, 3, 4, 5, 6, 7, 8, 9, 10), 95, 10},
+		{"p0", ms(4, 2, 3), 0, 2},
+		{"p100", ms(4, 2, 3), 100, 4},
+	}
+	for _, tt := range tests {
+		if got := percentileMillis(tt.durations, tt.p); got != tt.want {
+			t.Errorf("%s: percentileMillis = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,210 @@
+package netperf
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptrace"
+	"octane/pkg/types"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// HTTPOptions 定义 HTTP 下载测试的参数
+type HTTPOptions struct {
+	Connections int           // 并行连接数，每个连接依次发出请求直到测试结束
+	Duration    time.Duration // 测试时长
+	RangeSize   int64         // 字节，大于 0 时每个请求用 Range 下载文件中的一段，否则每次下载整个文件
+}
+
+// httpStats 汇总所有连接的请求统计
+type httpStats struct {
+	bytes atomic.Int64
+	total atomic.Int64 // 文件大小，由第一个 206 响应的 Content-Range 得出
+
+	mu       sync.Mutex
+	requests int
+	reused   int
+	ttfb     []time.Duration
+	connect  []time.Duration
+}
+
+// countingWriter 统计写入的字节数并丢弃内容
+type countingWriter struct {
+	n *atomic.Int64
+}
+
+func (w countingWriter) Write(p []byte) (int, error) {
+	w.n.Add(int64(len(p)))
+	return len(p), nil
+}
+
+// HTTPDownload 用多个连接在 Duration 内反复下载 url 并丢弃内容，不写磁盘。
+// 结果中 Download 为总吞吐，Latency 为 TCP 连接耗时的中位数，TTFB 为首字节时间的中位数，
+// ConnectionReuse 为复用已有连接的请求比例。
+func HTTPDownload(ctx context.Context, url string, opts HTTPOptions) (*types.BandwidthResult, error) {
+	if opts.Connections <= 0 {
+		opts.Connections = 4
+	}
+	if opts.Duration <= 0 {
+		opts.Duration = 10 * time.Second
+	}
+
+	transport := &http.Transport{
+		Proxy:               http.ProxyFromEnvironment,
+		MaxIdleConnsPerHost: opts.Connections,
+		MaxConnsPerHost:     opts.Connections,
+		// 要求原样传输，避免压缩影响吞吐的计算
+		DisableCompression: true,
+	}
+	defer transport.CloseIdleConnections()
+	client := &http.Client{Transport: transport}
+
+	// 到时取消仍在进行的请求，已收到的数据计入吞吐
+	testCtx, cancel := context.WithTimeout(ctx, opts.Duration)
+	defer cancel()
+
+	// 一个连接出错时停止所有连接，只返回第一个错误
+	var failure error
+	var once sync.Once
+	stats := &httpStats{}
+	var chunk atomic.Int64
+	start := time.Now()
+	parallel(opts.Connections, func() error {
+		for testCtx.Err() == nil {
+			var offset int64 = -1
+			if opts.RangeSize > 0 {
+				offset = chunk.Add(1) - 1
+				if total := stats.total.Load(); total > 0 {
+					offset = offset * opts.RangeSize % total
+				} else {
+					offset *= opts.RangeSize
+				}
+			}
+			err := stats.get(testCtx, client, url, offset, opts.RangeSize)
+			if err != nil && testCtx.Err() == nil {
+				once.Do(func() {
+					failure = err
+					cancel()
+				})
+			}
+		}
+		return nil
+	})
+	elapsed := time.Since(start)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if failure != nil {
+		return nil, failure
+	}
+
+	stats.mu.Lock()
+	defer stats.mu.Unlock()
+	if stats.requests == 0 {
+		return nil, errors.New("no response within the test duration")
+	}
+	return &types.BandwidthResult{
+		Download:        mbps(stats.bytes.Load(), elapsed),
+		Latency:         percentileMillis(stats.connect, 50),
+		TTFB:            percentileMillis(stats.ttfb, 50),
+		Requests:        stats.requests,
+		ConnectionReuse: float64(stats.reused) / float64(stats.requests) * 100,
+	}, nil
+}
+
+// get 发出一个请求并丢弃响应体。offset 为负数时下载整个文件。
+func (s *httpStats) get(ctx context.Context, client *http.Client, url string, offset, size int64) error {
+	var start time.Time
+	var reused bool
+	var ttfb time.Duration
+	// 拨号在 Transport 的独立协程中进行，可能在请求改用其他空闲连接并返回之后才完成，
+	// 因此连接耗时需要加锁
+	var dial struct {
+		sync.Mutex
+		start   time.Time
+		connect time.Duration
+	}
+	trace := &httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) { reused = info.Reused },
+		ConnectStart: func(string, string) {
+			dial.Lock()
+			dial.start = time.Now()
+			dial.Unlock()
+		},
+		ConnectDone: func(_, _ string, err error) {
+			dial.Lock()
+			dial.connect = time.Since(dial.start)
+			dial.Unlock()
+		},
+		GotFirstResponseByte: func() { ttfb = time.Since(start) },
+	}
+
+	req, err := http.NewRequestWithContext(httptrace.WithClientTrace(ctx, trace), http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	if offset >= 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-%d", offset, offset+size-1))
+	}
+
+	start = time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusPartialContent:
+		if s.total.Load() == 0 {
+			s.total.Store(contentRangeTotal(resp.Header.Get("Content-Range")))
+		}
+	case http.StatusRequestedRangeNotSatisfiable:
+		// 得知文件大小前的偏移可能超出文件末尾，响应中带有文件大小，之后的偏移按大小回绕
+		total := contentRangeTotal(resp.Header.Get("Content-Range"))
+		if total == 0 {
+			return fmt.Errorf("unexpected status %s", resp.Status)
+		}
+		s.total.Store(total)
+		return nil
+	default:
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	dial.Lock()
+	connect := dial.connect
+	dial.Unlock()
+
+	s.mu.Lock()
+	s.requests++
+	if reused {
+		s.reused++
+	}
+	s.ttfb = append(s.ttfb, ttfb)
+	if connect > 0 {
+		s.connect = append(s.connect, connect)
+	}
+	s.mu.Unlock()
+
+	_, err = io.Copy(countingWriter{&s.bytes}, resp.Body)
+	return err
+}
+
+// contentRangeTotal 从 "bytes 0-99/1234" 中取出文件总大小，未知时返回 0
+func contentRangeTotal(header string) int64 {
+	_, total, ok := strings.Cut(header, "/")
+	if !ok {
+		return 0
+	}
+	n, err := strconv.ParseInt(total, 10, 64)
+	if err != nil {
+		return 0
+	}
+	return n
+}
@@ -0,0 +1,181 @@
+package netperf
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+const httpFileSize = 4096
+
+// rangeServer 提供 httpFileSize 字节的文件，记录每个请求的 Range 起点和响应状态
+type rangeServer struct {
+	// totalKnown 为 false 时 206 响应的 Content-Range 不带文件大小，只有 416 响应带有
+	totalKnown bool
+	// ignoreRange 为 true 时忽略 Range，总是以 200 返回整个文件
+	ignoreRange bool
+	// sizeOn416 为 false 时 416 响应不带文件大小
+	sizeOn416 bool
+
+	mu       sync.Mutex
+	starts   []int64
+	statuses map[int]int
+}
+
+func (s *rangeServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body := bytes.Repeat([]byte{'x'}, httpFileSize)
+	var first, last int64
+	_, err := fmt.Sscanf(r.Header.Get("Range"), "bytes=%d-%d", &first, &last)
+	status := http.StatusOK
+	switch {
+	case s.ignoreRange || err != nil:
+	case first >= httpFileSize:
+		status = http.StatusRequestedRangeNotSatisfiable
+		if s.sizeOn416 {
+			w.Header().Set("Content-Range", fmt.Sprintf("bytes */%d", httpFileSize))
+		}
+		body = nil
+	default:
+		status = http.StatusPartialContent
+		last = min(last, httpFileSize-1)
+		total := "*"
+		if s.totalKnown {
+			total = fmt.Sprint(httpFileSize)
+		}
+		w.Header().Set("Content-Range", fmt.Sprintf("bytes %d-%d/%s", first, last, total))
+		body = body[first : last+1]
+	}
+
+	s.mu.Lock()
+	if status != http.StatusOK {
+		s.starts = append(s.starts, first)
+	}
+	if s.statuses == nil {
+		s.statuses = make(map[int]int)
+	}
+	s.statuses[status]++
+	s.mu.Unlock()
+
+	w.Header().Set("Content-Length", fmt.Sprint(len(body)))
+	w.WriteHeader(status)
+	w.Write(body)
+}
+
+func TestHTTPDownload(t *testing.T) {
+	tests := []struct {
+		name     string
+		server   *rangeServer
+		statuses []int // 必须出现的状态
+		absent   []int // 不应出现的状态
+	}{
+		// 第一个 206 给出文件大小，之后的偏移回绕，不会请求超出末尾的范围
+		{"partial content wraps", &rangeServer{totalKnown: true, sizeOn416: true},
+			[]int{http.StatusPartialContent}, []int{http.StatusRequestedRangeNotSatisfiable}},
+		// 206 不带文件大小，超出末尾时由 416 给出大小，之后回绕
+		{"range not satisfiable carries size", &rangeServer{sizeOn416: true},
+			[]int{http.StatusPartialContent, http.StatusRequestedRangeNotSatisfiable}, nil},
+		// 服务器忽略 Range，每个请求都下载整个文件
+		{"server ignores range", &rangeServer{ignoreRange: true},
+			[]int{http.StatusOK}, []int{http.StatusPartialContent}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(tt.server)
+			defer server.Close()
+
+			result, err := HTTPDownload(context.Background(), server.URL, HTTPOptions{
+				Connections: 1,
+				Duration:    300 * time.Millisecond,
+				RangeSize:   1024,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result.Download <= 0 || result.Requests == 0 {
+				t.Errorf("expected throughput and requests, got %+v", result)
+			}
+
+			tt.server.mu.Lock()
+			defer tt.server.mu.Unlock()
+			for _, status := range tt.statuses {
+				if tt.server.statuses[status] == 0 {
+					t.Errorf("no %d responses, got %v", status, tt.server.statuses)
+				}
+			}
+			for _, status := range tt.absent {
+				if n := tt.server.statuses[status]; n != 0 {
+					t.Errorf("%d responses with status %d", n, status)
+				}
+			}
+			// 得知大小后偏移回绕，最多只有一个请求超出末尾
+			beyond := 0
+			for _, start := range tt.server.starts {
+				if start >= httpFileSize {
+					beyond++
+				}
+			}
+			if beyond > 1 {
+				t.Errorf("%d requests started beyond the end of the file", beyond)
+			}
+			// 文件只有 4 段，超过 4 个 206 说明偏移已回绕
+			if !tt.server.ignoreRange && tt.server.statuses[http.StatusPartialContent] <= httpFileSize/1024 {
+				t.Errorf("expected more than %d range requests to wrap, got %v", httpFileSize/1024, tt.server.statuses)
+			}
+		})
+	}
+}
+
+// 多个连接同时拨号时，连接耗时在 Transport 的协程中记录，用 -race 运行可以发现数据竞争
+func TestHTTPDownloadConnections(t *testing.T) {
+	server := httptest.NewServer(&rangeServer{totalKnown: true})
+	defer server.Close()
+
+	result, err := HTTPDownload(context.Background(), server.URL, HTTPOptions{
+		Connections: 4,
+		Duration:    300 * time.Millisecond,
+		RangeSize:   1024,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Latency <= 0 || result.TTFB <= 0 {
+		t.Errorf("expected connect time and TTFB, got %+v", result)
+	}
+	if result.ConnectionReuse <= 0 {
+		t.Errorf("expected requests to reuse connections, got %+v", result)
+	}
+}
+
+func TestHTTPDownloadRangeNotSatisfiableWithoutSize(t *testing.T) {
+	server := httptest.NewServer(&rangeServer{})
+	defer server.Close()
+
+	_, err := HTTPDownload(context.Background(), server.URL, HTTPOptions{
+		Connections: 1,
+		Duration:    5 * time.Second,
+		RangeSize:   1024,
+	})
+	if err == nil || !strings.Contains(err.Error(), "416") {
+		t.Fatalf("expected an unexpected status error for 416, got %v", err)
+	}
+}
+
+func TestContentRangeTotal(t *testing.T) {
+	tests := map[string]int64{
+		"bytes 0-99/1234": 1234,
+		"bytes */4096":    4096,
+		"bytes 0-99/*":    0,
+		"":                0,
+	}
+	for header, want := range tests {
+		if got := contentRangeTotal(header); got != want {
+			t.Errorf("contentRangeTotal(%q) = %d, want %d", header, got, want)
+		}
+	}
+}
@@ -0,0 +1,220 @@
+package netperf
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"octane/pkg/executor"
+	"octane/pkg/types"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Iperf3Port 是 iperf3 服务端的默认端口
+const Iperf3Port = 5201
+
+// 地区分组，对应 NetworkResults.Bandwidth 中的两个表
+const (
+	RegionDomestic      = "domestic"
+	RegionInternational = "international"
+)
+
+// Iperf3Server 是配置中的一个 iperf3 服务端
+type Iperf3Server struct {
+	Name   string `mapstructure:"name"`
+	Host   string `mapstructure:"host"`
+	Port   int    `mapstructure:"port"`   // 0 表示 5201
+	Region string `mapstructure:"region"` // domestic 或 international
+}
+
+// addr 返回服务端的 host:port
+func (s Iperf3Server) addr() (string, string) {
+	port := s.Port
+	if port == 0 {
+		port = Iperf3Port
+	}
+	return s.Host, strconv.Itoa(port)
+}
+
+// Iperf3 通过 iperf3 -J 测量到服务端的带宽
+type Iperf3 struct {
+	Path     string        // iperf3 可执行文件
+	Streams  int           // 并行流数
+	Duration time.Duration // 每个方向的时长
+	UDPRate  float64       // Mbps，UDP 目标速率，0 表示不测 UDP
+}
+
+// Iperf3Summary 是一次 iperf3 运行中用到的汇总数据
+type Iperf3Summary struct {
+	Protocol    string
+	Reverse     bool
+	Sent        float64 // bits/s，发送端
+	Received    float64 // bits/s，接收端
+	Retransmits int
+	MeanRTT     float64 // ms，各流发送端 TCP 平滑 RTT 的平均值，仅 Linux 上有
+	Jitter      float64 // ms，UDP
+	LostPercent float64 // %，UDP
+	HostCPU     float64 // %
+	RemoteCPU   float64 // %
+}
+
+// iperf3Sum 是 iperf3 JSON 中的汇总或单个流的结果
+type iperf3Sum struct {
+	BitsPerSecond float64 `json:"bits_per_second"`
+	Retransmits   int     `json:"retransmits"`
+	JitterMS      float64 `json:"jitter_ms"`
+	LostPercent   float64 `json:"lost_percent"`
+	Packets       int     `json:"packets"`
+	MeanRTT       float64 `json:"mean_rtt"` // µs
+}
+
+// iperf3Output 是 iperf3 -J 输出中用到的字段
+type iperf3Output struct {
+	Start struct {
+		TestStart struct {
+			Protocol string `json:"protocol"`
+			Reverse  int    `json:"reverse"`
+		} `json:"test_start"`
+	} `json:"start"`
+	End struct {
+		Streams []struct {
+			Sender *iperf3Sum `json:"sender"`
+		} `json:"streams"`
+		Sum         *iperf3Sum `json:"sum"` // UDP
+		SumSent     *iperf3Sum `json:"sum_sent"`
+		SumReceived *iperf3Sum `json:"sum_received"`
+		CPU         struct {
+			HostTotal   float64 `json:"host_total"`
+			RemoteTotal float64 `json:"remote_total"`
+		} `json:"cpu_utilization_percent"`
+	} `json:"end"`
+	Error string `json:"error"`
+}
+
+// ParseIperf3 解析 iperf3 -J 的输出。iperf3 出错时同样输出 JSON，错误信息在 error 字段中。
+func ParseIperf3(data []byte) (*Iperf3Summary, error) {
+	var out iperf3Output
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, fmt.Errorf("malformed iperf3 output: %v", err)
+	}
+	if out.Error != "" {
+		return nil, fmt.Errorf("iperf3: %s", out.Error)
+	}
+
+	end := out.End
+	summary := &Iperf3Summary{
+		Protocol:  out.Start.TestStart.Protocol,
+		Reverse:   out.Start.TestStart.Reverse != 0,
+		HostCPU:   end.CPU.HostTotal,
+		RemoteCPU: end.CPU.RemoteTotal,
+	}
+	if end.SumSent != nil {
+		summary.Sent = end.SumSent.BitsPerSecond
+		summary.Retransmits = end.SumSent.Retransmits
+	}
+	if end.SumReceived != nil {
+		summary.Received = end.SumReceived.BitsPerSecond
+	}
+
+	if summary.Protocol == "UDP" {
+		// iperf3 3.10 起 sum_received 带有接收端的抖动和丢包，旧版本只有 sum
+		udp := end.Sum
+		if end.SumReceived != nil && end.SumReceived.Packets > 0 {
+			udp = end.SumReceived
+		}
+		if udp == nil {
+			return nil, errors.New("iperf3: no UDP summary in output")
+		}
+		summary.Jitter = udp.JitterMS
+		summary.LostPercent = udp.LostPercent
+		if summary.Received == 0 {
+			summary.Received = udp.BitsPerSecond
+		}
+		return summary, nil
+	}
+
+	if end.SumSent == nil || end.SumReceived == nil {
+		return nil, errors.New("iperf3: no TCP summary in output")
+	}
+	var rtt float64
+	var streams int
+	for _, stream := range end.Streams {
+		if stream.Sender != nil && stream.Sender.MeanRTT > 0 {
+			rtt += stream.Sender.MeanRTT
+			streams++
+		}
+	}
+	if streams > 0 {
+		summary.MeanRTT = rtt / float64(streams) / 1000
+	}
+	return summary, nil
+}
+
+// run 执行一次 iperf3 并解析输出
+func (i *Iperf3) run(ctx context.Context, server Iperf3Server, extra ...string) (*Iperf3Summary, error) {
+	host, port := server.addr()
+	args := []string{"-J", "-c", host, "-p", port,
+		"-t", strconv.Itoa(max(1, int(i.Duration.Seconds()))),
+		"-P", strconv.Itoa(max(1, i.Streams))}
+	args = append(args, extra...)
+
+	// 服务端一次只接受一个测试，忙碌时稍后重试
+	for attempt := 1; ; attempt++ {
+		stdout, stderr, err := executor.RunCommand(ctx, executor.Command{Path: i.Path, Args: args})
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		summary, perr := ParseIperf3(stdout)
+		if perr == nil {
+			return summary, nil
+		}
+		if strings.Contains(perr.Error(), "busy") && attempt < 3 {
+			select {
+			case <-time.After(time.Duration(attempt) * 2 * time.Second):
+				continue
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}
+		if err != nil && len(stdout) == 0 {
+			return nil, fmt.Errorf("iperf3: %v: %s", err, strings.TrimSpace(string(stderr)))
+		}
+		return nil, perr
+	}
+}
+
+// Run 对服务端依次运行 TCP 上传、TCP 下载（-R）以及（设置了速率时）UDP 测试
+func (i *Iperf3) Run(ctx context.Context, server Iperf3Server) (*types.BandwidthResult, error) {
+	upload, err := i.run(ctx, server)
+	if err != nil {
+		return nil, fmt.Errorf("upload: %v", err)
+	}
+	download, err := i.run(ctx, server, "-R")
+	if err != nil {
+		return nil, fmt.Errorf("download: %v", err)
+	}
+
+	// 带宽以接收端实际收到的数据为准
+	result := &types.BandwidthResult{
+		Upload:      upload.Received / 1e6,
+		Download:    download.Received / 1e6,
+		Latency:     upload.MeanRTT,
+		Retransmits: upload.Retransmits + download.Retransmits,
+		LocalCPU:    max(upload.HostCPU, download.HostCPU),
+		RemoteCPU:   max(upload.RemoteCPU, download.RemoteCPU),
+	}
+
+	if i.UDPRate > 0 {
+		udp, err := i.run(ctx, server, "-u", "-b", strconv.FormatFloat(i.UDPRate, 'f', -1, 64)+"M")
+		if err != nil {
+			return nil, fmt.Errorf("udp: %v", err)
+		}
+		result.Jitter = udp.Jitter
+		result.PacketLoss = udp.LostPercent
+		result.LocalCPU = max(result.LocalCPU, udp.HostCPU)
+		result.RemoteCPU = max(result.RemoteCPU, udp.RemoteCPU)
+	}
+	return result, nil
+}
@@ -0,0 +1,134 @@
+package netperf
+
+import (
+	"context"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseIperf3(t *testing.T) {
+	tests := []struct {
+		file string
+		want Iperf3Summary
+	}{
+		// 两个流，MeanRTT 为各流发送端 mean_rtt 的平均值
+		{"tcp.json", Iperf3Summary{
+			Protocol: "TCP", Sent: 922731979.35, Received: 917102216.56, Retransmits: 19,
+			MeanRTT: 12.5, HostCPU: 14.683105, RemoteCPU: 9.372011,
+		}},
+		// -R 时发送端在服务器上，没有 mean_rtt
+		{"tcp-reverse.json", Iperf3Summary{
+			Protocol: "TCP", Reverse: true, Sent: 935152190.31, Received: 935111188.07, Retransmits: 3,
+			HostCPU: 22.418335, RemoteCPU: 6.127009,
+		}},
+		// 3.16 的 sum 为发送端统计，抖动和丢包取 sum_received
+		{"udp.json", Iperf3Summary{
+			Protocol: "UDP", Sent: 200003145.18, Received: 199381478.44,
+			Jitter: 0.048318, LostPercent: 0.189973, HostCPU: 31.940012, RemoteCPU: 12.710028,
+		}},
+		// 3.7 只有 sum
+		{"udp-3.7.json", Iperf3Summary{
+			Protocol: "UDP", Received: 50001339.84,
+			Jitter: 0.211046, LostPercent: 0.120471, HostCPU: 8.118412, RemoteCPU: 2.804197,
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			data, err := os.ReadFile(filepath.Join("testdata/iperf3", tt.file))
+			if err != nil {
+				t.Fatal(err)
+			}
+			summary, err := ParseIperf3(data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if *summary != tt.want {
+				t.Errorf("got  %+v\nwant %+v", *summary, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseIperf3Errors(t *testing.T) {
+	tests := []struct {
+		file string
+		err  string
+	}{
+		{"error.json", "iperf3: unable to connect to server"},
+		{"busy.json", "iperf3: the server is busy"},
+	}
+	for _, tt := range tests {
+		data, err := os.ReadFile(filepath.Join("testdata/iperf3", tt.file))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := ParseIperf3(data); err == nil || !strings.HasPrefix(err.Error(), tt.err) {
+			t.Errorf("%s: got %v, want an error starting with %q", tt.file, err, tt.err)
+		}
+	}
+
+	for _, data := range []string{"", "iperf3: error - unable to connect", `{"start": {"test_start": {"protocol": "TCP"}}, "end": {}}`} {
+		if _, err := ParseIperf3([]byte(data)); err == nil {
+			t.Errorf("ParseIperf3(%q): expected an error", data)
+		}
+	}
+}
+
+// fakeIperf3 返回一个按参数输出 testdata/iperf3 中记录的 iperf3 脚本：
+// 主机为 refused 时输出 error.json 并以 1 退出
+func fakeIperf3(t *testing.T) string {
+	t.Helper()
+	dir, err := filepath.Abs("testdata/iperf3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	script := `#!/bin/sh
+case " $* " in
+*" refused "*) cat ` + dir + `/error.json; exit 1 ;;
+*" -u "*) exec cat ` + dir + `/udp.json ;;
+*" -R "*) exec cat ` + dir + `/tcp-reverse.json ;;
+*) exec cat ` + dir + `/tcp.json ;;
+esac
+`
+	path := filepath.Join(t.TempDir(), "iperf3")
+	if err := os.WriteFile(path, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestIperf3Run(t *testing.T) {
+	iperf := &Iperf3{Path: fakeIperf3(t), Streams: 2, Duration: time.Second, UDPRate: 100}
+	result, err := iperf.Run(context.Background(), Iperf3Server{Name: "test", Host: "203.0.113.10"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	checks := []struct {
+		name      string
+		got, want float64
+	}{
+		{"upload", result.Upload, 917.10221656},
+		{"download", result.Download, 935.11118807},
+		{"latency", result.Latency, 12.5},
+		{"retransmits", float64(result.Retransmits), 22},
+		{"local CPU", result.LocalCPU, 31.940012},
+		{"remote CPU", result.RemoteCPU, 12.710028},
+		{"jitter", result.Jitter, 0.048318},
+		{"packet loss", result.PacketLoss, 0.189973},
+	}
+	for _, c := range checks {
+		if math.Abs(c.got-c.want) > 1e-6 {
+			t.Errorf("%s = %v, want %v", c.name, c.got, c.want)
+		}
+	}
+
+	_, err = iperf.Run(context.Background(), Iperf3Server{Name: "down", Host: "refused"})
+	if err == nil || !strings.HasPrefix(err.Error(), "upload: iperf3: unable to connect") {
+		t.Errorf("expected the error document from iperf3, got %v", err)
+	}
+}
@@ -0,0 +1,216 @@
+package netperf
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// 端口状态
+const (
+	PortOpen       = "open"       // 连接成功
+	PortClosed     = "closed"     // 对端拒绝连接（RST）
+	PortFiltered   = "filtered"   // 超时或网络不可达，通常被防火墙丢弃
+	PortUnresolved = "unresolved" // 主机名无法解析
+)
+
+// 端口扫描的默认值和上限。扫描只用于确认交付环境中少量已知端口的可达性，
+// 速率、并发数和目标数超过上限时按上限执行，避免一个配置值造成连接风暴。
+const (
+	DefaultScanRate        = 50 // 每秒连接数
+	MaxScanRate            = 500
+	DefaultScanConcurrency = 16
+	MaxScanConcurrency     = 64
+	DefaultScanTargets     = 1024 // 一次扫描最多检查的 host:port 数量
+	MaxScanTargets         = 4096
+)
+
+// ScanTarget 是一个主机和要检查的端口
+type ScanTarget struct {
+	Host  string `mapstructure:"host"`  // 主机名或 IP，不支持网段
+	Ports string `mapstructure:"ports"` // 如 "22,80,8000-8010"
+}
+
+// ScanOptions 定义端口扫描的参数
+type ScanOptions struct {
+	Concurrency int           // 同时进行的连接数，最多 MaxScanConcurrency
+	Rate        float64       // 每秒最多发起的连接数，0 表示 DefaultScanRate，最多 MaxScanRate
+	Timeout     time.Duration // 单个连接的超时，超时的端口视为 filtered
+	MaxTargets  int           // 一次最多检查的 host:port 数量，0 表示 DefaultScanTargets，最多 MaxScanTargets
+}
+
+// withDefaults 填充未设置的参数，并将速率、并发数和目标数限制在上限内
+func (o ScanOptions) withDefaults() ScanOptions {
+	if o.Concurrency <= 0 {
+		o.Concurrency = DefaultScanConcurrency
+	}
+	o.Concurrency = min(o.Concurrency, MaxScanConcurrency)
+	if o.Rate <= 0 {
+		o.Rate = DefaultScanRate
+	}
+	o.Rate = min(o.Rate, MaxScanRate)
+	if o.Timeout <= 0 {
+		o.Timeout = 2 * time.Second
+	}
+	if o.MaxTargets <= 0 {
+		o.MaxTargets = DefaultScanTargets
+	}
+	o.MaxTargets = min(o.MaxTargets, MaxScanTargets)
+	return o
+}
+
+// ParsePorts 解析逗号分隔的端口和端口范围，返回去重排序后的端口
+func ParsePorts(spec string) ([]int, error) {
+	seen := make(map[int]bool)
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		first, last, isRange := strings.Cut(part, "-")
+		low, err := parsePort(first)
+		if err != nil {
+			return nil, err
+		}
+		high := low
+		if isRange {
+			if high, err = parsePort(last); err != nil {
+				return nil, err
+			}
+			if high < low {
+				return nil, fmt.Errorf("invalid port range %q", part)
+			}
+		}
+		for port := low; port <= high; port++ {
+			seen[port] = true
+		}
+	}
+	if len(seen) == 0 {
+		return nil, fmt.Errorf("no ports in %q", spec)
+	}
+
+	ports := make([]int, 0, len(seen))
+	for port := range seen {
+		ports = append(ports, port)
+	}
+	sort.Ints(ports)
+	return ports, nil
+}
+
+// parsePort 解析单个端口号
+func parsePort(s string) (int, error) {
+	port, err := strconv.Atoi(strings.TrimSpace(s))
+	if err != nil || port < 1 || port > 65535 {
+		return 0, fmt.Errorf("invalid port %q", s)
+	}
+	return port, nil
+}
+
+// ScanAddresses 展开扫描目标为 host:port 列表，超过 limit 个时返回错误
+func ScanAddresses(targets []ScanTarget, limit int) ([]string, error) {
+	var addresses []string
+	seen := make(map[string]bool)
+	for _, target := range targets {
+		host := strings.Trim(target.Host, "[]")
+		if host == "" {
+			return nil, errors.New("scan target has no host")
+		}
+		if strings.Contains(host, "/") {
+			return nil, fmt.Errorf("scan target %s: networks are not supported, list hosts individually", host)
+		}
+		ports, err := ParsePorts(target.Ports)
+		if err != nil {
+			return nil, fmt.Errorf("scan target %s: %v", host, err)
+		}
+		for _, port := range ports {
+			address := net.JoinHostPort(host, strconv.Itoa(port))
+			if seen[address] {
+				continue
+			}
+			seen[address] = true
+			addresses = append(addresses, address)
+		}
+		if len(addresses) > limit {
+			return nil, fmt.Errorf("port scan exceeds the limit of %d host:port targets", limit)
+		}
+	}
+	return addresses, nil
+}
+
+// Scan 对每个 host:port 发起 TCP 连接并分类，结果以 host:port 为键
+func Scan(ctx context.Context, targets []ScanTarget, opts ScanOptions) (map[string]string, error) {
+	opts = opts.withDefaults()
+	addresses, err := ScanAddresses(targets, opts.MaxTargets)
+	if err != nil {
+		return nil, err
+	}
+
+	// 按速率依次放出地址，工作协程并发连接
+	jobs := make(chan string)
+	go func() {
+		defer close(jobs)
+		ticker := time.NewTicker(time.Duration(float64(time.Second) / opts.Rate))
+		defer ticker.Stop()
+		for i, address := range addresses {
+			if i > 0 {
+				select {
+				case <-ticker.C:
+				case <-ctx.Done():
+					return
+				}
+			}
+			select {
+			case jobs <- address:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	results := make(map[string]string, len(addresses))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for i := 0; i < min(opts.Concurrency, len(addresses)); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for address := range jobs {
+				state := probe(ctx, address, opts.Timeout)
+				mu.Lock()
+				results[address] = state
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// probe 连接一个端口并按结果分类
+func probe(ctx context.Context, address string, timeout time.Duration) string {
+	dialer := net.Dialer{Timeout: timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err == nil {
+		conn.Close()
+		return PortOpen
+	}
+	var dnsErr *net.DNSError
+	switch {
+	case errors.Is(err, syscall.ECONNREFUSED):
+		return PortClosed
+	case errors.As(err, &dnsErr):
+		return PortUnresolved
+	}
+	return PortFiltered
+}
@@ -0,0 +1,104 @@
+package netperf
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestScanLoopback(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			conn.Close()
+		}
+	}()
+
+	// 取一个空闲端口后关闭，连接会被拒绝
+	closed, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	closedPort := closed.Addr().(*net.TCPAddr).Port
+	closed.Close()
+
+	openPort := listener.Addr().(*net.TCPAddr).Port
+	targets := []ScanTarget{{Host: "127.0.0.1", Ports: strconv.Itoa(openPort) + "," + strconv.Itoa(closedPort)}}
+	results, err := Scan(context.Background(), targets, ScanOptions{Timeout: time.Second})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]string{
+		net.JoinHostPort("127.0.0.1", strconv.Itoa(openPort)):   PortOpen,
+		net.JoinHostPort("127.0.0.1", strconv.Itoa(closedPort)): PortClosed,
+	}
+	if len(results) != len(want) {
+		t.Fatalf("got %v, want %v", results, want)
+	}
+	for address, state := range want {
+		if results[address] != state {
+			t.Errorf("%s is %q, want %q", address, results[address], state)
+		}
+	}
+}
+
+func TestScanOptionsLimits(t *testing.T) {
+	tests := []struct {
+		name string
+		in   ScanOptions
+		want ScanOptions
+	}{
+		{"defaults", ScanOptions{}, ScanOptions{Concurrency: DefaultScanConcurrency, Rate: DefaultScanRate, Timeout: 2 * time.Second, MaxTargets: DefaultScanTargets}},
+		{"negative rate", ScanOptions{Concurrency: 4, Rate: -1, Timeout: time.Second, MaxTargets: 10}, ScanOptions{Concurrency: 4, Rate: DefaultScanRate, Timeout: time.Second, MaxTargets: 10}},
+		{"above the caps", ScanOptions{Concurrency: 10000, Rate: 1e6, Timeout: time.Second, MaxTargets: 10}, ScanOptions{Concurrency: MaxScanConcurrency, Rate: MaxScanRate, Timeout: time.Second, MaxTargets: 10}},
+		{"too many targets", ScanOptions{Concurrency: 4, Rate: 10, Timeout: time.Second, MaxTargets: 65535 * 256}, ScanOptions{Concurrency: 4, Rate: 10, Timeout: time.Second, MaxTargets: MaxScanTargets}},
+		{"at the target cap", ScanOptions{Concurrency: 4, Rate: 10, Timeout: time.Second, MaxTargets: MaxScanTargets}, ScanOptions{Concurrency: 4, Rate: 10, Timeout: time.Second, MaxTargets: MaxScanTargets}},
+	}
+	for _, tt := range tests {
+		if got := tt.in.withDefaults(); got != tt.want {
+			t.Errorf("%s: got %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestScanAddresses(t *testing.T) {
+	tests := []struct {
+		name    string
+		targets []ScanTarget
+		limit   int
+		want    int
+		err     string
+	}{
+		{"ranges and duplicates", []ScanTarget{{Host: "db", Ports: "22, 80,8000-8002,80"}, {Host: "db", Ports: "22"}}, 10, 5, ""},
+		{"ipv6 host", []ScanTarget{{Host: "[::1]", Ports: "443"}}, 10, 1, ""},
+		{"over the limit", []ScanTarget{{Host: "db", Ports: "1-11"}}, 10, 0, "exceeds the limit of 10"},
+		{"network", []ScanTarget{{Host: "10.0.0.0/24", Ports: "22"}}, 10, 0, "networks are not supported"},
+		{"reversed range", []ScanTarget{{Host: "db", Ports: "90-80"}}, 10, 0, "invalid port range"},
+		{"port zero", []ScanTarget{{Host: "db", Ports: "0"}}, 10, 0, "invalid port"},
+		{"no host", []ScanTarget{{Ports: "22"}}, 10, 0, "no host"},
+	}
+	for _, tt := range tests {
+		addresses, err := ScanAddresses(tt.targets, tt.limit)
+		if tt.err != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("%s: error %v, want %q", tt.name, err, tt.err)
+			}
+			continue
+		}
+		if err != nil || len(addresses) != tt.want {
+			t.Errorf("%s: got %v, %v, want %d addresses", tt.name, addresses, err, tt.want)
+		}
+	}
+}
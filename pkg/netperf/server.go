@@ -0,0 +1,202 @@
+package netperf
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultPort 是对端服务默认监听的 TCP/UDP 端口
+const DefaultPort = 5210
+
+// 协议：每个 TCP 连接以一行 "OCTANE <version> <mode> [arg]" 开头
+const (
+	protocolMagic   = "OCTANE"
+	protocolVersion = "1"
+
+	modeDownload = "download" // 服务端持续发送 arg 毫秒
+	modeUpload   = "upload"   // 客户端发送至半关闭，服务端回复收到的字节数
+	modeEcho     = "echo"     // 服务端逐行回显，用于测量往返延迟
+)
+
+// 单个连接的限制，防止异常客户端长期占用服务端
+const (
+	headerTimeout = 10 * time.Second
+	maxDuration   = 5 * time.Minute
+	bufferSize    = 128 << 10
+)
+
+// Server 是网络测试的对端，在同一端口上处理 TCP 吞吐、回显和 UDP 回显
+type Server struct {
+	tcp net.Listener
+	udp net.PacketConn
+
+	wg sync.WaitGroup
+}
+
+// Listen 在 addr 上监听 TCP 和 UDP。端口为 0 时 UDP 使用系统为 TCP 分配的端口。
+func Listen(addr string) (*Server, error) {
+	tcp, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+
+	host, _, _ := net.SplitHostPort(addr)
+	port := tcp.Addr().(*net.TCPAddr).Port
+	udp, err := net.ListenPacket("udp", net.JoinHostPort(host, strconv.Itoa(port)))
+	if err != nil {
+		tcp.Close()
+		return nil, err
+	}
+	udp.(*net.UDPConn).SetReadBuffer(udpBuffer)
+	return &Server{tcp: tcp, udp: udp}, nil
+}
+
+// Addr 返回服务端监听的地址
+func (s *Server) Addr() string {
+	return s.tcp.Addr().String()
+}
+
+// Serve 处理连接直到上下文取消，返回前关闭监听和所有连接
+func (s *Server) Serve(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stop := context.AfterFunc(ctx, func() { s.Close() })
+	defer stop()
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		s.serveUDP()
+	}()
+
+	var err error
+	for {
+		var conn net.Conn
+		conn, err = s.tcp.Accept()
+		if err != nil {
+			break
+		}
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			defer conn.Close()
+			// 服务停止时中断仍在进行的测试
+			stop := context.AfterFunc(ctx, func() { conn.Close() })
+			defer stop()
+			if err := handle(conn); err != nil {
+				log.Printf("netperf: %s: %v", conn.RemoteAddr(), err)
+			}
+		}()
+	}
+
+	cancel()
+	s.wg.Wait()
+	if ctx.Err() != nil || errors.Is(err, net.ErrClosed) {
+		return nil
+	}
+	return err
+}
+
+// Close 关闭 TCP 和 UDP 监听
+func (s *Server) Close() error {
+	err := s.tcp.Close()
+	if uerr := s.udp.Close(); err == nil {
+		err = uerr
+	}
+	return err
+}
+
+// handle 读取连接头部并按模式处理
+func handle(conn net.Conn) error {
+	conn.SetReadDeadline(time.Now().Add(headerTimeout))
+	reader := bufio.NewReader(conn)
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return fmt.Errorf("reading header: %v", err)
+	}
+	conn.SetReadDeadline(time.Time{})
+
+	fields := strings.Fields(line)
+	if len(fields) < 3 || fields[0] != protocolMagic {
+		return fmt.Errorf("invalid header %q", strings.TrimSpace(line))
+	}
+	if fields[1] != protocolVersion {
+		return fmt.Errorf("unsupported protocol version %s", fields[1])
+	}
+
+	switch fields[2] {
+	case modeDownload:
+		if len(fields) < 4 {
+			return errors.New("download without duration")
+		}
+		ms, err := strconv.Atoi(fields[3])
+		if err != nil || ms <= 0 {
+			return fmt.Errorf("invalid download duration %q", fields[3])
+		}
+		return send(conn, min(time.Duration(ms)*time.Millisecond, maxDuration))
+	case modeUpload:
+		conn.SetReadDeadline(time.Now().Add(maxDuration))
+		n, err := io.Copy(io.Discard, reader)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(conn, "%d\n", n)
+		return err
+	case modeEcho:
+		conn.SetDeadline(time.Now().Add(maxDuration))
+		for {
+			line, err := reader.ReadBytes('\n')
+			if err != nil {
+				if err == io.EOF {
+					return nil
+				}
+				return err
+			}
+			if _, err := conn.Write(line); err != nil {
+				return err
+			}
+		}
+	}
+	return fmt.Errorf("unknown mode %q", fields[2])
+}
+
+// send 在 d 时间内持续写入数据，客户端提前断开不算错误
+func send(conn net.Conn, d time.Duration) error {
+	buf := make([]byte, bufferSize)
+	deadline := time.Now().Add(d)
+	conn.SetWriteDeadline(deadline)
+	for time.Now().Before(deadline) {
+		if _, err := conn.Write(buf); err != nil {
+			return nil
+		}
+	}
+	return nil
+}
+
+// serveUDP 回显每个 UDP 测试包的头部，直到监听关闭
+func (s *Server) serveUDP() {
+	buf := make([]byte, 64<<10)
+	for {
+		n, addr, err := s.udp.ReadFrom(buf)
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return
+			}
+			continue
+		}
+		if n < packetHeader || string(buf[:4]) != udpMagic {
+			continue
+		}
+		s.udp.WriteTo(buf[:packetHeader], addr)
+	}
+}
@@ -0,0 +1,222 @@
+package netperf
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptrace"
+	"net/url"
+	"octane/pkg/types"
+	"sync"
+	"time"
+)
+
+// serviceBodyLimit 是 HTTP 检查最多读取的响应体大小，总耗时计到读完为止
+const serviceBodyLimit = 1 << 20
+
+// Service 是一个需要检查可达性的服务，URL 和 Address 二选一
+type Service struct {
+	Name         string `mapstructure:"name"`
+	URL          string `mapstructure:"url"`           // HTTP(S) 地址
+	ExpectStatus int    `mapstructure:"expect_status"` // 期望的 HTTP 状态码，0 表示任何小于 400 的状态
+	Address      string `mapstructure:"address"`       // TCP host:port
+	TLS          bool   `mapstructure:"tls"`           // TCP 连接后进行 TLS 握手
+	Insecure     bool   `mapstructure:"insecure"`      // 不校验服务端证书
+}
+
+// Validate 检查服务的名称和地址
+func (s Service) Validate() error {
+	if s.Name == "" {
+		return errors.New("service has no name")
+	}
+	switch {
+	case s.URL != "" && s.Address != "":
+		return fmt.Errorf("service %s: set either url or address, not both", s.Name)
+	case s.URL != "":
+		u, err := url.Parse(s.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("service %s: invalid URL %q", s.Name, s.URL)
+		}
+	case s.Address != "":
+		if _, _, err := net.SplitHostPort(s.Address); err != nil {
+			return fmt.Errorf("service %s: invalid address %q (want host:port)", s.Name, s.Address)
+		}
+	default:
+		return fmt.Errorf("service %s has no url or address", s.Name)
+	}
+	return nil
+}
+
+// target 返回结果中记录的服务地址
+func (s Service) target() string {
+	if s.URL != "" {
+		return s.URL
+	}
+	return s.Address
+}
+
+// CheckServices 依次检查每个服务，单个服务的检查最长 timeout
+func CheckServices(ctx context.Context, services []Service, timeout time.Duration) ([]types.ServiceResult, error) {
+	if timeout <= 0 {
+		timeout = 5 * time.Second
+	}
+	results := make([]types.ServiceResult, 0, len(services))
+	for _, service := range services {
+		result := CheckService(ctx, service, timeout)
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// CheckService 检查一个服务，记录 TCP 连接、TLS 握手、首字节和总耗时
+func CheckService(ctx context.Context, service Service, timeout time.Duration) types.ServiceResult {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	result := types.ServiceResult{Name: service.Name, Target: service.target()}
+	var err error
+	if service.URL != "" {
+		err = checkHTTP(ctx, service, &result)
+	} else {
+		err = checkTCP(ctx, service, &result)
+	}
+	if err != nil {
+		result.Error = ctxErr(ctx, err).Error()
+		return result
+	}
+	result.Reachable = true
+	return result
+}
+
+// checkTCP 连接服务，需要时完成 TLS 握手
+func checkTCP(ctx context.Context, service Service, result *types.ServiceResult) error {
+	start := time.Now()
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", service.Address)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	result.Connect = millis(time.Since(start))
+
+	if service.TLS {
+		host, _, _ := net.SplitHostPort(service.Address)
+		handshake := time.Now()
+		client := tls.Client(conn, &tls.Config{ServerName: host, InsecureSkipVerify: service.Insecure})
+		if err := client.HandshakeContext(ctx); err != nil {
+			return err
+		}
+		result.TLS = millis(time.Since(handshake))
+	}
+	result.Total = millis(time.Since(start))
+	return nil
+}
+
+// checkHTTP 请求服务的 URL 并检查状态码，不跟随重定向
+func checkHTTP(ctx context.Context, service Service, result *types.ServiceResult) error {
+	// 双栈主机会并发拨号多个地址，回调可能在不同 goroutine 中执行，
+	// 也可能在 Do 返回后才结束，因此加锁记录，只取实际使用的连接的耗时
+	var start time.Time
+	var dial struct {
+		sync.Mutex
+		starts   map[string]time.Time
+		connects map[string]time.Duration
+		used     string
+		tlsStart time.Time
+		tls      time.Duration
+		ttfb     time.Duration
+	}
+	dial.starts = make(map[string]time.Time)
+	dial.connects = make(map[string]time.Duration)
+	trace := &httptrace.ClientTrace{
+		ConnectStart: func(_, addr string) {
+			dial.Lock()
+			dial.starts[addr] = time.Now()
+			dial.Unlock()
+		},
+		ConnectDone: func(_, addr string, err error) {
+			dial.Lock()
+			if err == nil {
+				dial.connects[addr] = time.Since(dial.starts[addr])
+			}
+			dial.Unlock()
+		},
+		TLSHandshakeStart: func() {
+			dial.Lock()
+			dial.tlsStart = time.Now()
+			dial.Unlock()
+		},
+		TLSHandshakeDone: func(_ tls.ConnectionState, err error) {
+			dial.Lock()
+			if err == nil {
+				dial.tls = time.Since(dial.tlsStart)
+			}
+			dial.Unlock()
+		},
+		GotConn: func(info httptrace.GotConnInfo) {
+			dial.Lock()
+			dial.used = info.Conn.RemoteAddr().String()
+			dial.Unlock()
+		},
+		GotFirstResponseByte: func() {
+			dial.Lock()
+			dial.ttfb = time.Since(start)
+			dial.Unlock()
+		},
+	}
+
+	req, err := http.NewRequestWithContext(httptrace.WithClientTrace(ctx, trace), http.MethodGet, service.URL, nil)
+	if err != nil {
+		return err
+	}
+	// 每次检查使用新的连接，使连接和握手耗时可比
+	client := &http.Client{
+		Transport: &http.Transport{
+			Proxy:             http.ProxyFromEnvironment,
+			DisableKeepAlives: true,
+			TLSClientConfig:   &tls.Config{InsecureSkipVerify: service.Insecure},
+		},
+		CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+	}
+
+	start = time.Now()
+	resp, err := client.Do(req)
+	dial.Lock()
+	result.Connect = millis(dial.connects[dial.used])
+	result.TLS = millis(dial.tls)
+	result.TTFB = millis(dial.ttfb)
+	dial.Unlock()
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if _, err := io.Copy(io.Discard, io.LimitReader(resp.Body, serviceBodyLimit)); err != nil {
+		return err
+	}
+	result.Total = millis(time.Since(start))
+	result.Status = resp.StatusCode
+
+	if service.ExpectStatus != 0 && resp.StatusCode != service.ExpectStatus {
+		return fmt.Errorf("status %d, expected %d", resp.StatusCode, service.ExpectStatus)
+	}
+	if service.ExpectStatus == 0 && resp.StatusCode >= 400 {
+		return fmt.Errorf("status %s", resp.Status)
+	}
+	return nil
+}
+
+// ServiceAccessibility 按服务名汇总可达性
+func ServiceAccessibility(results []types.ServiceResult) map[string]bool {
+	accessibility := make(map[string]bool, len(results))
+	for _, r := range results {
+		accessibility[r.Name] = r.Reachable
+	}
+	return accessibility
+}
@@ -0,0 +1,144 @@
+package netperf
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// statusHandler 按路径返回状态码：/204、/302（重定向到 /followed）、/404，其余返回 200
+func statusHandler(followed *bool) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/204", func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusNoContent) })
+	mux.HandleFunc("/302", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/followed", http.StatusFound)
+	})
+	mux.HandleFunc("/404", http.NotFound)
+	mux.HandleFunc("/followed", func(w http.ResponseWriter, r *http.Request) { *followed = true })
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) { w.Write([]byte("ok")) })
+	return mux
+}
+
+func TestCheckServiceHTTP(t *testing.T) {
+	var followed bool
+	server := httptest.NewServer(statusHandler(&followed))
+	defer server.Close()
+
+	tests := []struct {
+		name      string
+		path      string
+		expect    int
+		reachable bool
+		status    int
+		err       string
+	}{
+		{"ok", "/", 0, true, 200, ""},
+		// 默认任何小于 400 的状态都算可达
+		{"no content", "/204", 0, true, 204, ""},
+		{"not found", "/404", 0, false, 404, "status 404 Not Found"},
+		{"expected status", "/204", 204, true, 204, ""},
+		{"status mismatch", "/204", 200, false, 204, "status 204, expected 200"},
+		// 不跟随重定向，记录 302 本身
+		{"redirect", "/302", 0, true, 302, ""},
+		{"redirect mismatch", "/302", 200, false, 302, "status 302, expected 200"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := Service{Name: tt.name, URL: server.URL + tt.path, ExpectStatus: tt.expect}
+			result := CheckService(context.Background(), service, 5*time.Second)
+			if result.Reachable != tt.reachable || result.Status != tt.status || result.Error != tt.err {
+				t.Errorf("got reachable=%v status=%d error=%q, want %v %d %q",
+					result.Reachable, result.Status, result.Error, tt.reachable, tt.status, tt.err)
+			}
+			if result.Target != service.URL {
+				t.Errorf("target = %q, want %q", result.Target, service.URL)
+			}
+			if result.Connect <= 0 || result.TTFB <= 0 || result.Total < result.TTFB {
+				t.Errorf("timings not recorded: %+v", result)
+			}
+			if result.TLS != 0 {
+				t.Errorf("TLS = %v for plain HTTP", result.TLS)
+			}
+		})
+	}
+	if followed {
+		t.Error("the redirect was followed")
+	}
+}
+
+func TestCheckServiceTLS(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	address := server.Listener.Addr().String()
+
+	// 测试服务器的证书是自签名的，校验失败
+	tests := []struct {
+		name    string
+		service Service
+		ok      bool
+	}{
+		{"https", Service{URL: server.URL}, false},
+		{"https insecure", Service{URL: server.URL, Insecure: true}, true},
+		{"tcp tls", Service{Address: address, TLS: true}, false},
+		{"tcp tls insecure", Service{Address: address, TLS: true, Insecure: true}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.service.Name = tt.name
+			result := CheckService(context.Background(), tt.service, 5*time.Second)
+			if result.Reachable != tt.ok {
+				t.Fatalf("reachable = %v, want %v (error %q)", result.Reachable, tt.ok, result.Error)
+			}
+			if !tt.ok {
+				if !strings.Contains(result.Error, "certificate") {
+					t.Errorf("error = %q, want a certificate error", result.Error)
+				}
+				return
+			}
+			if result.Connect <= 0 || result.TLS <= 0 {
+				t.Errorf("connect or TLS timing not recorded: %+v", result)
+			}
+		})
+	}
+}
+
+func TestCheckServiceTCP(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	address := server.Listener.Addr().String()
+
+	// 只建立 TCP 连接，不关心服务器是否说 HTTP
+	result := CheckService(context.Background(), Service{Name: "tcp", Address: address}, 5*time.Second)
+	if !result.Reachable || result.Connect <= 0 || result.TLS != 0 || result.Status != 0 {
+		t.Errorf("got %+v, want a reachable TCP service without TLS", result)
+	}
+
+	server.Close()
+	result = CheckService(context.Background(), Service{Name: "tcp", Address: address}, 5*time.Second)
+	if result.Reachable || result.Error == "" {
+		t.Errorf("got %+v after the server closed, want an error", result)
+	}
+}
+
+func TestCheckServiceTimeout(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	start := time.Now()
+	result := CheckService(context.Background(), Service{Name: "slow", URL: server.URL}, 100*time.Millisecond)
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("check took %v with a 100ms timeout", elapsed)
+	}
+	if result.Reachable || result.Error != context.DeadlineExceeded.Error() {
+		t.Errorf("got reachable=%v error=%q, want %q", result.Reachable, result.Error, context.DeadlineExceeded)
+	}
+}
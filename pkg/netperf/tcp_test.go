@@ -0,0 +1,82 @@
+package netperf
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+)
+
+// startPeer 在回环地址上启动对端服务，测试结束时停止
+func startPeer(t *testing.T) string {
+	t.Helper()
+	server, err := Listen("127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- server.Serve(ctx) }()
+	t.Cleanup(func() {
+		cancel()
+		if err := <-done; err != nil {
+			t.Errorf("Serve: %v", err)
+		}
+	})
+	return server.Addr()
+}
+
+func TestRunLoopback(t *testing.T) {
+	peer := startPeer(t)
+	result, err := Run(context.Background(), Options{
+		Peer:       peer,
+		Streams:    2,
+		Duration:   300 * time.Millisecond,
+		Pings:      5,
+		UDPRate:    1,
+		PacketSize: 200,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Latency <= 0 || result.Download <= 0 || result.Upload <= 0 {
+		t.Errorf("expected latency and throughput in both directions, got %+v", result)
+	}
+	if result.PacketLoss != 0 {
+		t.Errorf("packet loss %.2f%% over loopback", result.PacketLoss)
+	}
+}
+
+func TestPeerRejectsInvalidHeader(t *testing.T) {
+	peer := startPeer(t)
+	for _, header := range []string{"HELLO 1 echo", "OCTANE 2 echo", "OCTANE 1 download", "OCTANE 1 sideways"} {
+		conn, err := net.DialTimeout("tcp", peer, time.Second)
+		if err != nil {
+			t.Fatal(err)
+		}
+		fmt.Fprintf(conn, "%s\n", header)
+		conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+		// 服务端记录错误后关闭连接，不回复任何数据
+		if line, err := bufio.NewReader(conn).ReadString('\n'); err == nil {
+			t.Errorf("%q: unexpected reply %q", header, line)
+		}
+		conn.Close()
+	}
+}
+
+func TestPeerAddr(t *testing.T) {
+	tests := map[string]string{
+		"10.0.0.2":      "10.0.0.2:5210",
+		"10.0.0.2:6000": "10.0.0.2:6000",
+		"peer.example":  "peer.example:5210",
+		"[fd00::2]":     "[fd00::2]:5210",
+		"fd00::2":       "[fd00::2]:5210",
+	}
+	for in, want := range tests {
+		if got := PeerAddr(in); got != want {
+			t.Errorf("PeerAddr(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,169 @@
+package netperf
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"sort"
+	"sync"
+	"time"
+)
+
+// UDP 测试包头部：4 字节标识、4 字节序号、8 字节发送时刻（相对测试开始的纳秒数）
+const (
+	udpMagic     = "OCTU"
+	packetHeader = 16
+)
+
+// udpGrace 是发送结束后等待迟到回显的时间
+const udpGrace = time.Second
+
+// udpBuffer 是 UDP 套接字的接收缓冲区大小
+const udpBuffer = 4 << 20
+
+// UDPResult 是 UDP 回显测试的结果
+type UDPResult struct {
+	Sent     int
+	Received int
+	Latency  float64 // ms，往返时间中位数
+	Jitter   float64 // ms，相邻包往返时间差的平均绝对值
+	Loss     float64 // %，往返丢包率
+}
+
+// UDP 以 rate Mbps 向对端发送 size 字节的包，持续 d 时间，根据回显计算抖动和丢包
+func UDP(ctx context.Context, peer string, rate float64, size int, d time.Duration) (*UDPResult, error) {
+	if rate <= 0 {
+		return nil, errors.New("udp rate must be positive")
+	}
+	if size < packetHeader {
+		return nil, fmt.Errorf("packet size must be at least %d bytes", packetHeader)
+	}
+	// 发送间隔按纳秒取整，速率过高时为 0
+	interval := time.Duration(float64(size*8) / (rate * 1e6) * float64(time.Second))
+	if interval <= 0 {
+		return nil, fmt.Errorf("udp rate %g Mbps is too high for %d-byte packets", rate, size)
+	}
+
+	dialer := net.Dialer{Timeout: dialTimeout}
+	conn, err := dialer.DialContext(ctx, "udp", peer)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+	// 较大的接收缓冲区避免按毫秒补发的突发回显在本端被丢弃
+	conn.(*net.UDPConn).SetReadBuffer(udpBuffer)
+
+	start := time.Now()
+	var mu sync.Mutex
+	rtts := make(map[uint32]time.Duration)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		buf := make([]byte, packetHeader)
+		for {
+			n, err := conn.Read(buf)
+			if err != nil {
+				// 发送结束后的读超时或连接关闭表示接收结束；端口不可达等错误忽略，按丢包计
+				if errors.Is(err, net.ErrClosed) || isTimeout(err) {
+					return
+				}
+				continue
+			}
+			if n < packetHeader || string(buf[:4]) != udpMagic {
+				continue
+			}
+			seq := binary.BigEndian.Uint32(buf[4:])
+			sent := time.Duration(binary.BigEndian.Uint64(buf[8:]))
+			mu.Lock()
+			if _, dup := rtts[seq]; !dup {
+				rtts[seq] = time.Since(start) - sent
+			}
+			mu.Unlock()
+		}
+	}()
+
+	sent := sendPaced(ctx, conn, start, interval, size, d)
+	conn.SetReadDeadline(time.Now().Add(udpGrace))
+	<-done
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if sent == 0 {
+		return nil, errors.New("no packets sent")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	return udpStats(sent, rtts), nil
+}
+
+// sendPaced 每隔 interval 发送一个测试包，返回发送的包数
+func sendPaced(ctx context.Context, conn net.Conn, start time.Time, interval time.Duration, size int, d time.Duration) int {
+	packet := make([]byte, size)
+	copy(packet, udpMagic)
+
+	// 每毫秒补发到按速率应发送的包数，避免过细的定时器
+	ticker := time.NewTicker(time.Millisecond)
+	defer ticker.Stop()
+
+	sent := 0
+	for {
+		elapsed := time.Since(start)
+		if elapsed >= d {
+			return sent
+		}
+		due := int(elapsed/interval) + 1
+		for ; sent < due; sent++ {
+			binary.BigEndian.PutUint32(packet[4:], uint32(sent))
+			binary.BigEndian.PutUint64(packet[8:], uint64(time.Since(start)))
+			conn.Write(packet)
+		}
+
+		select {
+		case <-ctx.Done():
+			return sent
+		case <-ticker.C:
+		}
+	}
+}
+
+// udpStats 按序号顺序计算往返时间的中位数、抖动和丢包率
+func udpStats(sent int, rtts map[uint32]time.Duration) *UDPResult {
+	result := &UDPResult{Sent: sent, Received: len(rtts)}
+	result.Loss = float64(sent-len(rtts)) / float64(sent) * 100
+
+	ordered := make([]float64, 0, len(rtts))
+	for seq := uint32(0); seq < uint32(sent); seq++ {
+		if rtt, ok := rtts[seq]; ok {
+			ordered = append(ordered, float64(rtt)/float64(time.Millisecond))
+		}
+	}
+	if len(ordered) == 0 {
+		return result
+	}
+
+	var diff float64
+	for i := 1; i < len(ordered); i++ {
+		diff += math.Abs(ordered[i] - ordered[i-1])
+	}
+	if len(ordered) > 1 {
+		result.Jitter = diff / float64(len(ordered)-1)
+	}
+
+	sorted := append([]float64(nil), ordered...)
+	sort.Float64s(sorted)
+	result.Latency = sorted[len(sorted)/2]
+	return result
+}
+
+// isTimeout 判断错误是否为读写超时
+func isTimeout(err error) bool {
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
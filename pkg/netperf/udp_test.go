@@ -0,0 +1,106 @@
+package netperf
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"net"
+	"testing"
+	"time"
+)
+
+// 测试对端丢弃序号为 4n+3 的包，并把奇数序号的往返时间加长 udpDelay，使抖动约为 udpDelay
+const udpDelay = 2 * time.Millisecond
+
+// lossyPeer 启动一个有丢包和抖动的 UDP 回显对端
+func lossyPeer(t *testing.T) string {
+	t.Helper()
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	go func() {
+		buf := make([]byte, 64<<10)
+		for {
+			n, addr, err := conn.ReadFrom(buf)
+			if err != nil {
+				if errors.Is(err, net.ErrClosed) {
+					return
+				}
+				continue
+			}
+			if n < packetHeader {
+				continue
+			}
+			seq := binary.BigEndian.Uint32(buf[4:])
+			if seq%4 == 3 {
+				continue
+			}
+			if seq%2 == 1 {
+				// 提前发送时刻等同于延迟回显，不需要在回显循环中等待
+				sent := binary.BigEndian.Uint64(buf[8:])
+				binary.BigEndian.PutUint64(buf[8:], sent-uint64(udpDelay))
+			}
+			conn.WriteTo(buf[:packetHeader], addr)
+		}
+	}()
+	return conn.LocalAddr().String()
+}
+
+func TestUDPLossAndJitter(t *testing.T) {
+	result, err := UDP(context.Background(), lossyPeer(t), 1, 200, 300*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Sent < 8 {
+		t.Fatalf("only %d packets sent", result.Sent)
+	}
+
+	dropped := result.Sent / 4
+	if result.Received != result.Sent-dropped {
+		t.Errorf("received %d of %d, want %d", result.Received, result.Sent, result.Sent-dropped)
+	}
+	if want := float64(dropped) / float64(result.Sent) * 100; result.Loss != want {
+		t.Errorf("loss %.2f%%, want %.2f%%", result.Loss, want)
+	}
+	// 收到的包按序号交替为正常和加长的往返时间
+	if ms := float64(udpDelay) / float64(time.Millisecond); result.Jitter < ms*0.5 || result.Jitter > ms*1.5 {
+		t.Errorf("jitter %.3f ms, want about %.0f ms", result.Jitter, ms)
+	}
+}
+
+func TestUDPRejectsInvalidRate(t *testing.T) {
+	tests := []struct {
+		name string
+		rate float64
+		size int
+	}{
+		{"zero rate", 0, 1200},
+		{"negative rate", -5, 1200},
+		// 发送间隔不足 1 纳秒，之前会在计算应发送的包数时除以 0
+		{"rate too high", 1e12, 16},
+		{"packet too small", 10, packetHeader - 1},
+	}
+	for _, tt := range tests {
+		if _, err := UDP(context.Background(), "127.0.0.1:9", tt.rate, tt.size, time.Second); err == nil {
+			t.Errorf("%s: expected an error", tt.name)
+		}
+	}
+}
+
+func TestUDPStats(t *testing.T) {
+	ms := time.Millisecond
+	// 序号 2 丢失，往返时间依次为 1、3、2 毫秒
+	result := udpStats(4, map[uint32]time.Duration{0: ms, 1: 3 * ms, 3: 2 * ms})
+	if result.Sent != 4 || result.Received != 3 || result.Loss != 25 {
+		t.Errorf("sent %d received %d loss %.1f%%", result.Sent, result.Received, result.Loss)
+	}
+	if result.Jitter != 1.5 {
+		t.Errorf("jitter %.3f ms, want 1.5", result.Jitter)
+	}
+	if result.Latency != 2 {
+		t.Errorf("latency %.3f ms, want 2", result.Latency)
+	}
+}
@@ -7,28 +7,97 @@ import (
 
 // OctaneCalculator is responsible for calculating the octane rating.
 type OctaneCalculator struct {
-	BaselineDB map[string]BaselineData // Baseline database
+	BaselineDB    map[string]BaselineData // Baseline database
+	RegionWeights map[string]float64      // Network score weight of each region group
+}
+
+// DefaultRegionWeights weights domestic bandwidth above international bandwidth
+var DefaultRegionWeights = map[string]float64{
+	"domestic":      0.7,
+	"international": 0.3,
+}
+
+// regionLatencyLimits is the latency above which a target is penalized, in ms
+var regionLatencyLimits = map[string]float64{
+	"domestic":      50,
+	"international": 200,
 }
 
 // NewOctaneCalculator creates a new instance of OctaneCalculator
 func NewOctaneCalculator() *OctaneCalculator {
 	return &OctaneCalculator{
-		BaselineDB: BaselineDB,
+		BaselineDB:    BaselineDB,
+		RegionWeights: DefaultRegionWeights,
 	}
 }
 
+// componentWeights is the share of each component in the overall rating
+var componentWeights = map[string]float64{
+	"cpu":     0.20,
+	"memory":  0.15,
+	"storage": 0.15,
+	"gpu":     0.25,
+	"network": 0.15,
+}
+
 // CalculateOctane calculates the octane rating based on test results.
 func (oc *OctaneCalculator) CalculateOctane(results *types.TestResults) *types.OctaneRating {
-	cpuOctane := oc.calculateCPUOctane(results.CPU)
-	memoryOctane := oc.calculateMemoryOctane(results.Memory)
-	storageOctane := oc.calculateStorageOctane(results.Storage)
-	gpuOctane := oc.calculateGPUOctane(results.GPU)
-	networkOctane := oc.calculateNetworkOctane(results.Network)
+	components := make([]string, 0, len(componentWeights))
+	for name := range componentWeights {
+		components = append(components, name)
+	}
+	return oc.CalculateOctaneFor(results, components)
+}
+
+// CalculateOctaneFor calculates the octane rating from the named components only,
+// e.g. the stages that completed; the weights of the others are spread over them.
+func (oc *OctaneCalculator) CalculateOctaneFor(results *types.TestResults, components []string) *types.OctaneRating {
+	scores := map[string]func() float64{
+		"cpu":     func() float64 { return oc.calculateCPUOctane(results.CPU) },
+		"memory":  func() float64 { return oc.calculateMemoryOctane(results.Memory) },
+		"storage": func() float64 { return oc.calculateStorageOctane(results.Storage) },
+		"gpu":     func() float64 { return oc.calculateGPUOctane(results.GPU) },
+		"network": func() float64 { return oc.calculateNetworkOctane(results.Network) },
+	}
 
-	// 权重计算 - 根据 Octane 品牌理念调整权重
-	overall := (cpuOctane*0.20 + memoryOctane*0.15 +
-		storageOctane*0.15 + gpuOctane*0.25 +
-		networkOctane*0.15)
+	// 权重计算 - 根据 Octane 品牌理念调整权重，缺少的组件按比例分给其余组件
+	var total, included, overall float64
+	for _, w := range componentWeights {
+		total += w
+	}
+	for _, name := range components {
+		if score, ok := scores[name]; ok {
+			included += componentWeights[name]
+			overall += score() * componentWeights[name]
+			delete(scores, name)
+		}
+	}
+	if included > 0 {
+		overall *= total / included
+	}
+
+	// 插件按配置的权重分走一部分总分：base*(1-Σw) + Σ(w·ron)，只计入能评分的插件
+	var weight, weighted float64
+	for _, plugin := range results.Plugins {
+		ron, ok := oc.calculatePluginOctane(plugin)
+		if !ok || plugin.Weight <= 0 {
+			continue
+		}
+		weight += plugin.Weight
+		weighted += ron * plugin.Weight
+	}
+	if weight > 1 {
+		// 加载时已拒绝总和超过 1 的权重，这里只防御旧的结果文件
+		weighted /= weight
+		weight = 1
+	}
+	switch {
+	case included > 0:
+		overall = overall*(1-weight) + weighted
+	case weight > 0:
+		// 没有内置组件时只按插件评分
+		overall = weighted / weight
+	}
 
 	return &types.OctaneRating{
 		RON:         overall,
@@ -40,7 +109,7 @@ func (oc *OctaneCalculator) CalculateOctane(results *types.TestResults) *types.O
 
 // CalculateComponentOctanes calculates octane ratings for individual components
 func (oc *OctaneCalculator) CalculateComponentOctanes(results *types.TestResults) map[string]types.OctaneRating {
-	return map[string]types.OctaneRating{
+	components := map[string]types.OctaneRating{
 		"cpu": {
 			RON:         oc.calculateCPUOctane(results.CPU),
 			Grade:       oc.getGradeFromRON(oc.calculateCPUOctane(results.CPU)),
@@ -72,6 +141,20 @@ func (oc *OctaneCalculator) CalculateComponentOctanes(results *types.TestResults
 			Color:       oc.getColorFromRON(oc.calculateNetworkOctane(results.Network)),
 		},
 	}
+
+	for _, plugin := range results.Plugins {
+		ron, ok := oc.calculatePluginOctane(plugin)
+		if !ok {
+			continue
+		}
+		components["plugin/"+plugin.Name] = types.OctaneRating{
+			RON:         ron,
+			Grade:       oc.getGradeFromRON(ron),
+			Description: oc.getDescriptionFromRON(ron),
+			Color:       oc.getColorFromRON(ron),
+		}
+	}
+	return components
 }
 
 // calculateCPUOctane calculates CPU octane rating based on performance results
@@ -189,26 +272,40 @@ func (oc *OctaneCalculator) calculateGPUOctane(results types.GPUResults) float64
 func (oc *OctaneCalculator) calculateNetworkOctane(results types.NetworkResults) float64 {
 	baseline := oc.BaselineDB["default"].Network
 
-	// 国内带宽评分
-	domesticAvg := 0.0
-	domesticCount := 0
-	for _, result := range results.Bandwidth.Domestic {
-		domesticAvg += result.Download
-		domesticCount++
+	regions := map[string]map[string]types.BandwidthResult{
+		"domestic":      results.Bandwidth.Domestic,
+		"international": results.Bandwidth.International,
 	}
-	if domesticCount > 0 {
-		domesticAvg /= float64(domesticCount)
-	}
-
-	bandwidthOctane := 70 + 30*math.Log10(domesticAvg/baseline)
 
-	// 延迟评分
+	// 各地区分组按平均下载带宽评分，按权重合并；权重只在有结果的分组之间归一化
+	bandwidthOctane := 0.0
 	latencyScore := 100.0
-	for _, result := range results.Bandwidth.Domestic {
-		if result.Latency > 50 { // 50ms以上延迟惩罚
-			latencyScore -= 10
+	totalWeight := 0.0
+	for region, group := range regions {
+		weight := oc.RegionWeights[region]
+		if len(group) == 0 || weight <= 0 {
+			continue
+		}
+		avg := 0.0
+		for _, result := range group {
+			avg += result.Download
+			// 延迟超过地区上限时惩罚
+			if result.Latency > regionLatencyLimits[region] {
+				latencyScore -= 10
+			}
 		}
+		avg /= float64(len(group))
+
+		bandwidthOctane += weight * (70 + 30*math.Log10(avg/baseline))
+		totalWeight += weight
+	}
+	if totalWeight > 0 {
+		bandwidthOctane /= totalWeight
+	} else {
+		// 没有带宽结果时网络评分为最低分
+		bandwidthOctane = math.Inf(-1)
 	}
+
 	latencyOctane := 70 + 30*(latencyScore/100)
 
 	// 连通性评分
@@ -220,13 +317,43 @@ func (oc *OctaneCalculator) calculateNetworkOctane(results types.NetworkResults)
 		}
 	}
 
-	connectivityOctane := 70 + 30*float64(connectivityScore)/float64(totalServices)
+	// 没有配置服务检查时连通性按最低分计，避免 0/0 得到 NaN
+	connectivityOctane := 70.0
+	if totalServices > 0 {
+		connectivityOctane += 30 * float64(connectivityScore) / float64(totalServices)
+	}
 
 	overall := bandwidthOctane*0.5 + latencyOctane*0.3 + connectivityOctane*0.2
 
 	return math.Min(100, math.Max(70, overall))
 }
 
+// calculatePluginOctane calculates a plugin octane rating from metrics that declare a baseline.
+// It returns false when the plugin failed or has no scorable metrics.
+func (oc *OctaneCalculator) calculatePluginOctane(results types.PluginResults) (float64, bool) {
+	if results.Error != "" {
+		return 0, false
+	}
+
+	total, count := 0.0, 0
+	for _, metric := range results.Metrics {
+		if metric.Baseline <= 0 || metric.Value <= 0 {
+			continue
+		}
+		ratio := metric.Value / metric.Baseline
+		if !metric.HigherIsBetter {
+			ratio = metric.Baseline / metric.Value
+		}
+		total += 70 + 30*math.Log10(ratio)
+		count++
+	}
+	if count == 0 {
+		return 0, false
+	}
+
+	return math.Min(100, math.Max(70, total/float64(count))), true
+}
+
 // getGradeFromRON maps the RON to a grade.
 func (oc *OctaneCalculator) getGradeFromRON(ron float64) string {
 	switch {
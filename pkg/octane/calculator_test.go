@@ -0,0 +1,120 @@
+package octane
+
+import (
+	"math"
+	"octane/pkg/types"
+	"testing"
+)
+
+func TestCalculateNetworkOctaneServices(t *testing.T) {
+	tests := []struct {
+		name     string
+		services map[string]bool
+		want     float64
+	}{
+		// 没有服务检查时连通性按最低分 70 计，而不是 0/0
+		{"no services", nil, 79},
+		{"empty services", map[string]bool{}, 79},
+		{"all accessible", map[string]bool{"github": true, "pypi": true}, 85},
+		{"half accessible", map[string]bool{"github": true, "pypi": false}, 82},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// 下载带宽等于基准值时带宽为 70，延迟未超限时为 100
+			var results types.NetworkResults
+			results.Bandwidth.Domestic = map[string]types.BandwidthResult{
+				"local": {Download: BaselineDB["default"].Network, Latency: 5},
+			}
+			results.Connectivity.ServiceAccessibility = tt.services
+
+			got := NewOctaneCalculator().calculateNetworkOctane(results)
+			if math.IsNaN(got) || math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("calculateNetworkOctane = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCalculateOctaneWithoutServices(t *testing.T) {
+	var results types.TestResults
+	results.Network.Bandwidth.Domestic = map[string]types.BandwidthResult{
+		"local": {Download: 1000, Latency: 5},
+	}
+
+	calculator := NewOctaneCalculator()
+	if rating := calculator.CalculateOctane(&results); math.IsNaN(rating.RON) || rating.Grade == "" {
+		t.Errorf("overall rating = %+v", rating)
+	}
+	if network := calculator.CalculateComponentOctanes(&results)["network"]; math.IsNaN(network.RON) || network.Grade == "" {
+		t.Errorf("network rating = %+v", network)
+	}
+}
+
+func TestCalculateOctanePluginWeights(t *testing.T) {
+	var results types.TestResults
+	calculator := NewOctaneCalculator()
+	base := calculator.CalculateOctane(&results).RON
+
+	// 基准值的 10 倍得 100 分，等于基准值得 70 分
+	metric := func(value float64) []types.PluginMetric {
+		return []types.PluginMetric{{Name: "ops", Value: value, Baseline: 100, HigherIsBetter: true}}
+	}
+	results.Plugins = []types.PluginResults{
+		{Name: "fast", Weight: 0.2, Metrics: metric(1000)},
+		{Name: "slow", Weight: 0.3, Metrics: metric(100)},
+		// 失败的插件和没有基准值的插件不参与评分，它们的权重也不从总分中扣除
+		{Name: "failed", Weight: 0.4, Error: "exit status 1"},
+		{Name: "unscored", Weight: 0.1, Metrics: []types.PluginMetric{{Name: "ops", Value: 5}}},
+		{Name: "unweighted", Metrics: metric(1000)},
+	}
+
+	want := base*0.5 + 100*0.2 + 70*0.3
+	if got := calculator.CalculateOctane(&results).RON; math.Abs(got-want) > 1e-9 {
+		t.Errorf("RON = %v, want %v", got, want)
+	}
+
+	// 插件权重总和超过 1 时按比例分配，不再计入内置组件
+	results.Plugins = []types.PluginResults{
+		{Name: "fast", Weight: 0.9, Metrics: metric(1000)},
+		{Name: "slow", Weight: 0.9, Metrics: metric(100)},
+	}
+	if got := calculator.CalculateOctane(&results).RON; math.Abs(got-85) > 1e-9 {
+		t.Errorf("RON = %v, want 85", got)
+	}
+}
+
+func TestCalculateNetworkOctaneRegionWeights(t *testing.T) {
+	baseline := BaselineDB["default"].Network
+	// 带宽为基准值的 10 倍得 100 分，等于基准值得 70 分；延迟未超限，没有服务检查
+	fast := map[string]types.BandwidthResult{"fast": {Download: baseline * 10, Latency: 5}}
+	slow := map[string]types.BandwidthResult{"slow": {Download: baseline, Latency: 5}}
+	network := func(bandwidth float64) float64 { return bandwidth*0.5 + 100*0.3 + 70*0.2 }
+
+	tests := []struct {
+		name           string
+		domestic, intl map[string]types.BandwidthResult
+		weights        map[string]float64
+		want           float64
+	}{
+		{"both regions", fast, slow, DefaultRegionWeights, network(100*0.7 + 70*0.3)},
+		// 只有一个地区有结果时，该地区的权重归一化为 1
+		{"domestic only", fast, nil, DefaultRegionWeights, network(100)},
+		{"international only", nil, slow, DefaultRegionWeights, network(70)},
+		{"custom weights", fast, slow, map[string]float64{"domestic": 1, "international": 3}, network(100*0.25 + 70*0.75)},
+		// 权重为 0 的地区不参与评分
+		{"zero weight", fast, slow, map[string]float64{"domestic": 0, "international": 1}, network(70)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var results types.NetworkResults
+			results.Bandwidth.Domestic = tt.domestic
+			results.Bandwidth.International = tt.intl
+
+			calculator := NewOctaneCalculator()
+			calculator.RegionWeights = tt.weights
+			if got := calculator.calculateNetworkOctane(results); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("calculateNetworkOctane = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,133 @@
+package octane
+
+import (
+	"fmt"
+	"octane/pkg/types"
+	"sort"
+)
+
+// Thresholds above which a disk is considered unhealthy enough to distrust its benchmark results.
+const (
+	wornOutPercentageUsed = 90
+	hotDiskTemperature    = 70 // °C
+)
+
+// StorageHealthWarnings returns a warning for every disk whose SMART data suggests it is failing.
+// Devices without SMART data are skipped.
+func StorageHealthWarnings(devices []types.StorageInfo) []string {
+	var warnings []string
+	for _, device := range devices {
+		if device.SmartStatus == "" {
+			continue
+		}
+		if device.SmartStatus == "failed" {
+			warnings = append(warnings, fmt.Sprintf("%s: SMART overall health check failed", device.Name))
+		}
+		if device.PercentageUsed >= wornOutPercentageUsed {
+			warnings = append(warnings, fmt.Sprintf("%s: %d%% of rated endurance used", device.Name, device.PercentageUsed))
+		}
+		if device.MediaErrors > 0 {
+			warnings = append(warnings, fmt.Sprintf("%s: %d media errors", device.Name, device.MediaErrors))
+		}
+		if device.ReallocatedSectors > 0 {
+			warnings = append(warnings, fmt.Sprintf("%s: %d reallocated sectors", device.Name, device.ReallocatedSectors))
+		}
+		if device.Temperature >= hotDiskTemperature {
+			warnings = append(warnings, fmt.Sprintf("%s: running hot at %d°C", device.Name, device.Temperature))
+		}
+	}
+	return warnings
+}
+
+// LinkRecommendations returns a recommendation for every wired interface that negotiated
+// less than its expected speed (Mbps) or half duplex. expected applies to every ethernet
+// and InfiniBand interface; overrides sets the speed per interface name. Interfaces named
+// in overrides are also expected to be up, and to exist. A zero expected speed disables the check.
+func LinkRecommendations(interfaces []types.NetworkInfo, expected int, overrides map[string]int) []types.Recommendation {
+	var recommendations []types.Recommendation
+	present := make(map[string]bool, len(interfaces))
+	for _, iface := range interfaces {
+		present[iface.Name] = true
+	}
+	var missing []string
+	for name, want := range overrides {
+		if !present[name] && want > 0 {
+			missing = append(missing, name)
+		}
+	}
+	sort.Strings(missing)
+	for _, name := range missing {
+		recommendations = append(recommendations, types.Recommendation{
+			Category:   "network",
+			Suggestion: fmt.Sprintf("%s is missing but a %d Mbps link is expected: check the interface name and that its driver is loaded", name, overrides[name]),
+			Impact:     "high",
+		})
+	}
+
+	for _, iface := range interfaces {
+		want, named := overrides[iface.Name]
+		if !named {
+			if iface.Type != "ethernet" && iface.Type != "infiniband" {
+				continue
+			}
+			want = expected
+		}
+		if want <= 0 {
+			continue
+		}
+
+		if iface.Status != "up" {
+			if named {
+				recommendations = append(recommendations, types.Recommendation{
+					Category:   "network",
+					Suggestion: fmt.Sprintf("%s is %s but a %d Mbps link is expected: check the cable and switch port", iface.Name, linkStatus(iface.Status), want),
+					Impact:     "high",
+				})
+			}
+			continue
+		}
+		if iface.Speed > 0 && iface.Speed < want {
+			recommendations = append(recommendations, types.Recommendation{
+				Category:   "network",
+				Suggestion: fmt.Sprintf("%s linked at %d Mbps but %d Mbps is expected: check the cable, transceiver and switch port configuration", iface.Name, iface.Speed, want),
+				Impact:     "high",
+			})
+		}
+		if iface.Duplex == "half" {
+			recommendations = append(recommendations, types.Recommendation{
+				Category:   "network",
+				Suggestion: fmt.Sprintf("%s negotiated half duplex: check autonegotiation on both ends", iface.Name),
+				Impact:     "medium",
+			})
+		}
+	}
+	return recommendations
+}
+
+// linkStatus describes an operstate for a recommendation
+func linkStatus(status string) string {
+	if status == "" {
+		return "not reporting a link state"
+	}
+	return status
+}
+
+// GPULinkRecommendations returns a recommendation for every GPU whose PCIe link is narrower
+// than the card supports, e.g. an x16 card in an x4 slot. The link speed is not compared
+// because GPUs lower it while idle.
+func GPULinkRecommendations(gpus []types.GPUInfo) []types.Recommendation {
+	var recommendations []types.Recommendation
+	for _, gpu := range gpus {
+		link := gpu.PCIe
+		if link.Width == 0 || link.MaxWidth == 0 || link.Width >= link.MaxWidth {
+			continue
+		}
+		recommendations = append(recommendations, types.Recommendation{
+			Category: "gpu",
+			Suggestion: fmt.Sprintf("GPU %d (%s, %s) runs at PCIe x%d but supports x%d: move it to a full-width slot or check the slot's lane configuration in the BIOS",
+				gpu.Index, gpu.Name, gpu.PCIBus, link.Width, link.MaxWidth),
+			Impact: "high",
+		})
+	}
+	return recommendations
+}
@@ -0,0 +1,54 @@
+package octane
+
+import (
+	"octane/pkg/types"
+	"strings"
+	"testing"
+)
+
+func TestLinkRecommendations(t *testing.T) {
+	interfaces := []types.NetworkInfo{
+		// 10G 网卡只协商到 1G
+		{Name: "eth0", Type: "ethernet", Speed: 1000, Duplex: "full", Status: "up"},
+		{Name: "eth1", Type: "ethernet", Speed: 10000, Duplex: "full", Status: "up"},
+		{Name: "eth2", Type: "ethernet", Speed: 100, Duplex: "half", Status: "up"},
+		// 未在 overrides 中列出的接口未连接时不提示
+		{Name: "eth3", Type: "ethernet", Status: "down"},
+		{Name: "ens1f0", Type: "ethernet", Status: "down"},
+		{Name: "eth0.100", Type: "virtual", Speed: 1000, Status: "up"},
+		{Name: "veth1", Type: "virtual", Status: "down"},
+		{Name: "wlan0", Type: "wireless", Speed: 300, Status: "up"},
+		{Name: "ib0", Type: "infiniband", Speed: 100000, Status: "up"},
+	}
+	overrides := map[string]int{
+		"eth0":     10000,
+		"ens1f0":   25000,
+		"eth0.100": 10000,
+		"eth9":     10000, // 不存在的接口
+		"eth8":     0,     // 0 表示不检查，不存在也不提示
+	}
+
+	recommendations := LinkRecommendations(interfaces, 1000, overrides)
+	want := []string{
+		"eth9 is missing but a 10000 Mbps link is expected",
+		"eth0 linked at 1000 Mbps but 10000 Mbps is expected",
+		"eth2 linked at 100 Mbps but 1000 Mbps is expected",
+		"eth2 negotiated half duplex",
+		"ens1f0 is down but a 25000 Mbps link is expected",
+		"eth0.100 linked at 1000 Mbps but 10000 Mbps is expected",
+	}
+	if len(recommendations) != len(want) {
+		t.Fatalf("got %d recommendations, want %d: %+v", len(recommendations), len(want), recommendations)
+	}
+	for i, prefix := range want {
+		r := recommendations[i]
+		if !strings.HasPrefix(r.Suggestion, prefix) || r.Category != "network" {
+			t.Errorf("recommendation %d = %+v, want %q", i, r, prefix)
+		}
+	}
+
+	// expected 为 0 且没有 overrides 时不检查
+	if got := LinkRecommendations(interfaces, 0, nil); len(got) != 0 {
+		t.Errorf("without expected speeds: %+v", got)
+	}
+}
@@ -0,0 +1,127 @@
+package plugin
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ManifestFile 是插件目录中清单文件的名称
+const ManifestFile = "plugin.yaml"
+
+// defaultTimeout 是清单未指定超时时的默认值
+const defaultTimeout = 10 * time.Minute
+
+// MetricDefinition 定义插件输出的一项指标
+type MetricDefinition struct {
+	Name           string  `yaml:"name"`
+	Unit           string  `yaml:"unit"`
+	HigherIsBetter bool    `yaml:"higher_is_better"`
+	Baseline       float64 `yaml:"baseline"` // 评分基准值，0 表示该指标不参与评分
+}
+
+// Manifest 定义插件清单 plugin.yaml 的结构
+type Manifest struct {
+	Name        string             `yaml:"name"`
+	Description string             `yaml:"description"`
+	Category    string             `yaml:"category"`
+	Command     string             `yaml:"command"`
+	Args        []string           `yaml:"args"`
+	Timeout     string             `yaml:"timeout"`
+	Duration    string             `yaml:"duration"` // 建议运行时长，通过 OCTANE_DURATION 传给插件
+	Weight      float64            `yaml:"weight"`   // 在总评分中的占比，0 表示不计分
+	Metrics     []MetricDefinition `yaml:"metrics"`
+
+	dir string // 清单所在目录
+}
+
+// LoadManifest 读取并校验插件清单
+func LoadManifest(path string) (*Manifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var m Manifest
+	if err := yaml.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("invalid manifest %s: %v", path, err)
+	}
+	m.dir = filepath.Dir(path)
+
+	if err := m.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid manifest %s: %v", path, err)
+	}
+	return &m, nil
+}
+
+// Validate 检查清单字段是否完整
+func (m *Manifest) Validate() error {
+	if m.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+	if m.Command == "" {
+		return fmt.Errorf("command is required")
+	}
+	if len(m.Metrics) == 0 {
+		return fmt.Errorf("at least one metric is required")
+	}
+	if _, err := m.timeout(); err != nil {
+		return err
+	}
+	if _, err := m.duration(); err != nil {
+		return err
+	}
+	if m.Weight < 0 || m.Weight > 1 {
+		return fmt.Errorf("weight must be between 0 and 1")
+	}
+
+	seen := make(map[string]bool)
+	for _, metric := range m.Metrics {
+		if metric.Name == "" {
+			return fmt.Errorf("metric name is required")
+		}
+		if seen[metric.Name] {
+			return fmt.Errorf("metric %s defined twice", metric.Name)
+		}
+		if metric.Baseline < 0 {
+			return fmt.Errorf("metric %s: baseline must not be negative", metric.Name)
+		}
+		seen[metric.Name] = true
+	}
+	return nil
+}
+
+// timeout 返回插件的超时时间
+func (m *Manifest) timeout() (time.Duration, error) {
+	if m.Timeout == "" {
+		return defaultTimeout, nil
+	}
+	d, err := time.ParseDuration(m.Timeout)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid timeout: %s", m.Timeout)
+	}
+	return d, nil
+}
+
+// duration 返回插件建议的运行时长
+func (m *Manifest) duration() (time.Duration, error) {
+	if m.Duration == "" {
+		return time.Minute, nil
+	}
+	d, err := time.ParseDuration(m.Duration)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid duration: %s", m.Duration)
+	}
+	return d, nil
+}
+
+// commandPath 返回可执行文件路径，相对路径以清单目录为基准
+func (m *Manifest) commandPath() string {
+	if filepath.IsAbs(m.Command) || filepath.Base(m.Command) == m.Command {
+		return m.Command
+	}
+	return filepath.Join(m.dir, m.Command)
+}
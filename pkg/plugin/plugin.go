@@ -0,0 +1,232 @@
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"octane/pkg/benchmark"
+	"octane/pkg/executor"
+	"octane/pkg/types"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Output 定义插件写到 stdout 的 JSON 结构
+//
+//	{"metrics": {"get_ops": 125000, "p99_ms": 0.8}}
+type Output struct {
+	Metrics map[string]float64 `json:"metrics"`
+}
+
+// Plugin 是由清单描述的外部基准测试，实现 benchmark.Benchmark 接口
+type Plugin struct {
+	Manifest *Manifest
+}
+
+// Name 返回插件在注册表中的名称
+func (p *Plugin) Name() string {
+	return "plugin/" + p.Manifest.Name
+}
+
+// Category 返回插件分类
+func (p *Plugin) Category() string {
+	if p.Manifest.Category == "" {
+		return "plugin"
+	}
+	return p.Manifest.Category
+}
+
+// Description 返回插件描述
+func (p *Plugin) Description() string {
+	return p.Manifest.Description
+}
+
+// DefaultDuration 返回插件建议的运行时长
+func (p *Plugin) DefaultDuration() time.Duration {
+	d, _ := p.Manifest.duration()
+	return d
+}
+
+// Run 执行插件命令并校验其输出
+func (p *Plugin) Run(ctx context.Context, opts benchmark.Options) (*benchmark.Result, error) {
+	timeout, _ := p.Manifest.timeout()
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	if opts.Duration <= 0 {
+		opts.Duration = p.DefaultDuration()
+	}
+	if opts.Dir != "" {
+		if err := os.MkdirAll(opts.Dir, 0755); err != nil {
+			return nil, fmt.Errorf("%s: %v", p.Name(), err)
+		}
+	}
+
+	start := time.Now()
+	stdout, stderr, err := executor.RunCommand(ctx, executor.Command{
+		Path: p.Manifest.commandPath(),
+		Args: p.Manifest.Args,
+		Dir:  p.Manifest.dir,
+		Env: []string{
+			"OCTANE_DURATION=" + strconv.Itoa(int(opts.Duration.Seconds())),
+			"OCTANE_THREADS=" + strconv.Itoa(opts.Threads),
+			"OCTANE_SIZE=" + strconv.Itoa(opts.Size),
+			"OCTANE_DIR=" + opts.Dir,
+		},
+	})
+	if ctx.Err() == context.DeadlineExceeded {
+		return nil, fmt.Errorf("%s: timed out after %v", p.Name(), timeout)
+	}
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v: %s", p.Name(), err, lastLine(stderr))
+	}
+
+	metrics, err := p.parseOutput(stdout)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", p.Name(), err)
+	}
+
+	return &benchmark.Result{
+		Benchmark: p.Name(),
+		Category:  p.Category(),
+		Duration:  time.Since(start),
+		Metrics:   metrics,
+	}, nil
+}
+
+// parseOutput 解析并校验插件输出，所有声明的指标必须存在且仅能包含声明的指标
+func (p *Plugin) parseOutput(stdout []byte) ([]benchmark.Metric, error) {
+	var out Output
+	if err := json.Unmarshal(stdout, &out); err != nil {
+		return nil, fmt.Errorf("malformed output: %v", err)
+	}
+
+	declared := make(map[string]bool)
+	metrics := make([]benchmark.Metric, 0, len(p.Manifest.Metrics))
+	for _, def := range p.Manifest.Metrics {
+		declared[def.Name] = true
+		value, ok := out.Metrics[def.Name]
+		if !ok {
+			return nil, fmt.Errorf("missing metric %s", def.Name)
+		}
+		if math.IsNaN(value) || math.IsInf(value, 0) {
+			return nil, fmt.Errorf("metric %s is not a finite number", def.Name)
+		}
+		metrics = append(metrics, benchmark.Metric{
+			Name:           def.Name,
+			Value:          value,
+			Unit:           def.Unit,
+			HigherIsBetter: def.HigherIsBetter,
+		})
+	}
+
+	for name := range out.Metrics {
+		if !declared[name] {
+			return nil, fmt.Errorf("undeclared metric %s", name)
+		}
+	}
+	return metrics, nil
+}
+
+// LoadDir 加载目录下每个子目录中的插件清单，返回有效插件和加载错误
+func LoadDir(dir string) ([]*Plugin, []error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, []error{err}
+	}
+
+	var plugins []*Plugin
+	var errs []error
+	seen := make(map[string]string)
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name(), ManifestFile)
+		if _, err := os.Stat(path); err != nil {
+			continue
+		}
+
+		m, err := LoadManifest(path)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if other, dup := seen[m.Name]; dup {
+			errs = append(errs, fmt.Errorf("plugin %s defined in both %s and %s", m.Name, other, path))
+			continue
+		}
+		seen[m.Name] = path
+		plugins = append(plugins, &Plugin{Manifest: m})
+	}
+
+	sort.Slice(plugins, func(i, j int) bool { return plugins[i].Manifest.Name < plugins[j].Manifest.Name })
+	return plugins, errs
+}
+
+// Register 将插件注册到基准测试注册表
+func Register(plugins []*Plugin) error {
+	for _, p := range plugins {
+		if _, exists := benchmark.Get(p.Name()); exists {
+			return fmt.Errorf("benchmark %s already registered", p.Name())
+		}
+		benchmark.Register(p)
+	}
+	return nil
+}
+
+// Execute 依次运行所有插件，单个插件失败只记录在其结果中
+func Execute(ctx context.Context, plugins []*Plugin, opts benchmark.Options) []types.PluginResults {
+	results := make([]types.PluginResults, 0, len(plugins))
+	for _, p := range plugins {
+		if ctx.Err() != nil {
+			break
+		}
+
+		fmt.Printf("Running %s...\n", p.Name())
+		result := types.PluginResults{
+			Name:     p.Manifest.Name,
+			Category: p.Category(),
+			Weight:   p.Manifest.Weight,
+		}
+
+		run, err := benchmark.Run(ctx, p, opts)
+		if err != nil {
+			fmt.Printf("  %v\n", err)
+			result.Error = err.Error()
+			results = append(results, result)
+			continue
+		}
+
+		result.Duration = run.Duration.Round(time.Millisecond).String()
+		for i, m := range run.Metrics {
+			fmt.Printf("  %s: %.2f %s\n", m.Name, m.Value, m.Unit)
+			result.Metrics = append(result.Metrics, types.PluginMetric{
+				Name:           m.Name,
+				Value:          m.Value,
+				Unit:           m.Unit,
+				HigherIsBetter: m.HigherIsBetter,
+				Baseline:       p.Manifest.Metrics[i].Baseline,
+			})
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+// lastLine 返回输出的最后一个非空行，用于错误信息
+func lastLine(output []byte) string {
+	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+	return lines[len(lines)-1]
+}
@@ -0,0 +1,132 @@
+package plugin
+
+import (
+	"context"
+	"octane/pkg/benchmark"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// validManifest 返回一个通过校验的清单，测试在其基础上修改单个字段
+func validManifest() Manifest {
+	return Manifest{
+		Name:    "kv",
+		Command: "./bench.sh",
+		Metrics: []MetricDefinition{
+			{Name: "get_ops", Unit: "ops/s", HigherIsBetter: true, Baseline: 100000},
+			{Name: "p99_ms", Unit: "ms"},
+		},
+	}
+}
+
+func TestManifestValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*Manifest)
+		err    string
+	}{
+		{"valid", func(*Manifest) {}, ""},
+		{"full weight", func(m *Manifest) { m.Weight = 1 }, ""},
+		{"no name", func(m *Manifest) { m.Name = "" }, "name is required"},
+		{"no command", func(m *Manifest) { m.Command = "" }, "command is required"},
+		{"no metrics", func(m *Manifest) { m.Metrics = nil }, "at least one metric is required"},
+		{"bad timeout", func(m *Manifest) { m.Timeout = "soon" }, "invalid timeout: soon"},
+		{"zero timeout", func(m *Manifest) { m.Timeout = "0s" }, "invalid timeout: 0s"},
+		{"bad duration", func(m *Manifest) { m.Duration = "-1m" }, "invalid duration: -1m"},
+		{"negative weight", func(m *Manifest) { m.Weight = -0.1 }, "weight must be between 0 and 1"},
+		{"weight above 1", func(m *Manifest) { m.Weight = 1.5 }, "weight must be between 0 and 1"},
+		{"unnamed metric", func(m *Manifest) { m.Metrics[1].Name = "" }, "metric name is required"},
+		{"duplicate metric", func(m *Manifest) { m.Metrics[1].Name = "get_ops" }, "metric get_ops defined twice"},
+		{"negative baseline", func(m *Manifest) { m.Metrics[0].Baseline = -1 }, "metric get_ops: baseline must not be negative"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := validManifest()
+			tt.modify(&m)
+			err := m.Validate()
+			if tt.err == "" && err != nil {
+				t.Errorf("Validate = %v, want nil", err)
+			}
+			if tt.err != "" && (err == nil || err.Error() != tt.err) {
+				t.Errorf("Validate = %v, want %q", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestParseOutput(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		err    string
+	}{
+		{"valid", `{"metrics": {"get_ops": 125000, "p99_ms": 0.8}}`, ""},
+		{"undeclared metric", `{"metrics": {"get_ops": 125000, "p99_ms": 0.8, "set_ops": 1}}`, "undeclared metric set_ops"},
+		{"missing metric", `{"metrics": {"get_ops": 125000}}`, "missing metric p99_ms"},
+		// JSON 没有 NaN 和 Inf，这类输出在解析时即被拒绝
+		{"NaN", `{"metrics": {"get_ops": NaN, "p99_ms": 0.8}}`, "malformed output"},
+		{"Inf", `{"metrics": {"get_ops": 1e999, "p99_ms": 0.8}}`, "malformed output"},
+		{"not JSON", "get_ops=125000", "malformed output"},
+	}
+	m := validManifest()
+	p := &Plugin{Manifest: &m}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			metrics, err := p.parseOutput([]byte(tt.output))
+			if tt.err == "" {
+				if err != nil {
+					t.Fatal(err)
+				}
+				if len(metrics) != 2 || metrics[0].Name != "get_ops" || metrics[0].Value != 125000 ||
+					metrics[1].Unit != "ms" || metrics[1].HigherIsBetter {
+					t.Errorf("metrics = %+v", metrics)
+				}
+				return
+			}
+			if err == nil || !strings.HasPrefix(err.Error(), tt.err) {
+				t.Errorf("parseOutput = %v, want an error starting with %q", err, tt.err)
+			}
+		})
+	}
+}
+
+// writePlugin 在临时目录中写入清单和插件脚本，返回加载后的插件
+func writePlugin(t *testing.T, script string) *Plugin {
+	t.Helper()
+	dir := t.TempDir()
+	manifest := "name: kv\ncommand: ./bench.sh\nmetrics:\n  - name: get_ops\n  - name: p99_ms\n"
+	if err := os.WriteFile(filepath.Join(dir, ManifestFile), []byte(manifest), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "bench.sh"), []byte("#!/bin/sh\n"+script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	m, err := LoadManifest(filepath.Join(dir, ManifestFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &Plugin{Manifest: m}
+}
+
+func TestRun(t *testing.T) {
+	p := writePlugin(t, `echo "{\"metrics\": {\"get_ops\": $OCTANE_THREADS, \"p99_ms\": 0.8}}"`)
+	result, err := p.Run(context.Background(), benchmark.Options{Threads: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Benchmark != "plugin/kv" || len(result.Metrics) != 2 || result.Metrics[0].Value != 4 {
+		t.Errorf("result = %+v", result)
+	}
+
+	// 非零退出时即使 stdout 是合法输出也视为失败，错误中带上 stderr 的最后一行
+	p = writePlugin(t, `echo '{"metrics": {"get_ops": 1, "p99_ms": 1}}'
+echo "connecting" >&2
+echo "connection refused" >&2
+exit 3`)
+	_, err = p.Run(context.Background(), benchmark.Options{})
+	if err == nil || !strings.Contains(err.Error(), "exit status 3") || !strings.HasSuffix(err.Error(), ": connection refused") {
+		t.Errorf("Run = %v, want the exit status and the last stderr line", err)
+	}
+}
@@ -0,0 +1,103 @@
+package suite
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"octane/pkg/types"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Checkpoint 记录一次套件运行的进度，用于中断后恢复
+type Checkpoint struct {
+	RunID       string              `json:"run_id"`
+	Fingerprint string              `json:"fingerprint"`
+	Config      map[string]string   `json:"config"`
+	Elapsed     time.Duration       `json:"elapsed"`
+	UpdatedAt   string              `json:"updated_at"`
+	Metadata    types.Metadata      `json:"metadata"`
+	Stages      []types.StageResult `json:"stages"`
+	TestResults types.TestResults   `json:"test_results"`
+}
+
+// runIDPattern 匹配 NewTestID 生成的运行ID
+var runIDPattern = regexp.MustCompile(`^octane-[0-9]{8}-[0-9]{6}-[0-9a-f]{8}$`)
+
+// CheckpointPath 返回运行ID对应的检查点文件路径。
+// 运行ID来自命令行和检查点文件，只接受 NewTestID 的格式，避免路径穿越到检查点目录之外。
+func CheckpointPath(dir, runID string) (string, error) {
+	if !runIDPattern.MatchString(runID) {
+		return "", fmt.Errorf("invalid run ID %q", runID)
+	}
+	return filepath.Join(dir, runID+".json"), nil
+}
+
+// LoadCheckpoint 读取指定运行ID的检查点
+func LoadCheckpoint(dir, runID string) (*Checkpoint, error) {
+	path, err := CheckpointPath(dir, runID)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("no checkpoint found for run %s", runID)
+		}
+		return nil, err
+	}
+
+	var cp Checkpoint
+	if err := json.Unmarshal(data, &cp); err != nil {
+		return nil, fmt.Errorf("invalid checkpoint %s: %v", runID, err)
+	}
+	if cp.RunID != runID || cp.Metadata.TestID != runID {
+		return nil, fmt.Errorf("invalid checkpoint %s: recorded for run %q", runID, cp.RunID)
+	}
+	return &cp, nil
+}
+
+// Save 原子地写入检查点文件
+func (cp *Checkpoint) Save(dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	cp.UpdatedAt = time.Now().UTC().Format(time.RFC3339)
+	data, err := json.MarshalIndent(cp, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	// 先写临时文件再重命名，避免断电留下损坏的检查点
+	path, err := CheckpointPath(dir, cp.RunID)
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// Completed 返回检查点中已完成的阶段
+func (cp *Checkpoint) Completed() map[string]types.StageResult {
+	done := make(map[string]types.StageResult)
+	for _, stage := range cp.Stages {
+		if stage.Status == StatusCompleted {
+			done[stage.Name] = stage
+		}
+	}
+	return done
+}
+
+// Fingerprint 根据硬件标识计算系统指纹
+func Fingerprint(parts ...string) string {
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\n")))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,165 @@
+package suite
+
+import (
+	"context"
+	"octane/pkg/types"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCheckpointPath(t *testing.T) {
+	id := NewTestID(time.Date(2025, 6, 25, 6, 31, 44, 0, time.UTC))
+	path, err := CheckpointPath("/var/lib/octane", id)
+	if err != nil || path != filepath.Join("/var/lib/octane", id+".json") {
+		t.Errorf("CheckpointPath(%q) = %q, %v", id, path, err)
+	}
+
+	for _, id := range []string{
+		"",
+		"../../etc/passwd",
+		"octane-20250625-063144-1a2b3c4d/../x",
+		"octane-20250625-063144-1a2b3c4d.json",
+		"/tmp/octane-20250625-063144-1a2b3c4d",
+		"octane-20250625-063144-1A2B3C4D",
+		"octane-2025-06-25-1a2b3c4d",
+	} {
+		if _, err := CheckpointPath("/var/lib/octane", id); err == nil {
+			t.Errorf("CheckpointPath(%q): expected an error", id)
+		}
+	}
+}
+
+func TestCheckpointRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	cp := &Checkpoint{
+		RunID:       "octane-20250625-063144-1a2b3c4d",
+		Fingerprint: Fingerprint("cpu", "host"),
+		Config:      map[string]string{"only": "cpu,memory", "duration": "30s"},
+		Elapsed:     90 * time.Second,
+		Metadata:    types.Metadata{TestID: "octane-20250625-063144-1a2b3c4d", Version: "1.2.0"},
+		Stages:      []types.StageResult{{Name: "cpu", Status: StatusCompleted, StartTime: "2025-06-25T06:31:44Z"}},
+	}
+	cp.TestResults.CPU.Tests.MultiCore.IntegerPerformance.Score = 4200
+	if err := cp.Save(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := LoadCheckpoint(dir, cp.RunID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.UpdatedAt == "" || !reflect.DeepEqual(loaded, cp) {
+		t.Errorf("loaded %+v\nwant %+v", loaded, cp)
+	}
+
+	if _, err := LoadCheckpoint(dir, "octane-20250625-063144-00000000"); err == nil || !strings.Contains(err.Error(), "no checkpoint found") {
+		t.Errorf("missing checkpoint: %v", err)
+	}
+	if _, err := LoadCheckpoint(dir, "../"+filepath.Base(dir)+"/"+cp.RunID); err == nil || !strings.Contains(err.Error(), "invalid run ID") {
+		t.Errorf("path in the run ID: %v", err)
+	}
+
+	// 检查点中记录的运行ID与文件名不一致时拒绝加载，之后删除检查点时不会用到其中的路径
+	other := "octane-20250625-063144-ffffffff"
+	data, err := os.ReadFile(filepath.Join(dir, cp.RunID+".json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, other+".json"), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadCheckpoint(dir, other); err == nil {
+		t.Error("loaded a checkpoint recorded for another run")
+	}
+
+	cp.RunID = "../escape"
+	if err := cp.Save(dir); err == nil {
+		t.Error("saved a checkpoint with an invalid run ID")
+	}
+}
+
+func TestResume(t *testing.T) {
+	dir := t.TempDir()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// 第一次运行：cpu 完成，memory 中途被中断
+	var first stageLog
+	stages := []Stage{
+		first.stage("cpu", func(ctx context.Context, results *types.TestResults) error {
+			results.CPU.Tests.MultiCore.IntegerPerformance.Score = 4200
+			return nil
+		}),
+		first.stage("memory", func(ctx context.Context, results *types.TestResults) error {
+			cancel()
+			<-ctx.Done()
+			return ctx.Err()
+		}),
+		first.stage("storage", nil),
+	}
+	opts := Options{CheckpointDir: dir, Fingerprint: Fingerprint("cpu", "host"), Config: map[string]string{"duration": "30s"}}
+	report, err := NewRunner(stages, opts).Run(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !report.Metadata.Partial {
+		t.Fatal("first run not partial")
+	}
+	runID := report.Metadata.TestID
+	cpuStage := report.Stages[0]
+
+	cp, err := LoadCheckpoint(dir, runID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cp.Config["duration"] != "30s" || len(cp.Completed()) != 1 {
+		t.Errorf("checkpoint = %+v", cp)
+	}
+
+	// 硬件指纹变化时拒绝恢复
+	changed := opts
+	changed.Fingerprint = Fingerprint("other cpu", "host")
+	if _, err := NewRunner(stages, changed).Resume(context.Background(), cp); err == nil || !strings.Contains(err.Error(), "fingerprint changed") {
+		t.Errorf("Resume with another fingerprint: %v", err)
+	}
+
+	// 恢复运行：只执行未完成的阶段，已完成阶段的结果和时间戳保留
+	var second stageLog
+	stages = []Stage{
+		second.stage("cpu", nil),
+		second.stage("memory", func(ctx context.Context, results *types.TestResults) error {
+			results.Memory.Bandwidth.Copy = 12000
+			return nil
+		}),
+		second.stage("storage", nil),
+	}
+	merged, err := NewRunner(stages, opts).Resume(context.Background(), cp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(second, " "); got != "memory storage" {
+		t.Errorf("resumed run ran %q, want the unfinished stages", got)
+	}
+	if got := statuses(merged); got != "cpu=completed memory=completed storage=completed" || merged.Metadata.Partial {
+		t.Errorf("merged stages: %s (partial %v)", got, merged.Metadata.Partial)
+	}
+	if merged.Metadata.TestID != runID {
+		t.Errorf("merged run ID %s, want %s", merged.Metadata.TestID, runID)
+	}
+	if merged.Stages[0] != cpuStage {
+		t.Errorf("cpu stage = %+v, want the original %+v", merged.Stages[0], cpuStage)
+	}
+	for _, stage := range merged.Stages[1:] {
+		if stage.StartTime == "" || stage.EndTime == "" || stage.Duration == "" {
+			t.Errorf("stage %s has no timestamps: %+v", stage.Name, stage)
+		}
+	}
+	if merged.TestResults.CPU.Tests.MultiCore.IntegerPerformance.Score != 4200 || merged.TestResults.Memory.Bandwidth.Copy != 12000 {
+		t.Errorf("merged results lost a stage: cpu %d, memory copy %v",
+			merged.TestResults.CPU.Tests.MultiCore.IntegerPerformance.Score, merged.TestResults.Memory.Bandwidth.Copy)
+	}
+}
@@ -0,0 +1,393 @@
+package suite
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"octane/pkg/gpu"
+	"octane/pkg/monitor"
+	"octane/pkg/octane"
+	"octane/pkg/types"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// 阶段状态
+const (
+	StatusCompleted   = "completed"
+	StatusFailed      = "failed"
+	StatusInterrupted = "interrupted"
+	StatusSkipped     = "skipped"
+)
+
+// Stage 定义测试套件中的一个阶段
+type Stage struct {
+	Name string
+	Run  func(ctx context.Context, results *types.TestResults) error
+}
+
+// Options 定义套件运行选项
+type Options struct {
+	Only     []string      // 仅运行这些阶段
+	Skip     []string      // 跳过这些阶段
+	Cooldown time.Duration // 阶段之间的冷却时间
+	Version  string        // 报告版本
+	Tags     []string      // 报告标签
+
+	StealThreshold float64 // CPU steal 超过此百分比时将结果标记为不可靠，0 表示不检查
+
+	Quiescence  *monitor.QuietOptions // 阶段开始前等待系统空闲，为空时不检查
+	AbortIfBusy bool                  // 超时后系统仍繁忙时中止运行，否则继续并将报告标记为 contended
+
+	GPUTelemetry gpu.Source    // 每个阶段运行期间采样 GPU 遥测，为空时不采样
+	GPUInterval  time.Duration // GPU 遥测的采样间隔
+
+	CheckpointDir string            // 检查点目录，为空时不写检查点
+	Fingerprint   string            // 系统硬件指纹
+	Config        map[string]string // 写入检查点的运行参数
+}
+
+// Runner 按顺序执行测试阶段并汇总报告
+type Runner struct {
+	Stages     []Stage
+	Options    Options
+	Calculator *octane.OctaneCalculator
+}
+
+// NewRunner 创建一个新的套件执行器
+func NewRunner(stages []Stage, opts Options) *Runner {
+	return &Runner{
+		Stages:     stages,
+		Options:    opts,
+		Calculator: octane.NewOctaneCalculator(),
+	}
+}
+
+// Run 依次执行所有选中的阶段，单个阶段失败不会中断其他阶段。
+// 上下文被取消时停止当前阶段，跳过剩余阶段，并将报告标记为部分结果。
+func (r *Runner) Run(ctx context.Context) (*types.Report, error) {
+	return r.run(ctx, nil)
+}
+
+// Resume 从检查点继续运行，跳过已完成的阶段；硬件指纹变化时拒绝恢复
+func (r *Runner) Resume(ctx context.Context, cp *Checkpoint) (*types.Report, error) {
+	if cp.Fingerprint != r.Options.Fingerprint {
+		return nil, fmt.Errorf("hardware fingerprint changed since run %s started, refusing to resume", cp.RunID)
+	}
+	return r.run(ctx, cp)
+}
+
+// run 执行套件，cp 不为空时在其基础上继续
+func (r *Runner) run(ctx context.Context, cp *Checkpoint) (*types.Report, error) {
+	if err := r.validateSelection(); err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	if cp == nil {
+		cp = &Checkpoint{
+			RunID:       NewTestID(start),
+			Fingerprint: r.Options.Fingerprint,
+			Config:      r.Options.Config,
+			Metadata: types.Metadata{
+				Version:   r.Options.Version,
+				Timestamp: start.UTC().Format(time.RFC3339),
+				User:      os.Getenv("USER"),
+				Tags:      r.Options.Tags,
+			},
+		}
+		cp.Metadata.TestID = cp.RunID
+		cp.Metadata.Hostname, _ = os.Hostname()
+	}
+
+	report := &types.Report{
+		Metadata:    cp.Metadata,
+		TestResults: cp.TestResults,
+	}
+	done := cp.Completed()
+	elapsed := cp.Elapsed
+
+	ran := 0
+	aborted := false
+	for _, stage := range r.Stages {
+		if previous, ok := done[stage.Name]; ok {
+			fmt.Printf("✓ Stage %s already completed at %s, skipping\n", stage.Name, previous.EndTime)
+			report.Stages = append(report.Stages, previous)
+			continue
+		}
+		if !r.selected(stage.Name) || ctx.Err() != nil || aborted {
+			report.Stages = append(report.Stages, types.StageResult{Name: stage.Name, Status: StatusSkipped})
+			continue
+		}
+
+		// 阶段之间冷却，避免上一阶段的热量影响下一阶段
+		if ran > 0 && r.Options.Cooldown > 0 {
+			fmt.Printf("Cooling down for %v...\n", r.Options.Cooldown)
+			if !sleep(ctx, r.Options.Cooldown) {
+				report.Stages = append(report.Stages, types.StageResult{Name: stage.Name, Status: StatusSkipped})
+				continue
+			}
+		}
+		ran++
+
+		// 等待其他进程的负载退去，避免后台任务拉低分数
+		if r.Options.Quiescence != nil {
+			load, err := monitor.WaitQuiet(ctx, *r.Options.Quiescence)
+			if ctx.Err() != nil {
+				report.Stages = append(report.Stages, types.StageResult{Name: stage.Name, Status: StatusSkipped})
+				continue
+			}
+			if err != nil {
+				fmt.Printf("Warning: failed to sample background load: %v\n", err)
+			} else if r.recordLoad(report, stage.Name, load) && r.Options.AbortIfBusy {
+				report.Stages = append(report.Stages, types.StageResult{
+					Name:   stage.Name,
+					Status: StatusFailed,
+					Error:  "system busy: " + r.Options.Quiescence.Busy(load),
+				})
+				r.checkpoint(cp, report, elapsed+time.Since(start))
+				aborted = true
+				continue
+			}
+		}
+
+		fmt.Printf("\n▶ Stage %s\n", stage.Name)
+		result := r.runStage(ctx, stage, &report.TestResults)
+		switch result.Status {
+		case StatusFailed:
+			fmt.Printf("✗ Stage %s failed: %s\n", stage.Name, result.Error)
+		case StatusInterrupted:
+			fmt.Printf("⚠ Stage %s interrupted\n", stage.Name)
+		default:
+			fmt.Printf("✓ Stage %s completed in %s\n", stage.Name, result.Duration)
+		}
+		report.Stages = append(report.Stages, result)
+		r.checkpoint(cp, report, elapsed+time.Since(start))
+	}
+
+	report.Metadata.Partial = ctx.Err() != nil
+	report.Metadata.Unreliable = len(r.StealExceeded(&report.TestResults)) > 0
+	report.Metadata.Duration = (elapsed + time.Since(start)).Round(time.Second).String()
+	r.rate(report)
+
+	return report, nil
+}
+
+// recordLoad 将阶段开始前的后台负载写入报告，系统未能空闲时打印最繁忙的进程并返回 true
+func (r *Runner) recordLoad(report *types.Report, stage string, load types.BackgroundLoad) bool {
+	if report.Metadata.BackgroundLoad == nil {
+		report.Metadata.BackgroundLoad = make(map[string]types.BackgroundLoad)
+	}
+	report.Metadata.BackgroundLoad[stage] = load
+	if !load.Contended {
+		return false
+	}
+
+	report.Metadata.Contended = true
+	fmt.Printf("⚠ System still busy after %s: %s\n", load.Waited, r.Options.Quiescence.Busy(load))
+	for _, p := range load.Processes {
+		fmt.Printf("    %7d  %-20s %5.1f%% CPU\n", p.PID, p.Name, p.CPU)
+	}
+	return true
+}
+
+// runStage 执行单个阶段，捕获错误和 panic
+func (r *Runner) runStage(ctx context.Context, stage Stage, results *types.TestResults) (result types.StageResult) {
+	start := time.Now()
+	result = types.StageResult{
+		Name:      stage.Name,
+		StartTime: start.UTC().Format(time.RFC3339),
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			result.Status = StatusFailed
+			result.Error = fmt.Sprintf("panic: %v", p)
+		}
+		end := time.Now()
+		result.EndTime = end.UTC().Format(time.RFC3339)
+		result.Duration = end.Sub(start).Round(time.Millisecond).String()
+	}()
+
+	// 阶段在独立的结果副本上运行，中断时不会留下半成品数据
+	staged := *results
+	stageCtx, recorder := monitor.WithRecorder(ctx)
+	var sampler *gpu.Sampler
+	if r.Options.GPUTelemetry != nil {
+		sampler = gpu.StartSampler(ctx, r.Options.GPUTelemetry, r.Options.GPUInterval)
+		defer sampler.Stop() // 阶段 panic 时结束采样，重复调用无副作用
+	}
+	err := stage.Run(stageCtx, &staged)
+	var telemetry types.GPUSample
+	if sampler != nil {
+		telemetry = sampler.Stop()
+	}
+	if ctx.Err() != nil {
+		result.Status = StatusInterrupted
+		result.Error = ctx.Err().Error()
+		return result
+	}
+	if err != nil {
+		result.Status = StatusFailed
+		result.Error = err.Error()
+		return result
+	}
+
+	// 副本与原结果共享 map，合并到新 map 中
+	steal := make(map[string]types.StealSample, len(results.Steal))
+	for name, sample := range results.Steal {
+		steal[name] = sample
+	}
+	for name, sample := range recorder.Samples() {
+		steal[name] = sample
+	}
+	staged.Steal = steal
+	if samples := recorder.Power(); len(samples) > 0 {
+		power := make(map[string]types.PowerSample, len(results.Power)+len(samples))
+		for name, sample := range results.Power {
+			power[name] = sample
+		}
+		for name, sample := range samples {
+			power[name] = sample
+		}
+		staged.Power = power
+	}
+	if sampler != nil {
+		gpus := make(map[string]types.GPUSample, len(results.GPUTelemetry)+1)
+		for name, sample := range results.GPUTelemetry {
+			gpus[name] = sample
+		}
+		gpus[stage.Name] = telemetry
+		staged.GPUTelemetry = gpus
+	}
+	*results = staged
+
+	result.Status = StatusCompleted
+	return result
+}
+
+// checkpoint 在每个阶段结束后保存进度
+func (r *Runner) checkpoint(cp *Checkpoint, report *types.Report, elapsed time.Duration) {
+	if r.Options.CheckpointDir == "" {
+		return
+	}
+
+	cp.Metadata = report.Metadata
+	cp.Stages = report.Stages
+	cp.TestResults = report.TestResults
+	cp.Elapsed = elapsed
+	if err := cp.Save(r.Options.CheckpointDir); err != nil {
+		fmt.Printf("Warning: failed to write checkpoint: %v\n", err)
+	}
+}
+
+// rate 根据已完成阶段计算辛烷值评级，失败、中断或跳过的阶段不计入总分
+func (r *Runner) rate(report *types.Report) {
+	var completed []string
+	for _, stage := range report.Stages {
+		if stage.Status == StatusCompleted {
+			completed = append(completed, stage.Name)
+		}
+	}
+	overall := r.Calculator.CalculateOctaneFor(&report.TestResults, completed)
+	components := r.Calculator.CalculateComponentOctanes(&report.TestResults)
+
+	report.OctaneRatings.Overall = *overall
+	report.OctaneRatings.Breakdown = make(map[string]types.OctaneRating)
+	report.Scores.Overall = overall.RON
+	report.Scores.Breakdown = make(map[string]float64)
+
+	for _, stage := range report.Stages {
+		if stage.Status != StatusCompleted {
+			continue
+		}
+		if rating, ok := components[stage.Name]; ok {
+			report.OctaneRatings.Breakdown[stage.Name] = rating
+			report.Scores.Breakdown[stage.Name] = rating.RON
+		}
+	}
+
+	// 插件评级以 plugin/<name> 为键，由插件阶段统一产生
+	for name, rating := range components {
+		if strings.HasPrefix(name, "plugin/") {
+			report.OctaneRatings.Breakdown[name] = rating
+			report.Scores.Breakdown[name] = rating.RON
+		}
+	}
+
+	report.Scores.ProfessionalScenarios = r.Calculator.CalculateProfessionalScenarios(&report.TestResults)
+}
+
+// StealExceeded 返回平均 CPU steal 超过阈值的基准测试，按名称排序
+func (r *Runner) StealExceeded(results *types.TestResults) []string {
+	if r.Options.StealThreshold <= 0 {
+		return nil
+	}
+
+	var names []string
+	for name, sample := range results.Steal {
+		if sample.Steal > r.Options.StealThreshold {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// selected 判断阶段是否被 Only/Skip 选中
+func (r *Runner) selected(name string) bool {
+	if len(r.Options.Only) > 0 && !contains(r.Options.Only, name) {
+		return false
+	}
+	return !contains(r.Options.Skip, name)
+}
+
+// validateSelection 检查 Only/Skip 中的阶段名是否存在
+func (r *Runner) validateSelection() error {
+	for _, name := range append(append([]string{}, r.Options.Only...), r.Options.Skip...) {
+		found := false
+		for _, stage := range r.Stages {
+			if stage.Name == name {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("unknown stage: %s", name)
+		}
+	}
+	return nil
+}
+
+// sleep 等待指定时间，上下文取消时提前返回 false
+func sleep(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// NewTestID 生成形如 octane-20250625-063144-1a2b3c4d 的测试ID
+func NewTestID(t time.Time) string {
+	buf := make([]byte, 4)
+	rand.Read(buf)
+	return fmt.Sprintf("octane-%s-%s", t.Format("20060102-150405"), hex.EncodeToString(buf))
+}
+
+// contains 判断切片中是否包含指定字符串
+func contains(items []string, item string) bool {
+	for _, i := range items {
+		if i == item {
+			return true
+		}
+	}
+	return false
+}
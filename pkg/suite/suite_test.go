@@ -0,0 +1,177 @@
+package suite
+
+import (
+	"context"
+	"errors"
+	"math"
+	"octane/pkg/types"
+	"strings"
+	"testing"
+)
+
+// stageLog 记录各阶段的运行顺序
+type stageLog []string
+
+// stage 返回一个记录自身运行、再执行 run 的阶段
+func (l *stageLog) stage(name string, run func(ctx context.Context, results *types.TestResults) error) Stage {
+	return Stage{Name: name, Run: func(ctx context.Context, results *types.TestResults) error {
+		*l = append(*l, name)
+		if run == nil {
+			return nil
+		}
+		return run(ctx, results)
+	}}
+}
+
+// statuses 返回报告中各阶段的 名称=状态
+func statuses(report *types.Report) string {
+	var parts []string
+	for _, stage := range report.Stages {
+		parts = append(parts, stage.Name+"="+stage.Status)
+	}
+	return strings.Join(parts, " ")
+}
+
+func TestRunIsolatesFailingStages(t *testing.T) {
+	var ran stageLog
+	stages := []Stage{
+		ran.stage("cpu", func(ctx context.Context, results *types.TestResults) error {
+			// 失败阶段写入的结果不应留在报告中
+			results.CPU.Tests.MultiCore.IntegerPerformance.Score = 1 << 30
+			return errors.New("benchmark crashed")
+		}),
+		ran.stage("memory", func(ctx context.Context, results *types.TestResults) error {
+			panic("index out of range")
+		}),
+		ran.stage("storage", nil),
+	}
+
+	report, err := NewRunner(stages, Options{}).Run(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(ran, " "); got != "cpu memory storage" {
+		t.Errorf("ran %q, want every stage", got)
+	}
+	if got := statuses(report); got != "cpu=failed memory=failed storage=completed" {
+		t.Errorf("stages: %s", got)
+	}
+	if report.Stages[0].Error != "benchmark crashed" || report.Stages[1].Error != "panic: index out of range" {
+		t.Errorf("errors not recorded: %+v", report.Stages)
+	}
+	for _, stage := range report.Stages {
+		if stage.StartTime == "" || stage.EndTime == "" || stage.Duration == "" {
+			t.Errorf("stage %s has no timestamps: %+v", stage.Name, stage)
+		}
+	}
+	if report.TestResults.CPU.Tests.MultiCore.IntegerPerformance.Score != 0 {
+		t.Error("results of the failed cpu stage were kept")
+	}
+	if report.Metadata.Partial {
+		t.Error("report marked partial without cancellation")
+	}
+}
+
+func TestRunRatesCompletedStagesOnly(t *testing.T) {
+	stages := []Stage{
+		{Name: "cpu", Run: func(context.Context, *types.TestResults) error { return errors.New("failed") }},
+		// 内存带宽远高于基准值，得分高于未运行组件的最低分
+		{Name: "memory", Run: func(ctx context.Context, results *types.TestResults) error {
+			bandwidth := &results.Memory.Bandwidth
+			bandwidth.SequentialRead, bandwidth.SequentialWrite, bandwidth.Copy = 1e9, 1e9, 1e9
+			return nil
+		}},
+		{Name: "storage", Run: func(context.Context, *types.TestResults) error { return nil }},
+	}
+	runner := NewRunner(stages, Options{Skip: []string{"storage"}})
+	report, err := runner.Run(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := runner.Calculator.CalculateOctaneFor(&report.TestResults, []string{"memory"}).RON
+	if got := report.Scores.Overall; math.Abs(got-want) > 1e-9 {
+		t.Errorf("overall = %v, want %v from the memory stage only", got, want)
+	}
+	if all := runner.Calculator.CalculateOctane(&report.TestResults).RON; math.Abs(report.Scores.Overall-all) < 1e-9 {
+		t.Errorf("overall %v also counts the failed and skipped stages", all)
+	}
+	if _, ok := report.Scores.Breakdown["cpu"]; ok || len(report.Scores.Breakdown) != 1 {
+		t.Errorf("breakdown = %v, want memory only", report.Scores.Breakdown)
+	}
+}
+
+func TestRunSelection(t *testing.T) {
+	tests := []struct {
+		name       string
+		only, skip []string
+		ran        string
+		err        string
+	}{
+		{"all", nil, nil, "cpu memory storage", ""},
+		{"only", []string{"memory", "cpu"}, nil, "cpu memory", ""},
+		{"skip", nil, []string{"memory"}, "cpu storage", ""},
+		{"only and skip", []string{"cpu", "memory"}, []string{"cpu"}, "memory", ""},
+		{"unknown only", []string{"disk"}, nil, "", "unknown stage: disk"},
+		{"unknown skip", nil, []string{"gpu"}, "", "unknown stage: gpu"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var ran stageLog
+			stages := []Stage{ran.stage("cpu", nil), ran.stage("memory", nil), ran.stage("storage", nil)}
+			report, err := NewRunner(stages, Options{Only: tt.only, Skip: tt.skip}).Run(context.Background())
+			if tt.err != "" {
+				if err == nil || err.Error() != tt.err {
+					t.Errorf("Run = %v, want %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := strings.Join(ran, " "); got != tt.ran {
+				t.Errorf("ran %q, want %q", got, tt.ran)
+			}
+			// 未选中的阶段仍出现在报告中，状态为 skipped
+			for _, stage := range report.Stages {
+				if want := map[bool]string{true: StatusCompleted, false: StatusSkipped}[strings.Contains(tt.ran, stage.Name)]; stage.Status != want {
+					t.Errorf("stage %s: %s, want %s", stage.Name, stage.Status, want)
+				}
+			}
+		})
+	}
+}
+
+func TestRunCancellation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var ran stageLog
+	stages := []Stage{
+		ran.stage("cpu", nil),
+		ran.stage("memory", func(ctx context.Context, results *types.TestResults) error {
+			// 模拟 Ctrl-C：阶段运行中取消，阶段随后因上下文取消返回
+			cancel()
+			<-ctx.Done()
+			return ctx.Err()
+		}),
+		ran.stage("storage", nil),
+	}
+
+	report, err := NewRunner(stages, Options{}).Run(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(ran, " "); got != "cpu memory" {
+		t.Errorf("ran %q, want the stages up to the cancellation", got)
+	}
+	if got := statuses(report); got != "cpu=completed memory=interrupted storage=skipped" {
+		t.Errorf("stages: %s", got)
+	}
+	if !report.Metadata.Partial {
+		t.Error("report not marked partial")
+	}
+	if _, ok := report.Scores.Breakdown["cpu"]; !ok {
+		t.Errorf("the completed cpu stage is missing from the breakdown: %v", report.Scores.Breakdown)
+	}
+}
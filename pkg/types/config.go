@@ -5,6 +5,8 @@ type Config struct {
     OutputFormat   string `yaml:"output_format"`
     ProgressBar    bool   `yaml:"progress_bar"`
     TempDir        string `yaml:"temp_dir"`
+    Database       string `yaml:"database"`
+    CheckpointDir  string `yaml:"checkpoint_dir"`
     Theme          string `yaml:"theme"`
     
     Octane struct {
@@ -24,9 +26,77 @@ type Config struct {
     } `yaml:"upload"`
 
     Tests struct {
-        BoostMode              bool `yaml:"boost_mode"`
-        FuelAnalysis           bool `yaml:"fuel_analysis"`
-        TemperatureMonitoring  bool `yaml:"temperature_monitoring"`
-        PowerMonitoring        bool `yaml:"power_monitoring"`
+        BoostMode              bool    `yaml:"boost_mode"`
+        FuelAnalysis           bool    `yaml:"fuel_analysis"`
+        TemperatureMonitoring  bool    `yaml:"temperature_monitoring"`
+        PowerMonitoring        bool    `yaml:"power_monitoring"`
+        StealThreshold         float64 `yaml:"steal_threshold"` // %
+
+        GPUTelemetry struct {
+            Enabled  bool   `yaml:"enabled"`
+            Interval string `yaml:"interval"`
+        } `yaml:"gpu_telemetry"`
+
+        Quiescence struct {
+            Enabled    bool    `yaml:"enabled"`
+            Timeout    string  `yaml:"timeout"`
+            MaxCPU     float64 `yaml:"max_cpu"`     // %
+            MaxDisk    float64 `yaml:"max_disk"`    // MB/s
+            MaxNetwork float64 `yaml:"max_network"` // MB/s
+            Action     string  `yaml:"action"`      // mark, abort
+        } `yaml:"quiescence"`
     } `yaml:"tests"`
+
+    Network struct {
+        Catalog       string             `yaml:"catalog"`
+        Concurrency   int                `yaml:"concurrency"`
+        Streams       int                `yaml:"streams"`
+        Duration      string             `yaml:"duration"`
+        UDPRate       float64            `yaml:"udp_rate"`       // Mbps
+        RegionWeights map[string]float64 `yaml:"region_weights"` // domestic, international
+        LinkSpeed     struct {
+            Expected   int            `yaml:"expected"`   // Mbps, 0 disables the check
+            Interfaces map[string]int `yaml:"interfaces"` // Mbps per interface name
+        } `yaml:"link_speed"`
+        DNS           struct {
+            Names     []string `yaml:"names"`
+            Resolvers []string `yaml:"resolvers"` // system, udp://, tcp://, tls://, https://
+            Queries   int      `yaml:"queries"`
+            Timeout   string   `yaml:"timeout"`
+        } `yaml:"dns"`
+        Connectivity  struct {
+            Timeout  string `yaml:"timeout"`
+            Services []struct {
+                Name         string `yaml:"name"`
+                URL          string `yaml:"url"`
+                ExpectStatus int    `yaml:"expect_status"`
+                Address      string `yaml:"address"` // host:port
+                TLS          bool   `yaml:"tls"`
+                Insecure     bool   `yaml:"insecure"`
+            } `yaml:"services"`
+        } `yaml:"connectivity"`
+        PortScan      struct {
+            Concurrency int     `yaml:"concurrency"`
+            Rate        float64 `yaml:"rate"` // connections per second
+            Timeout     string  `yaml:"timeout"`
+            MaxTargets  int     `yaml:"max_targets"` // host:port pairs per scan
+            Targets     []struct {
+                Host  string `yaml:"host"`
+                Ports string `yaml:"ports"` // e.g. "22,80,8000-8010"
+            } `yaml:"targets"`
+        } `yaml:"port_scan"`
+        Iperf3        struct {
+            Servers []struct {
+                Name   string `yaml:"name"`
+                Host   string `yaml:"host"`
+                Port   int    `yaml:"port"`
+                Region string `yaml:"region"` // domestic, international
+            } `yaml:"servers"`
+        } `yaml:"iperf3"`
+    } `yaml:"network"`
+
+    Plugins struct {
+        Dir     string             `yaml:"dir"`
+        Weights map[string]float64 `yaml:"weights"` // 按插件名覆盖清单中的权重
+    } `yaml:"plugins"`
 }
\ No newline at end of file
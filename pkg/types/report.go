@@ -10,23 +10,61 @@ type Report struct {
 	Recommendations Recommendations `yaml:"recommendations"`
 	UploadInfo      UploadInfo      `yaml:"upload_info"`
 	OctaneRatings   OctaneRatings   `yaml:"octane_ratings"`
+	Stages          []StageResult   `yaml:"stages"`
 }
 
 // Metadata contains information about the report.
 type Metadata struct {
-	Version       string   `yaml:"version"`
-	TestID        string   `yaml:"test_id"`
-	Timestamp     string   `yaml:"timestamp"`
-	User          string   `yaml:"user"`
-	Hostname      string   `yaml:"hostname"`
-	Duration      string   `yaml:"duration"`
-	UploadConsent bool     `yaml:"upload_consent"`
-	Tags          []string `yaml:"tags"`
+	Version          string   `yaml:"version"`
+	TestID           string   `yaml:"test_id"`
+	Timestamp        string   `yaml:"timestamp"`
+	User             string   `yaml:"user"`
+	Hostname         string   `yaml:"hostname"`
+	Duration         string   `yaml:"duration"`
+	UploadConsent    bool     `yaml:"upload_consent"`
+	Partial          bool     `yaml:"partial"`           // 运行被中断，仅包含已完成阶段的结果
+	ContainerLimited bool     `yaml:"container_limited"` // CPU 或内存受 cgroup 限制，结果不代表整机性能
+	Unreliable       bool     `yaml:"unreliable"`        // 测试期间 CPU steal 超过阈值，结果受邻居虚拟机干扰
+	Contended        bool     `yaml:"contended"`         // 有阶段开始前系统未能在超时内空闲
+	Tags             []string `yaml:"tags"`
+
+	BackgroundLoad map[string]BackgroundLoad `yaml:"background_load,omitempty"` // 按阶段记录开始前的后台负载
+}
+
+// BackgroundLoad records system activity measured before a stage started.
+type BackgroundLoad struct {
+	Load1     float64       `yaml:"load1"`
+	CPU       float64       `yaml:"cpu"`     // % busy across all CPUs
+	Disk      float64       `yaml:"disk"`    // MB/s read + written
+	Network   float64       `yaml:"network"` // MB/s received + sent, excluding loopback
+	Waited    string        `yaml:"waited"`
+	Contended bool          `yaml:"contended"`
+	Processes []ProcessLoad `yaml:"processes,omitempty"` // busiest processes
+}
+
+// ProcessLoad is a process's CPU usage while background load was sampled.
+type ProcessLoad struct {
+	PID  int     `yaml:"pid"`
+	Name string  `yaml:"name"`
+	CPU  float64 `yaml:"cpu"` // % of one CPU
+}
+
+// StageResult records the outcome of a single test suite stage.
+type StageResult struct {
+	Name      string `yaml:"name"`
+	Status    string `yaml:"status"` // completed, failed, interrupted, skipped
+	Error     string `yaml:"error,omitempty"`
+	StartTime string `yaml:"start_time,omitempty"`
+	EndTime   string `yaml:"end_time,omitempty"`
+	Duration  string `yaml:"duration,omitempty"`
 }
 
 // SystemInfo contains details about the system being tested.
 type SystemInfo struct {
 	Host    HostInfo      `yaml:"host"`
+	Product ProductInfo   `yaml:"product"`
+	Board   BoardInfo     `yaml:"board"`
+	BIOS    BIOSInfo      `yaml:"bios"`
 	CPU     CPUInfo       `yaml:"cpu"`
 	Memory  MemoryInfo    `yaml:"memory"`
 	Storage []StorageInfo `yaml:"storage"`
@@ -52,6 +90,7 @@ type Comparisons struct {
 // Recommendations contains optimization tips.
 type Recommendations struct {
 	FuelOptimizationTips []FuelOptimizationTip `yaml:"fuel_optimization_tips"`
+	Hardware             []Recommendation      `yaml:"hardware,omitempty"` // configuration problems, e.g. a NIC linked below its expected speed
 }
 
 // UploadInfo contains information about the report upload.
@@ -77,6 +116,37 @@ type HostInfo struct {
 	Hostname     string `yaml:"hostname"`
 	Uptime       string `yaml:"uptime"`
 	Timezone     string `yaml:"timezone"`
+
+	Container     string  `yaml:"container,omitempty"` // docker, kubernetes, lxc, systemd-nspawn, ...
+	CgroupVersion int     `yaml:"cgroup_version,omitempty"`
+	CPULimit      float64 `yaml:"cpu_limit,omitempty"`    // CPUs available under the cgroup quota/cpuset
+	MemoryLimit   int     `yaml:"memory_limit,omitempty"` // MB
+	Hypervisor    string  `yaml:"hypervisor,omitempty"`   // kvm, vmware, xen, hyperv, ...
+	Cloud         string  `yaml:"cloud,omitempty"`        // aws, gcp, azure, ...
+}
+
+// ProductInfo contains the system manufacturer and model from SMBIOS.
+type ProductInfo struct {
+	Manufacturer string `yaml:"manufacturer"`
+	Name         string `yaml:"name"`
+	Version      string `yaml:"version"`
+	SerialNumber string `yaml:"serial_number"`
+	UUID         string `yaml:"uuid"`
+}
+
+// BoardInfo contains baseboard information from SMBIOS.
+type BoardInfo struct {
+	Manufacturer string `yaml:"manufacturer"`
+	Product      string `yaml:"product"`
+	Version      string `yaml:"version"`
+	SerialNumber string `yaml:"serial_number"`
+}
+
+// BIOSInfo contains firmware information from SMBIOS.
+type BIOSInfo struct {
+	Vendor      string `yaml:"vendor"`
+	Version     string `yaml:"version"`
+	ReleaseDate string `yaml:"release_date"`
 }
 
 // CPUCores contains CPU core information.
@@ -118,9 +188,13 @@ type MemorySlots struct {
 
 // MemoryModule contains individual memory module information.
 type MemoryModule struct {
+	Slot         string `yaml:"slot"`
 	Size         int    `yaml:"size"` // MB
+	Type         string `yaml:"type"`
+	Speed        int    `yaml:"speed"` // MT/s
 	Manufacturer string `yaml:"manufacturer"`
 	PartNumber   string `yaml:"part_number"`
+	SerialNumber string `yaml:"serial_number"`
 }
 
 // StorageInfo contains storage device information.
@@ -133,14 +207,24 @@ type StorageInfo struct {
 	Used        int    `yaml:"used"`        // MB
 	Health      int    `yaml:"health"`      // %
 	Temperature int    `yaml:"temperature"` // °C
+
+	SmartStatus        string `yaml:"smart_status,omitempty"` // passed, failed
+	PercentageUsed     int    `yaml:"percentage_used"`        // % of rated endurance
+	MediaErrors        int64  `yaml:"media_errors"`
+	ReallocatedSectors int64  `yaml:"reallocated_sectors"`
+	PowerOnHours       int64  `yaml:"power_on_hours"`
 }
 
 // GPUInfo contains GPU information.
 type GPUInfo struct {
 	Index        int            `yaml:"index"`
 	Name         string         `yaml:"name"`
+	Vendor       string         `yaml:"vendor"`
+	VendorID     string         `yaml:"vendor_id"` // PCI vendor ID, hex
+	DeviceID     string         `yaml:"device_id"` // PCI device ID, hex
 	Architecture string         `yaml:"architecture"`
 	PCIBus       string         `yaml:"pci_bus"`
+	PCIe         PCIeLink       `yaml:"pcie"`
 	CUDACores    int            `yaml:"cuda_cores"`
 	RTCores      int            `yaml:"rt_cores"`
 	TensorCores  int            `yaml:"tensor_cores"`
@@ -151,6 +235,14 @@ type GPUInfo struct {
 	Driver       GPUDriver      `yaml:"driver"`
 }
 
+// PCIeLink contains the negotiated and maximum PCIe link of a device.
+type PCIeLink struct {
+	Speed    float64 `yaml:"speed"` // GT/s
+	Width    int     `yaml:"width"` // lanes
+	MaxSpeed float64 `yaml:"max_speed"`
+	MaxWidth int     `yaml:"max_width"`
+}
+
 // GPUMemory contains GPU memory information.
 type GPUMemory struct {
 	Total     int    `yaml:"total"` // MB
@@ -174,6 +266,7 @@ type GPUPower struct {
 
 // GPUDriver contains GPU driver information.
 type GPUDriver struct {
+	Name          string `yaml:"name"` // kernel driver, e.g. nvidia or amdgpu
 	Version       string `yaml:"version"`
 	CUDAVersion   string `yaml:"cuda_version"`
 	OpenGLVersion string `yaml:"opengl_version"`
@@ -190,6 +283,7 @@ type NetworkInfo struct {
 	Speed  int    `yaml:"speed"` // Mbps
 	Duplex string `yaml:"duplex"`
 	Status string `yaml:"status"`
+	MTU    int    `yaml:"mtu"`
 	IPv4   string `yaml:"ipv4,omitempty"`
 	IPv6   string `yaml:"ipv6,omitempty"`
 	SSID   string `yaml:"ssid,omitempty"`
@@ -198,10 +292,11 @@ type NetworkInfo struct {
 
 // OctaneRating contains octane rating information.
 type OctaneRating struct {
-	RON         float64 `yaml:"ron"`
-	Grade       string  `yaml:"grade"`
-	Description string  `yaml:"description"`
-	Color       string  `yaml:"color"`
+	RON         float64  `yaml:"ron"`
+	Grade       string   `yaml:"grade"`
+	Description string   `yaml:"description"`
+	Color       string   `yaml:"color"`
+	Warnings    []string `yaml:"warnings,omitempty"`
 }
 
 // ProfessionalScenarios contains professional scenario scores.
@@ -2,11 +2,17 @@ package types
 
 // TestResults 定义测试结果的结构
 type TestResults struct {
-	CPU     CPUResults     `json:"cpu"`
-	Memory  MemoryResults  `json:"memory"`
-	Storage StorageResults `json:"storage"`
-	GPU     GPUResults     `json:"gpu"`
-	Network NetworkResults `json:"network"`
+	CPU     CPUResults      `json:"cpu"`
+	Memory  MemoryResults   `json:"memory"`
+	Storage StorageResults  `json:"storage"`
+	GPU     GPUResults      `json:"gpu"`
+	Network NetworkResults  `json:"network"`
+	Plugins []PluginResults `json:"plugins,omitempty"`
+
+	Steal map[string]StealSample `json:"steal,omitempty"` // 按基准测试名称记录的 CPU steal 采样
+	Power map[string]PowerSample `json:"power,omitempty"` // 按基准测试名称记录的 RAPL 能耗
+
+	GPUTelemetry map[string]GPUSample `json:"gpu_telemetry,omitempty"` // 按阶段名称记录的 GPU 遥测
 }
 
 // CPUResults 定义CPU测试结果的结构
@@ -62,6 +68,15 @@ type CPUResults struct {
 			} `json:"compression"`
 		} `json:"multi_core"`
 	} `json:"tests"`
+
+	// Efficiency 由测试期间的 RAPL package 平均功率得出
+	Efficiency struct {
+		Available               bool    `json:"available"`
+		Reason                  string  `json:"reason,omitempty"`                      // 不可用的原因
+		SingleCorePointsPerWatt float64 `json:"single_core_points_per_watt,omitempty"` // points/W
+		MultiCorePointsPerWatt  float64 `json:"multi_core_points_per_watt,omitempty"`  // points/W
+		MultiCoreWatts          float64 `json:"multi_core_watts,omitempty"`            // W，多核测试期间的 package 平均功率
+	} `json:"efficiency"`
 }
 
 // MemoryResults 定义内存测试结果的结构
@@ -225,7 +240,13 @@ type NetworkResults struct {
 		DNSResolution        map[string]float64 `json:"dns_resolution"` // ms
 		ServiceAccessibility map[string]bool    `json:"service_accessibility"`
 		PortScan             map[string]string  `json:"port_scan"`
+
+		DNS      []DNSResult     `json:"dns,omitempty"`      // 按名称和解析器的查询统计
+		Services []ServiceResult `json:"services,omitempty"` // 各服务的连接耗时
 	} `json:"connectivity"`
+
+	Errors   map[string]string `json:"errors,omitempty"`   // 按目标名记录测试失败的原因
+	Warnings []string          `json:"warnings,omitempty"` // 低于期望带宽等提示
 }
 
 // BandwidthResult 定义带宽测试结果的结构
@@ -235,4 +256,104 @@ type BandwidthResult struct {
 	Latency    float64 `json:"latency"`     // ms
 	Jitter     float64 `json:"jitter"`      // ms
 	PacketLoss float64 `json:"packet_loss"` // %
+
+	Retransmits int     `json:"retransmits,omitempty"` // TCP 重传次数（iperf3）
+	LocalCPU    float64 `json:"local_cpu,omitempty"`   // %，测试期间本机 CPU 占用（iperf3）
+	RemoteCPU   float64 `json:"remote_cpu,omitempty"`  // %，测试期间对端 CPU 占用（iperf3）
+
+	TTFB            float64 `json:"ttfb,omitempty"`             // ms，首字节时间中位数（HTTP 下载）
+	Requests        int     `json:"requests,omitempty"`         // 完成的请求数（HTTP 下载）
+	ConnectionReuse float64 `json:"connection_reuse,omitempty"` // %，复用已有连接的请求比例（HTTP 下载）
+}
+
+// DNSResult 定义一个名称在一个解析器上的查询统计
+type DNSResult struct {
+	Name        string  `json:"name"`
+	Resolver    string  `json:"resolver"`
+	Queries     int     `json:"queries"`
+	Failures    int     `json:"failures"`
+	FailureRate float64 `json:"failure_rate"`    // %
+	Median      float64 `json:"median"`          // ms，成功查询的中位数
+	P95         float64 `json:"p95"`             // ms
+	Cold        float64 `json:"cold"`            // ms，第一次查询，解析器可能尚未缓存
+	Cached      float64 `json:"cached"`          // ms，之后查询的中位数
+	Error       string  `json:"error,omitempty"` // 最后一次失败的原因
+}
+
+// ServiceResult 定义一个服务的可达性和各阶段耗时
+type ServiceResult struct {
+	Name      string  `json:"name"`
+	Target    string  `json:"target"` // URL 或 host:port
+	Reachable bool    `json:"reachable"`
+	Status    int     `json:"status,omitempty"` // HTTP 状态码
+	Connect   float64 `json:"connect"`          // ms，TCP 连接
+	TLS       float64 `json:"tls,omitempty"`    // ms，TLS 握手
+	TTFB      float64 `json:"ttfb,omitempty"`   // ms，发出请求到收到首字节（HTTP）
+	Total     float64 `json:"total"`            // ms
+	Error     string  `json:"error,omitempty"`
+}
+
+// StealSample 定义一个基准测试期间的 CPU steal 和 iowait 占比
+type StealSample struct {
+	Steal     float64 `json:"steal"`      // %，整个测试期间的平均值
+	IOWait    float64 `json:"iowait"`     // %
+	PeakSteal float64 `json:"peak_steal"` // %，单个采样区间的最大值
+}
+
+// PowerSample 定义一个基准测试期间由 RAPL 计数器测得的能耗
+type PowerSample struct {
+	Available bool          `json:"available"`
+	Reason    string        `json:"reason,omitempty"` // 不可用的原因
+	Seconds   float64       `json:"seconds"`
+	Package   EnergyReading `json:"package"`
+	Core      EnergyReading `json:"core"`
+	DRAM      EnergyReading `json:"dram"`
+}
+
+// EnergyReading 定义一个能耗域的总能耗和平均功率
+type EnergyReading struct {
+	Joules float64 `json:"joules"`
+	Watts  float64 `json:"watts"`
+}
+
+// GPUSample 定义一个测试阶段期间由 nvidia-smi 或 rocm-smi 周期采样的 GPU 遥测。
+// 多块显卡时温度取最高的一块，功耗取总和。
+type GPUSample struct {
+	Available bool   `json:"available"`
+	Reason    string `json:"reason,omitempty"` // 不可用的原因
+	Tool      string `json:"tool,omitempty"`   // nvidia-smi 或 rocm-smi
+	Samples   int    `json:"samples"`
+
+	Temperature struct {
+		Idle    float64 `json:"idle"`    // °C，阶段开始时
+		Average float64 `json:"average"` // °C
+		Max     float64 `json:"max"`     // °C
+	} `json:"temperature"`
+
+	Power struct {
+		Idle    float64 `json:"idle"`    // Watts，阶段开始时
+		Average float64 `json:"average"` // Watts
+		Peak    float64 `json:"peak"`    // Watts
+	} `json:"power"`
+
+	Utilization float64 `json:"utilization"` // %，所有显卡的平均值
+}
+
+// PluginResults 定义外部插件的测试结果
+type PluginResults struct {
+	Name     string         `json:"name"`
+	Category string         `json:"category"`
+	Duration string         `json:"duration"`
+	Weight   float64        `json:"weight"` // 在总评分中的占比，0 表示不计分
+	Error    string         `json:"error,omitempty"`
+	Metrics  []PluginMetric `json:"metrics"`
+}
+
+// PluginMetric 定义插件上报的单项指标
+type PluginMetric struct {
+	Name           string  `json:"name"`
+	Value          float64 `json:"value"`
+	Unit           string  `json:"unit"`
+	HigherIsBetter bool    `json:"higher_is_better"`
+	Baseline       float64 `json:"baseline,omitempty"` // 评分基准值
 }
@@ -2,6 +2,8 @@ import pynvml
 import time
 import torch
 
+from octane_output import emit, log, metric
+
 def initialize_gpu():
     pynvml.nvmlInit()
     device_count = pynvml.nvmlDeviceGetCount()
@@ -33,16 +35,26 @@ def run_tensor_operations(device_index):
 
 def main():
     device_count = initialize_gpu()
-    print(f"Detected {device_count} GPU(s).")
-    
+    log(f"Detected {device_count} GPU(s).")
+
+    devices = []
+    durations = []
     for i in range(device_count):
         gpu_info = get_gpu_info(i)
-        print(f"GPU {i}: {gpu_info}")
-        
+        log(f"GPU {i}: {gpu_info}")
+
         duration = run_tensor_operations(i)
-        print(f"Tensor operations on GPU {i} took {duration:.4f} seconds.")
+        log(f"Tensor operations on GPU {i} took {duration:.4f} seconds.")
+        gpu_info['tensor_seconds'] = duration
+        devices.append(gpu_info)
+        durations.append(duration)
 
     pynvml.nvmlShutdown()
 
+    metrics = [metric('devices', device_count, 'count')]
+    if durations:
+        metrics.append(metric('tensor_time', sum(durations) / len(durations) * 1000, 'ms', higher_is_better=False))
+    emit('gpu_test', metrics, {'devices': devices})
+
 if __name__ == "__main__":
     main()
\ No newline at end of file
@@ -3,13 +3,15 @@ import json
 import time
 import psutil
 
+from octane_output import emit, log, metric
+
 def run_speedtest():
     """Run a speed test and return the results."""
     try:
         result = subprocess.run(['speedtest', '--json'], capture_output=True, text=True, check=True)
         return json.loads(result.stdout)
     except Exception as e:
-        print(f"Error running speed test: {e}")
+        log(f"Error running speed test: {e}")
         return None
 
 def get_network_info():
@@ -27,17 +29,26 @@ def get_network_info():
 
 def main():
     """Main function to run network tests."""
-    print("Collecting network information...")
+    log("Collecting network information...")
     network_info = get_network_info()
-    print("Network Information:", network_info)
+    log(f"Network Information: {network_info}")
 
-    print("Running speed test...")
+    log("Running speed test...")
     speedtest_results = run_speedtest()
-    
+
+    metrics = []
     if speedtest_results:
-        print("Speed Test Results:", speedtest_results)
+        log(f"Speed Test Results: {speedtest_results}")
+        # speedtest-cli reports bits per second
+        metrics = [
+            metric('download', speedtest_results.get('download', 0) / 1e6, 'Mbps'),
+            metric('upload', speedtest_results.get('upload', 0) / 1e6, 'Mbps'),
+            metric('latency', speedtest_results.get('ping', 0), 'ms', higher_is_better=False),
+        ]
     else:
-        print("Failed to retrieve speed test results.")
+        log("Failed to retrieve speed test results.")
+
+    emit('network_test', metrics, {'interfaces': network_info, 'speedtest': speedtest_results})
 
 if __name__ == "__main__":
     main()
\ No newline at end of file
@@ -0,0 +1,38 @@
+"""Result envelope shared by the octane test scripts.
+
+Scripts print exactly one JSON envelope on stdout; progress and diagnostics
+go to stderr, which octane forwards to its log.
+"""
+import json
+import sys
+
+ENVELOPE_VERSION = 1
+
+
+def log(message):
+    """Write a progress message to stderr."""
+    print(message, file=sys.stderr, flush=True)
+
+
+def metric(name, value, unit, higher_is_better=True):
+    """Build a single metric entry."""
+    return {
+        'name': name,
+        'value': float(value),
+        'unit': unit,
+        'higher_is_better': higher_is_better,
+    }
+
+
+def emit(script, metrics, data=None):
+    """Print the result envelope on stdout."""
+    envelope = {
+        'version': ENVELOPE_VERSION,
+        'script': script,
+        'metrics': metrics,
+    }
+    if data is not None:
+        envelope['data'] = data
+    json.dump(envelope, sys.stdout, default=str)
+    sys.stdout.write('\n')
+    sys.stdout.flush()